@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// activation is one observed unit activation: the time systemd began
+// starting the service (for socket-activated units, the moment traffic
+// triggered it) until it reported active.
+type activation struct {
+	start    time.Time
+	duration time.Duration
+}
+
+var (
+	startingRe = regexp.MustCompile(`systemd\[\d+\]: (?:Starting|Triggering) `)
+	startedRe  = regexp.MustCompile(`systemd\[\d+\]: (?:Started|Finished) `)
+)
+
+// parseActivations extracts start→active latencies from short-iso
+// journal lines of a unit (and its socket). Pairs are matched in
+// order; an unfinished Starting with no Started is dropped.
+func parseActivations(lines []string) []activation {
+	var acts []activation
+	var pendingStart time.Time
+	var havePending bool
+	for _, line := range lines {
+		ts, ok := parseJournalTime(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case startingRe.MatchString(line):
+			pendingStart, havePending = ts, true
+		case startedRe.MatchString(line):
+			if havePending && !ts.Before(pendingStart) {
+				acts = append(acts, activation{start: pendingStart, duration: ts.Sub(pendingStart)})
+				havePending = false
+			}
+		}
+	}
+	return acts
+}
+
+// parseJournalTime reads the leading short-iso timestamp of a journal
+// line, e.g. "2024-08-29T10:00:01+0000".
+func parseJournalTime(line string) (time.Time, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05-0700", "2006-01-02T15:04:05-07:00"} {
+		if ts, err := time.Parse(layout, fields[0]); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// buildActivationReport renders activation latencies as display lines
+// for the viewer, newest last.
+func buildActivationReport(unitName string, acts []activation) []string {
+	if len(acts) == 0 {
+		return []string{"no completed activations found in the journal for " + unitName}
+	}
+	var out []string
+	var total, worst time.Duration
+	for _, a := range acts {
+		if a.duration > worst {
+			worst = a.duration
+		}
+		total += a.duration
+		out = append(out, fmt.Sprintf("%s  started in %s",
+			a.start.Format("2006-01-02 15:04:05"), a.duration.Round(time.Millisecond)))
+	}
+	avg := total / time.Duration(len(acts))
+	out = append(out, "",
+		fmt.Sprintf("%d activations: avg %s, worst %s",
+			len(acts), avg.Round(time.Millisecond), worst.Round(time.Millisecond)))
+	return out
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseActivations(t *testing.T) {
+	lines := []string{
+		"2024-08-29T10:00:00+0000 host systemd[1]: Starting On-demand API Service...",
+		"2024-08-29T10:00:02+0000 host systemd[1]: Started On-demand API Service.",
+		"2024-08-29T11:30:00+0000 host api[999]: some app output",
+		"2024-08-29T12:00:00+0000 host systemd[1]: Starting On-demand API Service...",
+		"2024-08-29T12:00:01+0000 host systemd[1]: Started On-demand API Service.",
+	}
+	acts := parseActivations(lines)
+	if len(acts) != 2 {
+		t.Fatalf("got %d activations, want 2", len(acts))
+	}
+	if acts[0].duration != 2*time.Second {
+		t.Errorf("first duration = %s, want 2s", acts[0].duration)
+	}
+	if acts[1].duration != time.Second {
+		t.Errorf("second duration = %s, want 1s", acts[1].duration)
+	}
+}
+
+func TestParseActivationsUnfinished(t *testing.T) {
+	lines := []string{
+		"2024-08-29T10:00:00+0000 host systemd[1]: Starting Slow Service...",
+		"2024-08-29T10:00:05+0000 host slow[1]: still warming up",
+	}
+	if acts := parseActivations(lines); len(acts) != 0 {
+		t.Errorf("got %d activations for unfinished start, want 0", len(acts))
+	}
+}
+
+func TestParseJournalTimeRejectsGarbage(t *testing.T) {
+	if _, ok := parseJournalTime("-- No entries --"); ok {
+		t.Error("parsed a timestamp from journalctl placeholder output")
+	}
+}
+
+func TestParseShowDeps(t *testing.T) {
+	out := "Wants=api.service worker.service\nRequires=db.service api.service\n"
+	got := parseShowDeps(out)
+	want := []string{"api.service", "worker.service", "db.service"}
+	if len(got) != len(want) {
+		t.Fatalf("parseShowDeps = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("member[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBuildActivationReport(t *testing.T) {
+	base := time.Date(2024, 8, 29, 10, 0, 0, 0, time.UTC)
+	acts := []activation{
+		{start: base, duration: time.Second},
+		{start: base.Add(time.Hour), duration: 3 * time.Second},
+	}
+	report := buildActivationReport("api.service", acts)
+	last := report[len(report)-1]
+	if !strings.Contains(last, "avg 2s") || !strings.Contains(last, "worst 3s") {
+		t.Errorf("summary = %q, want avg 2s / worst 3s", last)
+	}
+
+	empty := buildActivationReport("api.service", nil)
+	if len(empty) != 1 || !strings.Contains(empty[0], "no completed activations") {
+		t.Errorf("empty report = %v", empty)
+	}
+}
+
+func TestRenderMetrics(t *testing.T) {
+	units := []unit{
+		{Name: "a.service", Active: "active"},
+		{Name: "b.service", Active: "active"},
+		{Name: "c.service", Active: "failed"},
+	}
+	out := renderMetrics(units)
+	for _, want := range []string{
+		`centurion_units{state="active"} 2`,
+		`centurion_units{state="failed"} 1`,
+		`centurion_unit_failed{unit="c.service"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseServiceStats(t *testing.T) {
+	out := `Id=api.service
+NRestarts=4
+MemoryCurrent=1048576
+
+Id=cron.service
+NRestarts=0
+MemoryCurrent=[not set]
+`
+	stats := parseServiceStats(out)
+	if got := stats["api.service"]; got.Restarts != 4 || got.MemBytes != 1048576 {
+		t.Errorf("api.service = %+v", got)
+	}
+	if got := stats["cron.service"]; got.Restarts != 0 || got.MemBytes != 0 {
+		t.Errorf("cron.service = %+v", got)
+	}
+}
+
+func TestRenderServiceMetrics(t *testing.T) {
+	out := renderServiceMetrics(map[string]unitStats{
+		"api.service":  {Restarts: 4, MemBytes: 1048576},
+		"cron.service": {Restarts: 0},
+	})
+	for _, want := range []string{
+		`centurion_unit_restarts{unit="api.service"} 4`,
+		`centurion_unit_restarts{unit="cron.service"} 0`,
+		`centurion_unit_memory_bytes{unit="api.service"} 1048576`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics missing %q:\n%s", want, out)
+		}
+	}
+	// No memory series when accounting is off.
+	if strings.Contains(out, `centurion_unit_memory_bytes{unit="cron.service"}`) {
+		t.Error("exported a memory series for a unit without accounting")
+	}
+}
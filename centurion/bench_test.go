@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// benchUnitList builds list-units output for n units, the shape a busy
+// host with thousands of units produces.
+func benchUnitList(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "unit-%04d.service loaded active running Worker number %d\n", i, i)
+	}
+	return b.String()
+}
+
+func BenchmarkParseUnitList(b *testing.B) {
+	out := benchUnitList(5000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if units := parseUnitList(out); len(units) != 5000 {
+			b.Fatalf("parsed %d units", len(units))
+		}
+	}
+}
+
+// applyFilter runs on every keystroke while filtering, over the whole
+// unit list.
+func BenchmarkApplyFilter(b *testing.B) {
+	a := &app{meta: loadNotes(""), filter: "1234"}
+	a.units = parseUnitList(benchUnitList(5000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.applyFilter()
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/sdbus"
+)
+
+const (
+	sdDest  = "org.freedesktop.systemd1"
+	sdPath  = "/org/freedesktop/systemd1"
+	sdIface = "org.freedesktop.systemd1.Manager"
+)
+
+// jobActionTimeout bounds how long an action waits for its job to
+// finish; systemd's own default job timeout is in the same range.
+const jobActionTimeout = 90 * time.Second
+
+// busManager drives the systemd manager over its bus API: structured
+// errors instead of parsed CLI output, and real job results instead of
+// an exit code.
+type busManager struct {
+	conn *sdbus.Conn
+}
+
+// newBusManager connects to the manager on the system bus (or the
+// user's session bus) and subscribes, so JobRemoved signals carry each
+// job's result back to us.
+func newBusManager(user bool) (*busManager, error) {
+	var conn *sdbus.Conn
+	var err error
+	if user {
+		conn, err = sdbus.User()
+	} else {
+		conn, err = sdbus.System()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Call(sdDest, sdPath, sdIface, "Subscribe"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	match := "type='signal',interface='" + sdIface + "',member='JobRemoved'"
+	if _, err := conn.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "AddMatch", match); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &busManager{conn: conn}, nil
+}
+
+// listUnits is ListUnits over the bus; rows come back typed, so there
+// is no column output to parse and no locale to get wrong.
+func (b *busManager) listUnits() ([]unit, error) {
+	body, err := b.conn.Call(sdDest, sdPath, sdIface, "ListUnits")
+	if err != nil {
+		return nil, fmt.Errorf("ListUnits: %w", err)
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("ListUnits: empty reply")
+	}
+	rows, ok := body[0].([]any)
+	if !ok {
+		return nil, fmt.Errorf("ListUnits: unexpected reply shape")
+	}
+	return unitsFromRows(rows), nil
+}
+
+// unitsFromRows maps ListUnits reply rows (ssssssouso: name,
+// description, load, active, sub, followed, object path, job id, job
+// type, job path) onto the unit struct.
+func unitsFromRows(rows []any) []unit {
+	var units []unit
+	for _, r := range rows {
+		f, ok := r.([]any)
+		if !ok || len(f) < 5 {
+			continue
+		}
+		str := func(i int) string { s, _ := f[i].(string); return s }
+		units = append(units, unit{
+			Name:        str(0),
+			Description: str(1),
+			Load:        str(2),
+			Active:      str(3),
+			Sub:         str(4),
+		})
+	}
+	return units
+}
+
+// jobError is a job that ran and finished with a result other than
+// "done" — a real failure, not bus trouble.
+type jobError struct {
+	verb, name, result string
+}
+
+func (e *jobError) Error() string {
+	return fmt.Sprintf("%s %s: job finished with result %q", e.verb, e.name, e.result)
+}
+
+// action starts, stops or restarts a unit and waits for the job to
+// finish, reporting its result. "replace" mirrors what systemctl does:
+// a conflicting queued job is replaced, not failed on.
+func (b *busManager) action(verb, name string) error {
+	method := map[string]string{
+		"start":   "StartUnit",
+		"stop":    "StopUnit",
+		"restart": "RestartUnit",
+	}[verb]
+	if method == "" {
+		return fmt.Errorf("unsupported action %q", verb)
+	}
+	body, err := b.conn.Call(sdDest, sdPath, sdIface, method, name, "replace")
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", verb, name, err)
+	}
+	if len(body) == 0 {
+		return fmt.Errorf("%s %s: no job path in reply", verb, name)
+	}
+	job, _ := body[0].(string)
+
+	// JobRemoved fires once per finished job: (id, job path, unit,
+	// result). The filter claims only our job's signal, so concurrent
+	// actions each see their own result.
+	sig, err := b.conn.WaitSignal("JobRemoved", func(m sdbus.Message) bool {
+		if len(m.Body) < 4 {
+			return false
+		}
+		path, _ := m.Body[1].(string)
+		return path == job
+	}, time.Now().Add(jobActionTimeout))
+	if err != nil {
+		return fmt.Errorf("%s %s: waiting for job: %w", verb, name, err)
+	}
+	result, _ := sig.Body[3].(string)
+	if result != "done" {
+		return &jobError{verb: verb, name: name, result: result}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitsFromRows(t *testing.T) {
+	rows := []any{
+		[]any{"nginx.service", "A web server", "loaded", "active", "running", "", "/path", uint32(0), "", "/"},
+		[]any{"tmp.mount", "Temporary Directory", "loaded", "inactive", "dead", "", "/path", uint32(0), "", "/"},
+		"not a row",
+	}
+	units := unitsFromRows(rows)
+	if len(units) != 2 {
+		t.Fatalf("got %d units", len(units))
+	}
+	want := unit{Name: "nginx.service", Load: "loaded", Active: "active", Sub: "running", Description: "A web server"}
+	if units[0] != want {
+		t.Errorf("unit = %+v, want %+v", units[0], want)
+	}
+	if units[1].Sub != "dead" {
+		t.Errorf("second unit = %+v", units[1])
+	}
+}
+
+func TestJobError(t *testing.T) {
+	err := &jobError{verb: "restart", name: "nginx.service", result: "failed"}
+	if !strings.Contains(err.Error(), `result "failed"`) {
+		t.Errorf("jobError text: %q", err)
+	}
+}
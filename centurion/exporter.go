@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/lifecycle"
+)
+
+// runExport implements `centurion export`: a non-interactive mode that
+// serves unit state as Prometheus text metrics, so a host's systemd
+// health can be scraped without running the TUI.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	listen := fs.String("listen", ":9822", "metrics listen address")
+	userMode := fs.Bool("user", false, "export the user service manager instead of the system one")
+	alertRules := fs.Bool("alert-rules", false, "print a Prometheus alert rules file matching the exported metrics and exit")
+	fs.Parse(args)
+
+	if *alertRules {
+		fmt.Print(promAlertRules)
+		return
+	}
+
+	sc := &systemctl{user: *userMode}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		units, err := sc.listUnits()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(renderMetrics(units)))
+		// Restart and memory series come from a second systemctl call;
+		// losing them should not break the state metrics above.
+		if stats, err := sc.serviceStats(serviceNames(units)); err == nil {
+			w.Write([]byte(renderServiceMetrics(stats)))
+		}
+	})
+	grp := lifecycle.New(5 * time.Second)
+	srv := &http.Server{Addr: *listen, Handler: mux}
+	grp.Go(func(context.Context) error {
+		log.Printf("serving metrics on %s/metrics", *listen)
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	})
+	grp.Defer("metrics server", srv.Shutdown)
+	if err := grp.Wait(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// renderMetrics produces Prometheus text exposition for a unit list:
+// per-state counts plus one series per failed unit so alerts can name
+// the culprit.
+func renderMetrics(units []unit) string {
+	counts := map[string]int{}
+	var failed []string
+	for _, u := range units {
+		counts[u.Active]++
+		if u.Active == "failed" || u.Sub == "failed" {
+			failed = append(failed, u.Name)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("# HELP centurion_units Number of systemd units by active state.\n")
+	b.WriteString("# TYPE centurion_units gauge\n")
+	states := make([]string, 0, len(counts))
+	for s := range counts {
+		states = append(states, s)
+	}
+	sort.Strings(states)
+	for _, s := range states {
+		fmt.Fprintf(&b, "centurion_units{state=%q} %d\n", s, counts[s])
+	}
+	b.WriteString("# HELP centurion_unit_failed Units currently in a failed state.\n")
+	b.WriteString("# TYPE centurion_unit_failed gauge\n")
+	sort.Strings(failed)
+	for _, name := range failed {
+		fmt.Fprintf(&b, "centurion_unit_failed{unit=%q} 1\n", name)
+	}
+	return b.String()
+}
+
+// serviceNames filters a unit list down to the .service units worth
+// polling for restart/memory stats.
+func serviceNames(units []unit) []string {
+	var names []string
+	for _, u := range units {
+		if strings.HasSuffix(u.Name, ".service") {
+			names = append(names, u.Name)
+		}
+	}
+	return names
+}
+
+// renderServiceMetrics adds per-service restart and memory series, the
+// inputs for the flapping and memory alerts.
+func renderServiceMetrics(stats map[string]unitStats) string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString("# HELP centurion_unit_restarts Service restart count (NRestarts).\n")
+	b.WriteString("# TYPE centurion_unit_restarts counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "centurion_unit_restarts{unit=%q} %d\n", name, stats[name].Restarts)
+	}
+	b.WriteString("# HELP centurion_unit_memory_bytes Service memory usage (MemoryCurrent).\n")
+	b.WriteString("# TYPE centurion_unit_memory_bytes gauge\n")
+	for _, name := range names {
+		if stats[name].MemBytes > 0 {
+			fmt.Fprintf(&b, "centurion_unit_memory_bytes{unit=%q} %d\n", name, stats[name].MemBytes)
+		}
+	}
+	return b.String()
+}
+
+// promAlertRules is a ready-to-use Prometheus rules file against the
+// metric names this exporter publishes. Thresholds are starting points;
+// tune them per fleet.
+const promAlertRules = `# Prometheus alert rules for the centurion exporter.
+# Generated by: centurion export -alert-rules
+groups:
+  - name: centurion
+    rules:
+      - alert: SystemdUnitFailed
+        expr: centurion_unit_failed == 1
+        for: 2m
+        labels:
+          severity: critical
+        annotations:
+          summary: "systemd unit {{ $labels.unit }} failed on {{ $labels.instance }}"
+
+      - alert: SystemdUnitFlapping
+        expr: increase(centurion_unit_restarts[15m]) > 3
+        labels:
+          severity: warning
+        annotations:
+          summary: "{{ $labels.unit }} restarted {{ $value }} times in 15m on {{ $labels.instance }}"
+
+      - alert: SystemdUnitMemoryHigh
+        expr: centurion_unit_memory_bytes > 2 * 1024 * 1024 * 1024
+        for: 10m
+        labels:
+          severity: warning
+        annotations:
+          summary: "{{ $labels.unit }} is using {{ $value | humanize1024 }}B on {{ $labels.instance }}"
+`
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// sessionCritical lists units whose stop can cut off the very session
+// the operator is working in: the display stack, networking and sshd.
+var sessionCritical = map[string]bool{
+	"display-manager.service":  true,
+	"gdm.service":              true,
+	"gdm3.service":             true,
+	"sddm.service":             true,
+	"lightdm.service":          true,
+	"NetworkManager.service":   true,
+	"systemd-networkd.service": true,
+	"wpa_supplicant.service":   true,
+	"ssh.service":              true,
+	"sshd.service":             true,
+	"dbus.service":             true,
+}
+
+func isSessionCritical(name string) bool { return sessionCritical[name] }
+
+// inhibitors returns the active inhibitor lock lines from
+// systemd-inhibit, without the legend and summary noise.
+func (s *systemctl) inhibitors() ([]string, error) {
+	out, err := exec.Command("systemd-inhibit", "--list", "--no-pager").Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemd-inhibit: %w", err)
+	}
+	return parseInhibitors(string(out)), nil
+}
+
+func parseInhibitors(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "No inhibitors." ||
+			strings.HasPrefix(trimmed, "WHO ") ||
+			strings.HasSuffix(trimmed, "listed.") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return lines
+}
+
+// holdsInhibitor reports whether an active inhibitor line mentions the
+// unit's base name. systemd-inhibit reports who/comm rather than the
+// owning unit, so a substring match is the best signal available.
+func holdsInhibitor(lines []string, unitName string) bool {
+	base := unitName
+	if i := strings.LastIndexByte(base, '.'); i > 0 {
+		base = base[:i]
+	}
+	for _, line := range lines {
+		if strings.Contains(line, base) {
+			return true
+		}
+	}
+	return false
+}
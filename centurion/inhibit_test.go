@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestParseInhibitors(t *testing.T) {
+	out := `WHO                          UID USER PID  COMM            WHAT     WHY                                       MODE
+NetworkManager               0   root 812  NetworkManager  sleep    NetworkManager needs to turn off networks delay
+PackageKit                   0   root 2104 packagekitd     shutdown Package updates are in progress           block
+
+2 inhibitors listed.
+`
+	lines := parseInhibitors(out)
+	if len(lines) != 2 {
+		t.Fatalf("parseInhibitors returned %d lines: %v", len(lines), lines)
+	}
+
+	if got := parseInhibitors("No inhibitors.\n0 inhibitors listed.\n"); len(got) != 0 {
+		t.Errorf("empty listing produced %v", got)
+	}
+
+	if !holdsInhibitor(lines, "NetworkManager.service") {
+		t.Error("NetworkManager inhibitor not matched to its unit")
+	}
+	if holdsInhibitor(lines, "nginx.service") {
+		t.Error("nginx matched an inhibitor it does not hold")
+	}
+}
+
+func TestIsSessionCritical(t *testing.T) {
+	if !isSessionCritical("sshd.service") || !isSessionCritical("display-manager.service") {
+		t.Error("session-critical units not flagged")
+	}
+	if isSessionCritical("nginx.service") {
+		t.Error("nginx flagged as session-critical")
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/sdinstall"
+)
+
+// runInstall implements `centurion install`: it packages the exporter
+// mode as a hardened systemd service, enables it, and verifies startup.
+func runInstall(args []string) {
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	listen := fs.String("listen", ":9822", "metrics listen address for the installed exporter")
+	unitDir := fs.String("unit-dir", "", "unit directory (default /etc/systemd/system)")
+	noEnable := fs.Bool("no-enable", false, "write the unit file but do not enable/start it")
+	fs.Parse(args)
+
+	bin, err := os.Executable()
+	if err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	unit := sdinstall.Unit{
+		Name:        "centurion-exporter.service",
+		Description: "centurion systemd metrics exporter",
+		ExecStart:   fmt.Sprintf("%s export -listen %s", bin, *listen),
+	}
+	path, err := sdinstall.Install(unit, *unitDir)
+	if err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	log.Printf("✓ wrote %s", path)
+	if *noEnable {
+		return
+	}
+	if err := sdinstall.Enable(unit.Name); err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	if err := sdinstall.VerifyActive(unit.Name, 30*time.Second); err != nil {
+		log.Fatalf("install: unit enabled but %v", err)
+	}
+	log.Printf("✓ %s is active", unit.Name)
+}
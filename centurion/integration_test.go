@@ -0,0 +1,89 @@
+//go:build integration
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// These tests run centurion's parsers against a real systemd inside a
+// privileged container, so output-format drift is caught by CI instead
+// of by users. Run with:
+//
+//	go test -tags integration ./centurion
+//
+// They are skipped when Docker is unavailable.
+
+const systemdImage = "jrei/systemd-ubuntu:22.04"
+
+func startSystemdContainer(t *testing.T) string {
+	t.Helper()
+	name := fmt.Sprintf("centurion-itest-%d", os.Getpid())
+	out, err := exec.Command("docker", "run", "-d", "--rm", "--privileged",
+		"--name", name, systemdImage).CombinedOutput()
+	if err != nil {
+		t.Skipf("cannot start systemd container: %v: %s", err, out)
+	}
+	t.Cleanup(func() { exec.Command("docker", "rm", "-f", name).Run() })
+
+	// Give systemd a moment to reach a steady state.
+	deadline := time.Now().Add(60 * time.Second)
+	for time.Now().Before(deadline) {
+		state, _ := exec.Command("docker", "exec", name, "systemctl", "is-system-running").Output()
+		s := strings.TrimSpace(string(state))
+		if s == "running" || s == "degraded" {
+			return name
+		}
+		time.Sleep(2 * time.Second)
+	}
+	t.Fatal("systemd container never became ready")
+	return ""
+}
+
+func containerExec(t *testing.T, name string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("docker", append([]string{"exec", name}, args...)...).Output()
+	if err != nil {
+		t.Fatalf("docker exec %v: %v", args, err)
+	}
+	return string(out)
+}
+
+func TestParseUnitListAgainstRealSystemd(t *testing.T) {
+	name := startSystemdContainer(t)
+
+	out := containerExec(t, name, "systemctl", "list-units",
+		"--all", "--plain", "--no-legend", "--no-pager", "--full")
+	units := parseUnitList(out)
+	if len(units) == 0 {
+		t.Fatal("parsed no units from real systemctl output")
+	}
+	found := false
+	for _, u := range units {
+		if u.Name == "systemd-journald.service" {
+			found = true
+			if u.Active != "active" {
+				t.Errorf("journald Active = %q, want active", u.Active)
+			}
+		}
+	}
+	if !found {
+		t.Error("systemd-journald.service missing from parsed units")
+	}
+}
+
+func TestParseShowDepsAgainstRealSystemd(t *testing.T) {
+	name := startSystemdContainer(t)
+
+	out := containerExec(t, name, "systemctl", "show",
+		"-p", "Wants", "-p", "Requires", "--no-pager", "multi-user.target")
+	members := parseShowDeps(out)
+	if len(members) == 0 {
+		t.Fatal("multi-user.target has no parsed members")
+	}
+}
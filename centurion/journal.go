@@ -0,0 +1,224 @@
+package main
+
+// journalView keeps a bounded window of a unit's journal in memory and
+// wraps lines lazily for the current viewport. Older history is fetched
+// on demand while scrolling instead of loading the whole journal up
+// front, so huge logs neither eat memory nor stall redraws on resize.
+type journalView struct {
+	// fetch returns up to count raw lines ending skip lines before the
+	// journal tail (skip 0 means the newest lines).
+	fetch func(count, skip int) ([]string, error)
+
+	maxLines int // bound on raw lines kept in memory
+	chunk    int // lines fetched per on-demand load
+
+	lines      []string // current window, oldest first
+	skipNewest int      // journal lines newer than the window's end
+	scroll     int      // wrapped rows scrolled up from the bottom
+	exhausted  bool     // no older lines exist before the window
+}
+
+const (
+	defaultJournalWindow = 2000
+	defaultJournalChunk  = 500
+)
+
+// openJournal loads the tail of a journal through fetch.
+func openJournal(fetch func(count, skip int) ([]string, error)) (*journalView, error) {
+	v := &journalView{fetch: fetch, maxLines: defaultJournalWindow, chunk: defaultJournalChunk}
+	lines, err := fetch(v.chunk, 0)
+	if err != nil {
+		return nil, err
+	}
+	v.lines = lines
+	v.exhausted = len(lines) < v.chunk
+	return v, nil
+}
+
+// visible renders the viewport: the last height wrapped rows above the
+// scroll offset, wrapping only as many raw lines as the viewport needs.
+func (v *journalView) visible(width, height int) []string {
+	if width < 1 || height < 1 {
+		return nil
+	}
+	need := v.scroll + height
+	var rows []string // collected bottom-up, reversed order
+	for i := len(v.lines) - 1; i >= 0 && len(rows) < need; i-- {
+		wrapped := wrapLine(v.lines[i], width)
+		for j := len(wrapped) - 1; j >= 0; j-- {
+			rows = append(rows, wrapped[j])
+		}
+	}
+	// Clamp scroll if the buffer has fewer rows than requested.
+	if len(rows) < need {
+		v.scroll = max(0, len(rows)-height)
+	}
+	// rows is collected bottom-up: rows[0] is the newest wrapped row.
+	top := min(v.scroll+height-1, len(rows)-1)
+	out := make([]string, 0, height)
+	for i := top; i >= v.scroll; i-- {
+		out = append(out, rows[i])
+	}
+	return out
+}
+
+// scrollUp moves the viewport up by n wrapped rows, pulling older
+// journal lines into the window when the top is reached. The scroll
+// offset is relative to the window's bottom, so evictions during a
+// slide are compensated to keep the viewport anchored.
+func (v *journalView) scrollUp(n, width, height int) {
+	v.scroll += n
+	for {
+		maxScroll := max(0, v.rowsAvailable(width)-height)
+		if v.scroll <= maxScroll {
+			return
+		}
+		if v.exhausted {
+			v.scroll = maxScroll
+			return
+		}
+		v.scroll -= v.loadOlder(width)
+		if v.scroll < 0 {
+			v.scroll = 0
+		}
+	}
+}
+
+// scrollDown moves the viewport toward the tail, sliding the window
+// back over newer lines that were evicted during a deep scroll.
+func (v *journalView) scrollDown(n, width int) {
+	v.scroll -= n
+	for v.scroll < 0 && v.skipNewest > 0 {
+		added := v.loadNewer(width)
+		if added == 0 {
+			break
+		}
+		v.scroll += added
+	}
+	if v.scroll < 0 {
+		v.scroll = 0
+	}
+}
+
+// appendLine adds one new journal line at the tail, for follow mode.
+// At the tail the viewport slides with it (auto-scroll); scrolled up,
+// the offset is re-anchored so the viewport doesn't jump.
+func (v *journalView) appendLine(line string, width int) {
+	if v.skipNewest > 0 {
+		// The window is slid back into history; the new line lands
+		// beyond its newer edge and comes back through loadNewer.
+		v.skipNewest++
+		return
+	}
+	v.lines = append(v.lines, line)
+	if v.scroll > 0 {
+		v.scroll += len(wrapLine(line, width))
+	}
+	if over := len(v.lines) - v.maxLines; over > 0 {
+		v.lines = v.lines[over:]
+		v.exhausted = false
+	}
+}
+
+// atTail reports whether the view is pinned to the newest lines.
+func (v *journalView) atTail() bool {
+	return v.scroll == 0 && v.skipNewest == 0
+}
+
+// rowsAvailable counts the wrapped rows the current window can produce.
+// Only called while deciding whether to fetch, so the O(window) walk is
+// fine.
+func (v *journalView) rowsAvailable(width int) int {
+	if width < 1 {
+		return 0
+	}
+	n := 0
+	for _, l := range v.lines {
+		n += len(wrapLine(l, width))
+	}
+	return n
+}
+
+// loadOlder prepends a chunk of older lines, evicting the newest end of
+// the window to stay within maxLines. It returns the number of wrapped
+// rows evicted below the viewport so the caller can re-anchor.
+func (v *journalView) loadOlder(width int) int {
+	older, err := v.fetch(v.chunk, v.skipNewest+len(v.lines))
+	if err != nil || len(older) == 0 {
+		v.exhausted = true
+		return 0
+	}
+	if len(older) < v.chunk {
+		v.exhausted = true
+	}
+	v.lines = append(older, v.lines...)
+	evictedRows := 0
+	if over := len(v.lines) - v.maxLines; over > 0 {
+		for _, l := range v.lines[len(v.lines)-over:] {
+			evictedRows += len(wrapLine(l, width))
+		}
+		v.lines = v.lines[:len(v.lines)-over]
+		v.skipNewest += over
+	}
+	return evictedRows
+}
+
+// loadNewer slides the window one chunk toward the journal tail after
+// lines were evicted by loadOlder. It returns the number of wrapped
+// rows it appended — scroll is measured in wrapped rows, so the caller
+// re-anchors with rows, not raw lines.
+func (v *journalView) loadNewer(width int) int {
+	if v.skipNewest == 0 {
+		return 0
+	}
+	count := min(v.chunk, v.skipNewest)
+	newer, err := v.fetch(count, v.skipNewest-count)
+	if err != nil || len(newer) == 0 {
+		return 0
+	}
+	v.skipNewest -= len(newer)
+	v.lines = append(v.lines, newer...)
+	addedRows := 0
+	for _, l := range newer {
+		addedRows += len(wrapLine(l, width))
+	}
+	if over := len(v.lines) - v.maxLines; over > 0 {
+		v.lines = v.lines[over:]
+		v.exhausted = false
+	}
+	return addedRows
+}
+
+// wrapLine hard-wraps a line to width columns. Journal lines have no
+// natural break points worth preserving, so plain rune chunking keeps
+// it cheap.
+func wrapLine(s string, width int) []string {
+	if s == "" {
+		return []string{""}
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return []string{s}
+	}
+	var out []string
+	for len(runes) > width {
+		out = append(out, string(runes[:width]))
+		runes = runes[width:]
+	}
+	out = append(out, string(runes))
+	return out
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeJournal simulates a journal of total numbered lines served through
+// the (count, skip) fetch contract.
+func fakeJournal(total int, calls *int) func(count, skip int) ([]string, error) {
+	return func(count, skip int) ([]string, error) {
+		if calls != nil {
+			*calls++
+		}
+		end := total - skip
+		if end < 0 {
+			end = 0
+		}
+		start := end - count
+		if start < 0 {
+			start = 0
+		}
+		lines := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			lines = append(lines, fmt.Sprintf("line-%d", i))
+		}
+		return lines, nil
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"short", 10, []string{"short"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"exact", 5, []string{"exact"}},
+	}
+	for _, tt := range tests {
+		if got := wrapLine(tt.in, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrapLine(%q, %d) = %v, want %v", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestJournalViewTail(t *testing.T) {
+	v, err := openJournal(fakeJournal(100, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := v.visible(80, 5)
+	want := []string{"line-95", "line-96", "line-97", "line-98", "line-99"}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("tail rows = %v, want %v", rows, want)
+	}
+	if !v.atTail() {
+		t.Error("expected atTail")
+	}
+}
+
+func TestJournalViewFetchesOlderOnScroll(t *testing.T) {
+	calls := 0
+	v, err := openJournal(fakeJournal(5000, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Initial load is one chunk, well below the full journal.
+	if len(v.lines) != defaultJournalChunk {
+		t.Fatalf("initial window = %d lines, want %d", len(v.lines), defaultJournalChunk)
+	}
+
+	v.scrollUp(defaultJournalChunk+10, 80, 10)
+	if calls < 2 {
+		t.Error("scrolling past the window should fetch older lines")
+	}
+	rows := v.visible(80, 1)
+	if want := "line-4489"; rows[0] != want {
+		t.Errorf("scrolled row = %q, want %q", rows[0], want)
+	}
+}
+
+func TestJournalViewBoundedMemory(t *testing.T) {
+	v, err := openJournal(fakeJournal(100000, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.scrollUp(10000, 80, 20)
+	if len(v.lines) > v.maxLines {
+		t.Errorf("window holds %d lines, bound is %d", len(v.lines), v.maxLines)
+	}
+	if v.skipNewest == 0 {
+		t.Error("deep scroll should have evicted newest lines from the window")
+	}
+
+	// Scrolling back down reloads the evicted tail.
+	v.scrollDown(1<<30, 80)
+	rows := v.visible(80, 1)
+	if want := "line-99999"; rows[0] != want {
+		t.Errorf("after return to tail, row = %q, want %q", rows[0], want)
+	}
+}
+
+func TestJournalViewFollow(t *testing.T) {
+	v, err := openJournal(fakeJournal(100, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At the tail, new lines slide straight into the viewport.
+	v.appendLine("live-0", 80)
+	rows := v.visible(80, 2)
+	if rows[1] != "live-0" {
+		t.Errorf("tail after append = %v", rows)
+	}
+	if !v.atTail() {
+		t.Error("append at tail should keep the view pinned")
+	}
+
+	// Scrolled up, the viewport stays anchored instead of jumping.
+	v.scrollUp(3, 80, 2)
+	anchored := v.visible(80, 2)
+	v.appendLine("live-1", 80)
+	if got := v.visible(80, 2); !reflect.DeepEqual(got, anchored) {
+		t.Errorf("viewport moved on append while scrolled: %v -> %v", anchored, got)
+	}
+	if v.atTail() {
+		t.Error("scrolled view should not report atTail")
+	}
+
+	// Returning to the tail shows everything appended meanwhile.
+	v.scrollDown(1<<30, 80)
+	rows = v.visible(80, 1)
+	if rows[0] != "live-1" {
+		t.Errorf("after return to tail, row = %v", rows)
+	}
+}
+
+func TestJournalViewFollowWhileSlidBack(t *testing.T) {
+	v, err := openJournal(fakeJournal(100000, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.scrollUp(10000, 80, 20)
+	if v.skipNewest == 0 {
+		t.Fatal("deep scroll should have slid the window back")
+	}
+	before := v.skipNewest
+	v.appendLine("live-0", 80)
+	if v.skipNewest != before+1 {
+		t.Errorf("skipNewest = %d, want %d", v.skipNewest, before+1)
+	}
+}
+
+func TestJournalViewScrollDownWrapped(t *testing.T) {
+	// Every line wraps to three rows at width 80; re-anchoring across a
+	// loadNewer slide must count wrapped rows, not raw lines, or the
+	// viewport jumps.
+	long := func(i int) string {
+		return fmt.Sprintf("%05d-%s", i, strings.Repeat("x", 194))
+	}
+	total := 100000
+	v, err := openJournal(func(count, skip int) ([]string, error) {
+		end := max(total-skip, 0)
+		start := max(end-count, 0)
+		lines := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			lines = append(lines, long(i))
+		}
+		return lines, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.scrollUp(10000, 80, 20)
+	if v.skipNewest == 0 {
+		t.Fatal("deep scroll should have slid the window back")
+	}
+	// Park at the bottom of the slid-back window, then step one row
+	// past it: the next row is the first chunk of the next line.
+	v.scrollDown(v.scroll, 80)
+	next := wrapLine(long(total-v.skipNewest), 80)[0]
+	v.scrollDown(1, 80)
+	if rows := v.visible(80, 1); rows[0] != next {
+		t.Errorf("after one-row scroll down, row = %.20q, want %.20q", rows[0], next)
+	}
+}
+
+func TestJournalViewScrollClamped(t *testing.T) {
+	v, err := openJournal(fakeJournal(3, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.scrollUp(50, 80, 2)
+	rows := v.visible(80, 2)
+	want := []string{"line-0", "line-1"}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("clamped rows = %v, want %v", rows, want)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/iamdanielv/utils/internal/errcode"
+	"golang.org/x/term"
+)
+
+func main() {
+	log.SetPrefix("centurion: ")
+	log.SetFlags(0)
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "install":
+			runInstall(os.Args[2:])
+			return
+		}
+	}
+
+	userMode := flag.Bool("user", false, "manage the user service manager instead of the system one")
+	flag.Parse()
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintln(os.Stderr, "centurion: needs an interactive terminal")
+		os.Exit(1)
+	}
+
+	sc := &systemctl{user: *userMode}
+	app, err := newApp(sc)
+	if err != nil {
+		// Failing to list units at startup means systemd itself is not
+		// talking to us.
+		errcode.Fatal(errcode.Wrap(errcode.Unavailable, err))
+	}
+	if err := app.run(); err != nil {
+		errcode.Fatal(err)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// unitMeta is a local annotation for one unit: free-form note text and
+// tags for grouping ("billing", "legacy"). Purely client-side, stored
+// in the user's config dir — nothing touches systemd.
+type unitMeta struct {
+	Tags []string `json:"tags,omitempty"`
+	Note string   `json:"note,omitempty"`
+}
+
+// notesStore persists unit annotations as JSON.
+type notesStore struct {
+	path string
+	m    map[string]unitMeta
+}
+
+// notesPath returns the store location, honoring the config dir and
+// user/system mode so annotations don't mix between managers.
+func notesPath(user bool) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	name := "notes.json"
+	if user {
+		name = "notes-user.json"
+	}
+	return filepath.Join(dir, "centurion", name), nil
+}
+
+// loadNotes reads the store; a missing file is an empty store.
+func loadNotes(path string) *notesStore {
+	s := &notesStore{path: path, m: map[string]unitMeta{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	json.Unmarshal(data, &s.m)
+	return s
+}
+
+func (s *notesStore) get(unit string) unitMeta { return s.m[unit] }
+
+// set parses annotation input — "#tag" words become tags, the rest is
+// the note — and persists it. Empty input clears the annotation.
+func (s *notesStore) set(unit, input string) error {
+	meta := parseMeta(input)
+	if len(meta.Tags) == 0 && meta.Note == "" {
+		delete(s.m, unit)
+	} else {
+		s.m[unit] = meta
+	}
+	return s.save()
+}
+
+func (s *notesStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// parseMeta splits "#billing #legacy flaky after reboots" into tags and
+// note text.
+func parseMeta(input string) unitMeta {
+	var meta unitMeta
+	var noteWords []string
+	for _, word := range strings.Fields(input) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok && tag != "" {
+			meta.Tags = append(meta.Tags, strings.ToLower(tag))
+		} else {
+			noteWords = append(noteWords, word)
+		}
+	}
+	sort.Strings(meta.Tags)
+	meta.Note = strings.Join(noteWords, " ")
+	return meta
+}
+
+// editText reconstructs the input form of an annotation, for prefilled
+// editing.
+func (m unitMeta) editText() string {
+	var parts []string
+	for _, tag := range m.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if m.Note != "" {
+		parts = append(parts, m.Note)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (m unitMeta) hasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if strings.HasPrefix(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// tagColors cycles chips through the palette; the same tag always gets
+// the same color.
+var tagColors = []string{cBlue, cGreen, cYellow, cRed}
+
+func tagColor(tag string) string {
+	var sum int
+	for _, r := range tag {
+		sum += int(r)
+	}
+	return tagColors[sum%len(tagColors)]
+}
+
+// chips renders the tags as colored fragments for the list row, and
+// reports the printable width consumed.
+func (m unitMeta) chips() (string, int) {
+	var b strings.Builder
+	width := 0
+	for _, tag := range m.Tags {
+		b.WriteString(tagColor(tag) + "[" + tag + "]" + cReset)
+		width += len(tag) + 2
+	}
+	return b.String(), width
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseMeta(t *testing.T) {
+	meta := parseMeta("#billing #Legacy flaky after reboots")
+	if len(meta.Tags) != 2 || meta.Tags[0] != "billing" || meta.Tags[1] != "legacy" {
+		t.Errorf("tags = %v", meta.Tags)
+	}
+	if meta.Note != "flaky after reboots" {
+		t.Errorf("note = %q", meta.Note)
+	}
+
+	if got := meta.editText(); got != "#billing #legacy flaky after reboots" {
+		t.Errorf("editText = %q", got)
+	}
+
+	if !meta.hasTag("bill") || !meta.hasTag("legacy") || meta.hasTag("web") {
+		t.Error("hasTag prefix matching broken")
+	}
+
+	empty := parseMeta("   ")
+	if len(empty.Tags) != 0 || empty.Note != "" {
+		t.Errorf("empty input parsed to %+v", empty)
+	}
+}
+
+func TestNotesStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "centurion", "notes.json")
+	s := loadNotes(path)
+
+	if err := s.set("web.service", "#billing handle with care"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	reloaded := loadNotes(path)
+	meta := reloaded.get("web.service")
+	if !meta.hasTag("billing") || meta.Note != "handle with care" {
+		t.Errorf("reloaded = %+v", meta)
+	}
+
+	// Clearing removes the entry entirely.
+	if err := reloaded.set("web.service", ""); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if got := loadNotes(path).get("web.service"); len(got.Tags) != 0 || got.Note != "" {
+		t.Errorf("after clear = %+v", got)
+	}
+}
+
+func TestTagColorStable(t *testing.T) {
+	if tagColor("billing") != tagColor("billing") {
+		t.Error("same tag changed color")
+	}
+}
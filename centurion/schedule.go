@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheduled actions are transient timer units created with systemd-run,
+// so they survive centurion exiting and show up in any systemd tooling.
+// The unit name prefix identifies ours for the pending view.
+const scheduledPrefix = "centurion-job-"
+
+// parseScheduleTime turns operator input into a point in time: either a
+// duration ("30m", "2h") or a wall clock "HH:MM" meaning the next time
+// that clock comes around.
+func parseScheduleTime(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if d, err := time.ParseDuration(input); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("%q is not in the future", input)
+		}
+		return now.Add(d), nil
+	}
+	clock, err := time.Parse("15:04", input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("want HH:MM or a duration like 30m, got %q", input)
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, nil
+}
+
+// scheduledUnitName builds a unique transient timer name; the verb and
+// target live in the description, this only has to be valid and ours.
+func scheduledUnitName(target string, at time.Time) string {
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, strings.TrimSuffix(target, ".service"))
+	return fmt.Sprintf("%s%s-%d", scheduledPrefix, base, at.Unix())
+}
+
+// scheduleAction arranges for a one-shot systemctl verb against a unit
+// at the given time.
+func (s *systemctl) scheduleAction(verb, name string, at time.Time) error {
+	delay := time.Until(at)
+	if delay < time.Second {
+		delay = time.Second
+	}
+	args := []string{
+		"--on-active=" + strconv.Itoa(int(delay.Seconds())) + "s",
+		"--timer-property=AccuracySec=1s",
+		"--unit=" + scheduledUnitName(name, at),
+		"--description=" + fmt.Sprintf("centurion: %s %s at %s", verb, name, at.Format("15:04")),
+	}
+	if s.user {
+		args = append([]string{"--user"}, args...)
+	}
+	args = append(args, "systemctl")
+	if s.user {
+		args = append(args, "--user")
+	}
+	args = append(args, verb, name)
+	out, err := exec.Command("systemd-run", args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s", firstLine(msg))
+		}
+		return err
+	}
+	return nil
+}
+
+// cancelScheduled stops a pending transient timer, which releases it.
+func (s *systemctl) cancelScheduled(timer string) error {
+	return s.action("stop", timer)
+}
+
+// pendingScheduled filters the unit list down to our scheduled-action
+// timers.
+func pendingScheduled(units []unit) []unit {
+	var out []unit
+	for _, u := range units {
+		if strings.HasPrefix(u.Name, scheduledPrefix) && strings.HasSuffix(u.Name, ".timer") &&
+			u.Active != "inactive" {
+			out = append(out, u)
+		}
+	}
+	return out
+}
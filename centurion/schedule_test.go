@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	now := time.Date(2024, 1, 8, 14, 30, 0, 0, time.UTC)
+
+	at, err := parseScheduleTime("30m", now)
+	if err != nil || !at.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("30m = %v, %v", at, err)
+	}
+
+	// A clock later today stays today.
+	at, err = parseScheduleTime("18:00", now)
+	if err != nil || at.Day() != 8 || at.Hour() != 18 {
+		t.Errorf("18:00 = %v, %v", at, err)
+	}
+
+	// A clock already past rolls to tomorrow.
+	at, err = parseScheduleTime("02:00", now)
+	if err != nil || at.Day() != 9 || at.Hour() != 2 {
+		t.Errorf("02:00 = %v, %v", at, err)
+	}
+
+	for _, bad := range []string{"", "yesterday", "-30m", "25:99"} {
+		if _, err := parseScheduleTime(bad, now); err == nil {
+			t.Errorf("parseScheduleTime(%q) accepted", bad)
+		}
+	}
+}
+
+func TestScheduledUnitName(t *testing.T) {
+	at := time.Unix(1704722400, 0)
+	name := scheduledUnitName("foo@bar.service", at)
+	if !strings.HasPrefix(name, scheduledPrefix) {
+		t.Errorf("name %q missing prefix", name)
+	}
+	if strings.ContainsAny(name, "@. ") {
+		t.Errorf("name %q not sanitized", name)
+	}
+}
+
+func TestPendingScheduled(t *testing.T) {
+	units := []unit{
+		{Name: "ssh.service", Active: "active"},
+		{Name: scheduledPrefix + "web-1704722400.timer", Active: "active", Description: "centurion: restart web.service at 02:00"},
+		{Name: scheduledPrefix + "old-1704000000.timer", Active: "inactive"},
+		{Name: "apt-daily.timer", Active: "active"},
+	}
+	pending := pendingScheduled(units)
+	if len(pending) != 1 || !strings.Contains(pending[0].Name, "web") {
+		t.Errorf("pending = %v", pending)
+	}
+}
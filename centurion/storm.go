@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// Storm detection: a dependency cascade shows up as several units
+// failing within a short window. Instead of scattered red rows, the
+// list view gets a banner naming the earliest failure — the probable
+// root cause the others toppled after.
+const (
+	stormThreshold = 3
+	stormWindow    = time.Minute
+	// stormMaxAge keeps ancient failure clusters from raising the banner
+	// forever after a restart of centurion.
+	stormMaxAge = time.Hour
+)
+
+// storm is a detected failure cascade.
+type storm struct {
+	units []string // failed units in failure order
+	root  string   // earliest failure
+	span  time.Duration
+}
+
+// detectStorm looks for threshold-or-more failures inside one window.
+// failedAt maps unit name to when it entered the failed state; zero
+// times (unknown) are ignored.
+func detectStorm(failedAt map[string]time.Time, now time.Time) *storm {
+	type failure struct {
+		name string
+		at   time.Time
+	}
+	var failures []failure
+	for name, at := range failedAt {
+		if at.IsZero() || now.Sub(at) > stormMaxAge {
+			continue
+		}
+		failures = append(failures, failure{name, at})
+	}
+	if len(failures) < stormThreshold {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool { return failures[i].at.Before(failures[j].at) })
+
+	// Find the largest cluster fitting in the window.
+	best := 0
+	bestStart := -1
+	for i := range failures {
+		j := i
+		for j+1 < len(failures) && failures[j+1].at.Sub(failures[i].at) <= stormWindow {
+			j++
+		}
+		if n := j - i + 1; n > best {
+			best, bestStart = n, i
+		}
+	}
+	if best < stormThreshold {
+		return nil
+	}
+	cluster := failures[bestStart : bestStart+best]
+	s := &storm{
+		root: cluster[0].name,
+		span: cluster[len(cluster)-1].at.Sub(cluster[0].at),
+	}
+	for _, f := range cluster {
+		s.units = append(s.units, f.name)
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectStorm(t *testing.T) {
+	now := time.Date(2024, 1, 8, 9, 20, 0, 0, time.UTC)
+	at := func(secsAgo int) time.Time { return now.Add(-time.Duration(secsAgo) * time.Second) }
+
+	t.Run("cascade inside one window", func(t *testing.T) {
+		s := detectStorm(map[string]time.Time{
+			"db.service":    at(50),
+			"api.service":   at(40),
+			"web.service":   at(35),
+			"queue.service": at(32),
+		}, now)
+		if s == nil {
+			t.Fatal("no storm detected")
+		}
+		if s.root != "db.service" {
+			t.Errorf("root = %q, want the earliest failure", s.root)
+		}
+		if len(s.units) != 4 {
+			t.Errorf("units = %v", s.units)
+		}
+	})
+
+	t.Run("scattered failures are not a storm", func(t *testing.T) {
+		if s := detectStorm(map[string]time.Time{
+			"a.service": at(10),
+			"b.service": at(600),
+			"c.service": at(1800),
+		}, now); s != nil {
+			t.Errorf("storm = %+v", s)
+		}
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		if s := detectStorm(map[string]time.Time{
+			"a.service": at(10),
+			"b.service": at(12),
+		}, now); s != nil {
+			t.Errorf("storm = %+v", s)
+		}
+	})
+
+	t.Run("old clusters expire", func(t *testing.T) {
+		old := int(stormMaxAge/time.Second) + 100
+		if s := detectStorm(map[string]time.Time{
+			"a.service": at(old),
+			"b.service": at(old + 5),
+			"c.service": at(old + 10),
+		}, now); s != nil {
+			t.Errorf("ancient storm resurfaced: %+v", s)
+		}
+	})
+
+	t.Run("unknown times ignored", func(t *testing.T) {
+		if s := detectStorm(map[string]time.Time{
+			"a.service": at(10),
+			"b.service": {},
+			"c.service": {},
+		}, now); s != nil {
+			t.Errorf("storm = %+v", s)
+		}
+	})
+}
+
+func TestParseFailureTimes(t *testing.T) {
+	out := "Id=db.service\nInactiveEnterTimestamp=Mon 2024-01-08 09:14:02 UTC\n\n" +
+		"Id=api.service\nInactiveEnterTimestamp=Mon 2024-01-08 09:14:30 UTC\n\n" +
+		"Id=fresh.service\nInactiveEnterTimestamp=n/a\n"
+
+	times := parseFailureTimes(out)
+	if len(times) != 2 {
+		t.Fatalf("times = %v", times)
+	}
+	want := time.Date(2024, 1, 8, 9, 14, 2, 0, time.UTC)
+	if !times["db.service"].Equal(want) {
+		t.Errorf("db.service = %v, want %v", times["db.service"], want)
+	}
+	if _, ok := times["fresh.service"]; ok {
+		t.Error("n/a timestamp parsed")
+	}
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/sdbus"
+)
+
+// unit is one row from systemctl list-units.
+type unit struct {
+	Name        string
+	Load        string
+	Active      string
+	Sub         string
+	Description string
+}
+
+// systemctl drives the systemd manager: over its bus API where a bus
+// is reachable (typed unit lists, structured errors, real job
+// results), and by exec'ing the systemctl CLI otherwise, so centurion
+// still works over ssh and in containers without D-Bus. journalctl is
+// always exec'd — the journal has no bus API.
+type systemctl struct {
+	user bool
+
+	busOnce sync.Once
+	bus     *busManager // nil when no bus was reachable
+}
+
+// manager returns the bus connection, dialing it on first use. A host
+// without a bus probes once and sticks with exec.
+func (s *systemctl) manager() *busManager {
+	s.busOnce.Do(func() {
+		b, err := newBusManager(s.user)
+		if err == nil {
+			s.bus = b
+		}
+	})
+	return s.bus
+}
+
+func (s *systemctl) args(extra ...string) []string {
+	if s.user {
+		return append([]string{"--user"}, extra...)
+	}
+	return extra
+}
+
+// listUnits returns all loaded units.
+func (s *systemctl) listUnits() ([]unit, error) {
+	if b := s.manager(); b != nil {
+		if units, err := b.listUnits(); err == nil {
+			return units, nil
+		}
+	}
+	args := s.args("list-units", "--all", "--plain", "--no-legend", "--no-pager", "--full")
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl list-units: %w", err)
+	}
+	return parseUnitList(string(out)), nil
+}
+
+// parseUnitList parses --plain --no-legend output: five whitespace
+// separated columns with the description taking the remainder.
+func parseUnitList(out string) []unit {
+	var units []unit
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		u := unit{
+			Name:   fields[0],
+			Load:   fields[1],
+			Active: fields[2],
+			Sub:    fields[3],
+		}
+		if len(fields) > 4 {
+			u.Description = strings.Join(fields[4:], " ")
+		}
+		units = append(units, u)
+	}
+	return units
+}
+
+// systemState reports the overall manager state (running, degraded,
+// maintenance, ...) via `systemctl is-system-running`. The command
+// exits nonzero for anything but running, so only the output counts.
+func (s *systemctl) systemState() string {
+	args := s.args("is-system-running")
+	out, _ := exec.Command("systemctl", args...).Output()
+	if state := strings.TrimSpace(string(out)); state != "" {
+		return state
+	}
+	return "unknown"
+}
+
+// action runs a systemctl verb (start, stop, restart) against a unit.
+// These can take a while for slow services, so callers run it off the
+// UI goroutine.
+func (s *systemctl) action(verb, name string) error {
+	if b := s.manager(); b != nil {
+		err := b.action(verb, name)
+		var jerr *jobError
+		var berr *sdbus.Error
+		if err == nil || errors.As(err, &jerr) || errors.As(err, &berr) {
+			// The bus delivered a verdict — done, a failed job, or a
+			// refusal like NoSuchUnit. Only transport trouble falls
+			// through to exec.
+			return err
+		}
+	}
+	args := s.args(verb, name)
+	out, err := exec.Command("systemctl", args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s", firstLine(msg))
+		}
+		return err
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// targetMembers returns the units grouped under a target via Wants=
+// and Requires=, i.e. what would come up with it.
+func (s *systemctl) targetMembers(name string) ([]string, error) {
+	args := s.args("show", "-p", "Wants", "-p", "Requires", "--no-pager", name)
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl show %s: %w", name, err)
+	}
+	return parseShowDeps(string(out)), nil
+}
+
+// parseShowDeps parses `systemctl show -p Wants -p Requires` output
+// into a deduplicated unit list.
+func parseShowDeps(out string) []string {
+	seen := map[string]bool{}
+	var members []string
+	for _, line := range strings.Split(out, "\n") {
+		_, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		for _, name := range strings.Fields(value) {
+			if !seen[name] {
+				seen[name] = true
+				members = append(members, name)
+			}
+		}
+	}
+	return members
+}
+
+// failureTimes reports when each of the given units entered its current
+// inactive/failed state.
+func (s *systemctl) failureTimes(names []string) (map[string]time.Time, error) {
+	args := s.args(append([]string{"show", "-p", "Id", "-p", "InactiveEnterTimestamp", "--no-pager"}, names...)...)
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl show: %w", err)
+	}
+	return parseFailureTimes(string(out)), nil
+}
+
+// parseFailureTimes parses blank-line separated show output into a
+// unit -> timestamp map. Units with no recorded transition ("n/a" or
+// empty) are left out.
+func parseFailureTimes(out string) map[string]time.Time {
+	times := map[string]time.Time{}
+	var id string
+	var at time.Time
+	flush := func() {
+		if id != "" && !at.IsZero() {
+			times[id] = at
+		}
+		id, at = "", time.Time{}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Id":
+			id = value
+		case "InactiveEnterTimestamp":
+			// e.g. "Mon 2024-01-08 09:14:02 UTC"
+			if t, err := time.Parse("Mon 2006-01-02 15:04:05 MST", value); err == nil {
+				at = t
+			}
+		}
+	}
+	flush()
+	return times
+}
+
+// unitStats is the per-service resource view the exporter publishes.
+type unitStats struct {
+	Restarts int
+	MemBytes uint64
+}
+
+// serviceStats reports restart counts and memory usage for the given
+// service units in one systemctl call.
+func (s *systemctl) serviceStats(names []string) (map[string]unitStats, error) {
+	args := s.args(append([]string{"show", "-p", "Id", "-p", "NRestarts", "-p", "MemoryCurrent", "--no-pager"}, names...)...)
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("systemctl show: %w", err)
+	}
+	return parseServiceStats(string(out)), nil
+}
+
+// parseServiceStats parses blank-line separated show output into a
+// unit -> stats map.
+func parseServiceStats(out string) map[string]unitStats {
+	stats := map[string]unitStats{}
+	var id string
+	var cur unitStats
+	flush := func() {
+		if id != "" {
+			stats[id] = cur
+		}
+		id, cur = "", unitStats{}
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Id":
+			id = value
+		case "NRestarts":
+			if n, err := strconv.Atoi(value); err == nil {
+				cur.Restarts = n
+			}
+		case "MemoryCurrent":
+			// "[not set]" or a huge sentinel when memory accounting is
+			// off; either way there is nothing worth exporting.
+			if n, err := strconv.ParseUint(value, 10, 64); err == nil && n < 1<<62 {
+				cur.MemBytes = n
+			}
+		}
+	}
+	flush()
+	return stats
+}
+
+// journalLines fetches up to count lines of one or more units'
+// journals, skipping the newest skip lines — skip>0 pages back into
+// older history.
+func (s *systemctl) journalLines(count, skip int, names ...string) ([]string, error) {
+	args := []string{"-n", strconv.Itoa(count + skip), "--no-pager", "-o", "short-iso"}
+	for _, name := range names {
+		args = append(args, "-u", name)
+	}
+	if s.user {
+		args = append([]string{"--user"}, args...)
+	}
+	out, err := exec.Command("journalctl", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("journalctl: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	if skip > 0 && len(lines) > skip {
+		lines = lines[:len(lines)-skip]
+	}
+	if len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+	return lines, nil
+}
+
+// followJournal streams new entries of a unit's journal into the
+// returned channel via `journalctl -f` until stop is called; the
+// channel closes when the stream ends.
+func (s *systemctl) followJournal(name string) (<-chan string, func(), error) {
+	args := []string{"-f", "-n", "0", "--no-pager", "-o", "short-iso", "-u", name}
+	if s.user {
+		args = append([]string{"--user"}, args...)
+	}
+	cmd := exec.Command("journalctl", args...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("journalctl -f: %w", err)
+	}
+	ch := make(chan string, 64)
+	go func() {
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			ch <- sc.Text()
+		}
+		close(ch)
+	}()
+	stop := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+	return ch, stop, nil
+}
@@ -0,0 +1,795 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/format"
+	"golang.org/x/term"
+)
+
+// ANSI palette, matching the colors used by the shell utilities in
+// this repo.
+const (
+	cReset  = "\x1b[0m"
+	cBold   = "\x1b[1m"
+	cRed    = "\x1b[31m"
+	cGreen  = "\x1b[32m"
+	cYellow = "\x1b[33m"
+	cBlue   = "\x1b[34m"
+	cGray   = "\x1b[30;1m"
+	cRev    = "\x1b[7m"
+
+	altScreen  = "\x1b[?1049h"
+	mainScreen = "\x1b[?1049l"
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+	clearAll   = "\x1b[2J\x1b[H"
+)
+
+type viewMode int
+
+const (
+	modeList viewMode = iota
+	modeLog
+	modeFilter
+	modeConfirm
+	modeSchedule
+	modePending
+	modeNote
+)
+
+// app is the TUI state machine.
+type app struct {
+	sc       *systemctl
+	units    []unit
+	sysState string // from systemctl is-system-running
+
+	mode    viewMode
+	filter  string
+	visible []int // indexes into units matching the filter
+	sel     int   // selection within visible
+	offset  int   // first visible row of the list
+
+	journal     *journalView
+	journalUnit string
+	// follow carries live journal lines while follow mode is on;
+	// stopFollow kills the journalctl -f behind it. logUnit is the real
+	// unit behind the log view — empty for report views, which cannot
+	// be followed.
+	follow     <-chan string
+	stopFollow func()
+	logUnit    string
+
+	width, height int
+	status        string
+
+	// pending maps unit name -> verb for actions still running in the
+	// background; done receives their results.
+	pending map[string]string
+	done    chan actionResult
+	notes   []string // most recent completion results, newest last
+	spin    int      // spinner frame
+
+	// confirm state for group operations on a target's members and for
+	// disruptive actions on session-critical units; confirmWarn holds
+	// extra context lines (inhibitor locks) shown above the unit list.
+	confirmVerb  string
+	confirmUnits []string
+	confirmWarn  []string
+
+	storm *storm // non-nil while a failure cascade is detected
+
+	// schedule prompt and pending-actions view state.
+	scheduleUnit  string
+	scheduleInput string
+	pendSel       int
+
+	// local annotations and the note prompt state.
+	meta      *notesStore
+	noteUnit  string
+	noteInput string
+}
+
+// actionResult is the outcome of a detached unit action.
+type actionResult struct {
+	unit string
+	verb string
+	err  error
+}
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+func newApp(sc *systemctl) (*app, error) {
+	a := &app{
+		sc:      sc,
+		pending: make(map[string]string),
+		done:    make(chan actionResult, 8),
+	}
+	path, err := notesPath(sc.user)
+	if err != nil {
+		path = "" // annotations just won't persist
+	}
+	a.meta = loadNotes(path)
+	if err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *app) reload() error {
+	units, err := a.sc.listUnits()
+	if err != nil {
+		return err
+	}
+	a.units = units
+	a.sysState = a.sc.systemState()
+	a.applyFilter()
+	a.detectFailureStorm()
+	return nil
+}
+
+// detectFailureStorm checks the failed units' transition times for a
+// cascade worth a banner. Lookup errors just clear the banner: the red
+// rows are still there.
+func (a *app) detectFailureStorm() {
+	var failed []string
+	for _, u := range a.units {
+		if u.Active == "failed" || u.Sub == "failed" {
+			failed = append(failed, u.Name)
+		}
+	}
+	if len(failed) < stormThreshold {
+		a.storm = nil
+		return
+	}
+	times, err := a.sc.failureTimes(failed)
+	if err != nil {
+		a.storm = nil
+		return
+	}
+	a.storm = detectStorm(times, time.Now())
+}
+
+func (a *app) applyFilter() {
+	a.visible = a.visible[:0]
+	needle := strings.ToLower(a.filter)
+	tag, byTag := strings.CutPrefix(needle, "#")
+	state, byState := strings.CutPrefix(needle, "!")
+	for i, u := range a.units {
+		switch {
+		case byState:
+			if u.Active == state || u.Sub == state {
+				a.visible = append(a.visible, i)
+			}
+		case byTag:
+			if a.meta.get(u.Name).hasTag(tag) {
+				a.visible = append(a.visible, i)
+			}
+		case needle == "" || strings.Contains(strings.ToLower(u.Name), needle) ||
+			strings.Contains(strings.ToLower(u.Description), needle):
+			a.visible = append(a.visible, i)
+		}
+	}
+	if a.sel >= len(a.visible) {
+		a.sel = max(0, len(a.visible)-1)
+	}
+}
+
+func (a *app) selectedUnit() (unit, bool) {
+	if a.sel < 0 || a.sel >= len(a.visible) {
+		return unit{}, false
+	}
+	return a.units[a.visible[a.sel]], true
+}
+
+func (a *app) run() error {
+	fd := int(os.Stdin.Fd())
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("raw mode: %w", err)
+	}
+	defer term.Restore(fd, old)
+
+	out := os.Stdout
+	fmt.Fprint(out, altScreen+hideCursor)
+	defer fmt.Fprint(out, showCursor+mainScreen)
+
+	// Keys arrive on a channel so the main loop can also react to
+	// finished background actions and spinner ticks.
+	keys := make(chan []byte)
+	go func() {
+		for {
+			buf := make([]byte, 16)
+			n, err := os.Stdin.Read(buf)
+			if err != nil || n == 0 {
+				close(keys)
+				return
+			}
+			keys <- buf[:n]
+		}
+	}()
+
+	ticker := time.NewTicker(120 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		a.width, a.height, _ = term.GetSize(fd)
+		if a.width <= 0 {
+			a.width, a.height = 80, 24
+		}
+		a.draw(out)
+
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			if quit := a.handleKey(key); quit {
+				return nil
+			}
+		case res := <-a.done:
+			a.finishAction(res)
+		case line, ok := <-a.follow:
+			if !ok {
+				// journalctl -f died underneath us; drop out of follow.
+				a.stopFollowing()
+				continue
+			}
+			a.journal.appendLine(line, a.width)
+		case <-ticker.C:
+			if len(a.pending) > 0 {
+				a.spin++
+			}
+		}
+	}
+}
+
+// finishAction records a detached action's result and refreshes the
+// unit list so the new state shows up.
+func (a *app) finishAction(res actionResult) {
+	delete(a.pending, res.unit)
+	note := fmt.Sprintf("%s✓%s %s %s", cGreen, cReset, res.verb, res.unit)
+	if res.err != nil {
+		note = fmt.Sprintf("%s✗%s %s %s: %v", cRed, cReset, res.verb, res.unit, res.err)
+	}
+	a.notes = append(a.notes, note)
+	if len(a.notes) > 20 {
+		a.notes = a.notes[len(a.notes)-20:]
+	}
+	a.reload()
+}
+
+// handleKey processes one key sequence; it returns true to exit.
+func (a *app) handleKey(key []byte) bool {
+	k := string(key)
+	if a.mode == modeConfirm {
+		a.handleConfirmKey(key)
+		return false
+	}
+	if a.mode == modeSchedule {
+		a.handleScheduleKey(key)
+		return false
+	}
+	if a.mode == modeNote {
+		a.handleNoteKey(key)
+		return false
+	}
+	if a.mode == modePending {
+		a.handlePendingKey(key)
+		return false
+	}
+	if a.mode == modeFilter {
+		switch key[0] {
+		case 0x1b: // esc
+			a.filter, a.mode = "", modeList
+			a.applyFilter()
+		case '\r':
+			a.mode = modeList
+		case 0x7f, 0x08: // backspace
+			if a.filter != "" {
+				a.filter = a.filter[:len(a.filter)-1]
+				a.applyFilter()
+			}
+		default:
+			if key[0] >= ' ' {
+				a.filter += k
+				a.applyFilter()
+			}
+		}
+		return false
+	}
+
+	up, down := k == "k" || k == "\x1b[A", k == "j" || k == "\x1b[B"
+
+	if a.mode == modeLog {
+		page := a.logHeight()
+		switch {
+		case k == "q" || key[0] == 0x1b && len(key) == 1:
+			a.stopFollowing()
+			a.mode, a.journal = modeList, nil
+		case k == "f":
+			a.toggleFollow()
+		case up:
+			a.journal.scrollUp(1, a.width, page)
+		case down:
+			a.journal.scrollDown(1, a.width)
+		case k == "\x04" || k == " ": // ctrl-d, space
+			a.journal.scrollUp(page, a.width, page)
+		case k == "\x15": // ctrl-u
+			a.journal.scrollDown(page, a.width)
+		case k == "G":
+			a.journal.scrollDown(1<<30, a.width)
+		}
+		return false
+	}
+
+	switch {
+	case k == "q" || k == "\x03":
+		return true
+	case up:
+		if a.sel > 0 {
+			a.sel--
+		}
+	case down:
+		if a.sel < len(a.visible)-1 {
+			a.sel++
+		}
+	case k == "g":
+		a.sel = 0
+	case k == "G":
+		a.sel = max(0, len(a.visible)-1)
+	case k == "/":
+		a.mode = modeFilter
+	case k == "D":
+		// Jump to what is dragging the system state down.
+		a.filter = "!failed"
+		a.applyFilter()
+		a.sel, a.offset = 0, 0
+		a.status = fmt.Sprintf("%d failed units (esc in / clears the filter)", len(a.visible))
+	case k == "R":
+		if err := a.reload(); err != nil {
+			a.status = err.Error()
+		} else {
+			a.status = "reloaded unit list"
+		}
+	case k == "l" || k == "\r":
+		a.openLogs()
+	case k == "a":
+		a.openActivation()
+	case k == "s":
+		a.unitAction("start")
+	case k == "t":
+		a.unitAction("stop")
+	case k == "r":
+		a.unitAction("restart")
+	case k == "S":
+		if u, ok := a.selectedUnit(); ok {
+			a.scheduleUnit, a.scheduleInput, a.mode = u.Name, "", modeSchedule
+		}
+	case k == "P":
+		a.pendSel, a.mode = 0, modePending
+	case k == "n":
+		if u, ok := a.selectedUnit(); ok {
+			a.noteUnit, a.noteInput, a.mode = u.Name, a.meta.get(u.Name).editText(), modeNote
+		}
+	}
+	return false
+}
+
+// handleNoteKey edits the selected unit's annotation; #words are tags,
+// the rest is the note.
+func (a *app) handleNoteKey(key []byte) {
+	switch key[0] {
+	case 0x1b: // esc
+		a.mode = modeList
+	case '\r':
+		a.mode = modeList
+		if err := a.meta.set(a.noteUnit, a.noteInput); err != nil {
+			a.status = err.Error()
+			return
+		}
+		a.status = "annotated " + a.noteUnit
+		a.applyFilter()
+	case 0x7f, 0x08: // backspace
+		if a.noteInput != "" {
+			a.noteInput = a.noteInput[:len(a.noteInput)-1]
+		}
+	default:
+		if key[0] >= ' ' {
+			a.noteInput += string(key)
+		}
+	}
+}
+
+// handleScheduleKey reads the time for a scheduled restart.
+func (a *app) handleScheduleKey(key []byte) {
+	switch key[0] {
+	case 0x1b: // esc
+		a.mode = modeList
+	case '\r':
+		a.mode = modeList
+		at, err := parseScheduleTime(a.scheduleInput, time.Now())
+		if err != nil {
+			a.status = err.Error()
+			return
+		}
+		if err := a.sc.scheduleAction("restart", a.scheduleUnit, at); err != nil {
+			a.status = err.Error()
+			return
+		}
+		a.status = fmt.Sprintf("restart of %s scheduled for %s", a.scheduleUnit, at.Format("15:04"))
+		a.reload()
+	case 0x7f, 0x08: // backspace
+		if a.scheduleInput != "" {
+			a.scheduleInput = a.scheduleInput[:len(a.scheduleInput)-1]
+		}
+	default:
+		if key[0] >= ' ' {
+			a.scheduleInput += string(key)
+		}
+	}
+}
+
+// handlePendingKey drives the scheduled-actions view.
+func (a *app) handlePendingKey(key []byte) {
+	pending := pendingScheduled(a.units)
+	k := string(key)
+	switch {
+	case k == "q" || key[0] == 0x1b && len(key) == 1:
+		a.mode = modeList
+	case k == "k" || k == "\x1b[A":
+		if a.pendSel > 0 {
+			a.pendSel--
+		}
+	case k == "j" || k == "\x1b[B":
+		if a.pendSel < len(pending)-1 {
+			a.pendSel++
+		}
+	case k == "x" || k == "d":
+		if a.pendSel < len(pending) {
+			timer := pending[a.pendSel].Name
+			if err := a.sc.cancelScheduled(timer); err != nil {
+				a.status = err.Error()
+			} else {
+				a.status = "cancelled " + timer
+			}
+			a.reload()
+			if a.pendSel > 0 {
+				a.pendSel--
+			}
+		}
+	case k == "R":
+		a.reload()
+	}
+}
+
+// handleConfirmKey processes keys while a group operation preview is
+// shown.
+func (a *app) handleConfirmKey(key []byte) {
+	switch key[0] {
+	case 'y', 'Y':
+		for _, name := range a.confirmUnits {
+			a.startAction(a.confirmVerb, name)
+		}
+		a.status = fmt.Sprintf("%s %d units", a.confirmVerb, len(a.confirmUnits))
+		a.mode = modeList
+	case 0x1b, 'n', 'q':
+		a.status = "cancelled"
+		a.mode = modeList
+	}
+}
+
+// groupAction previews a verb against all wanted-by/required members
+// of the selected target; the action only runs after confirmation.
+func (a *app) groupAction(verb string, target unit) {
+	members, err := a.sc.targetMembers(target.Name)
+	if err != nil {
+		a.status = err.Error()
+		return
+	}
+	if len(members) == 0 {
+		a.status = target.Name + " has no member units"
+		return
+	}
+	a.confirmVerb, a.confirmUnits, a.confirmWarn, a.mode = verb, members, nil, modeConfirm
+}
+
+// confirmDisruptive previews a stop/restart that could cut off the
+// current session, listing the active inhibitor locks, and only runs
+// after an explicit confirmation.
+func (a *app) confirmDisruptive(verb string, u unit, inhibitors []string) {
+	warn := []string{fmt.Sprintf("⚠ %s of %s may end this session.", verb, u.Name)}
+	if len(inhibitors) > 0 {
+		warn = append(warn, "active inhibitor locks:")
+		warn = append(warn, inhibitors...)
+	}
+	a.confirmVerb, a.confirmUnits, a.confirmWarn, a.mode = verb, []string{u.Name}, warn, modeConfirm
+}
+
+// unitAction kicks off a systemctl verb in the background; the list
+// stays interactive and the row shows a spinner until the result comes
+// back on a.done.
+func (a *app) unitAction(verb string) {
+	u, ok := a.selectedUnit()
+	if !ok {
+		return
+	}
+	// Targets get stack-level semantics: operate on their members,
+	// with a preview first.
+	if strings.HasSuffix(u.Name, ".target") && (verb == "start" || verb == "stop") {
+		a.groupAction(verb, u)
+		return
+	}
+	// Disruptive verbs against session-critical units (or anything
+	// holding an inhibitor lock) get a preview with the inhibitors.
+	if verb == "stop" || verb == "restart" {
+		inhibitors, _ := a.sc.inhibitors()
+		if isSessionCritical(u.Name) || holdsInhibitor(inhibitors, u.Name) {
+			a.confirmDisruptive(verb, u, inhibitors)
+			return
+		}
+	}
+	a.startAction(verb, u.Name)
+}
+
+func (a *app) startAction(verb, name string) {
+	if _, busy := a.pending[name]; busy {
+		a.status = name + " already has an action running"
+		return
+	}
+	a.pending[name] = verb
+	a.status = fmt.Sprintf("%s %s…", verb, name)
+	go func() {
+		err := a.sc.action(verb, name)
+		a.done <- actionResult{unit: name, verb: verb, err: err}
+	}()
+}
+
+func (a *app) openLogs() {
+	u, ok := a.selectedUnit()
+	if !ok {
+		return
+	}
+	v, err := openJournal(func(count, skip int) ([]string, error) {
+		return a.sc.journalLines(count, skip, u.Name)
+	})
+	if err != nil {
+		a.status = err.Error()
+		return
+	}
+	a.journal, a.journalUnit, a.logUnit, a.mode = v, u.Name, u.Name, modeLog
+}
+
+// toggleFollow starts or stops streaming new journal entries into the
+// log view. Report views (activation latency) have nothing to stream.
+func (a *app) toggleFollow() {
+	if a.stopFollow != nil {
+		a.stopFollowing()
+		return
+	}
+	if a.logUnit == "" {
+		return
+	}
+	ch, stop, err := a.sc.followJournal(a.logUnit)
+	if err != nil {
+		a.status = err.Error()
+		return
+	}
+	a.follow, a.stopFollow = ch, stop
+}
+
+// stopFollowing tears the follow stream down; safe when not following.
+func (a *app) stopFollowing() {
+	if a.stopFollow != nil {
+		a.stopFollow()
+	}
+	a.follow, a.stopFollow = nil, nil
+}
+
+// openActivation shows start→active latencies for the selected unit,
+// useful for debugging slow cold starts of socket-activated services.
+// The socket/service counterpart's journal is included so the trigger
+// side is visible too.
+func (a *app) openActivation() {
+	u, ok := a.selectedUnit()
+	if !ok {
+		return
+	}
+	names := []string{u.Name}
+	switch {
+	case strings.HasSuffix(u.Name, ".service"):
+		names = append(names, strings.TrimSuffix(u.Name, ".service")+".socket")
+	case strings.HasSuffix(u.Name, ".socket"):
+		names = append(names, strings.TrimSuffix(u.Name, ".socket")+".service")
+	}
+	lines, err := a.sc.journalLines(defaultJournalWindow, 0, names...)
+	if err != nil {
+		a.status = err.Error()
+		return
+	}
+	report := buildActivationReport(u.Name, parseActivations(lines))
+	v, err := openJournal(staticFetch(report))
+	if err != nil {
+		a.status = err.Error()
+		return
+	}
+	a.journal, a.journalUnit, a.logUnit, a.mode = v, "activation "+u.Name, "", modeLog
+}
+
+// staticFetch serves a fixed set of lines through the journal fetch
+// contract, for report-style views.
+func staticFetch(lines []string) func(count, skip int) ([]string, error) {
+	return func(count, skip int) ([]string, error) {
+		end := max(0, len(lines)-skip)
+		start := max(0, end-count)
+		return lines[start:end], nil
+	}
+}
+
+// logHeight is the viewport height of the log view (minus header and
+// status rows).
+func (a *app) logHeight() int { return max(1, a.height-2) }
+
+func (a *app) draw(out *os.File) {
+	var b strings.Builder
+	b.WriteString(clearAll)
+	switch a.mode {
+	case modeLog:
+		a.drawLog(&b)
+	case modeConfirm:
+		a.drawConfirm(&b)
+	case modePending:
+		a.drawPending(&b)
+	default:
+		a.drawList(&b)
+	}
+	out.WriteString(b.String())
+}
+
+func (a *app) drawList(b *strings.Builder) {
+	title := fmt.Sprintf(" centurion — %d units — system %s", len(a.visible), a.sysState)
+	if a.filter != "" || a.mode == modeFilter {
+		title += fmt.Sprintf("  /%s", a.filter)
+	}
+	// The header bar doubles as a health light: blue while running,
+	// red when degraded, yellow for anything in between.
+	bar := cBlue
+	switch a.sysState {
+	case "running":
+	case "degraded":
+		bar = cRed
+	default:
+		bar = cYellow
+	}
+	fmt.Fprintf(b, "%s%s%-*s%s\r\n", cBold, bar, a.width, title, cReset)
+
+	stormRows := 0
+	if a.storm != nil {
+		stormRows = 1
+		span := a.storm.span
+		if span < time.Second {
+			span = time.Second
+		}
+		banner := fmt.Sprintf(" ⚠ %d units failed within %s — probable root: %s",
+			len(a.storm.units), format.Duration(span), a.storm.root)
+		fmt.Fprintf(b, "%s%-*s%s\r\n", cBold+cRed+cRev, a.width, format.Truncate(banner, a.width), cReset)
+	}
+
+	noteRows := min(len(a.notes), 2)
+	rows := max(1, a.height-2-noteRows-stormRows)
+	if a.sel < a.offset {
+		a.offset = a.sel
+	}
+	if a.sel >= a.offset+rows {
+		a.offset = a.sel - rows + 1
+	}
+	for i := a.offset; i < len(a.visible) && i < a.offset+rows; i++ {
+		u := a.units[a.visible[i]]
+		icon := stateIcon(u)
+		if a.storm != nil && u.Name == a.storm.root {
+			icon = cBold + cRed + "⚑" + cReset
+		}
+		if _, busy := a.pending[u.Name]; busy {
+			icon = cYellow + spinnerFrames[a.spin%len(spinnerFrames)] + cReset
+		}
+		chips, chipWidth := a.meta.get(u.Name).chips()
+		if chips != "" {
+			chips += " "
+			chipWidth++
+		}
+		line := fmt.Sprintf(" %s %-40s %-10s %s%s", icon, format.Truncate(u.Name, 40), u.Sub, chips,
+			format.Truncate(u.Description, max(0, a.width-56-chipWidth)))
+		if i == a.sel {
+			fmt.Fprintf(b, "%s%-*s%s\r\n", cRev, a.width, format.Truncate(line, a.width), cReset)
+		} else {
+			fmt.Fprintf(b, "%s\r\n", format.Truncate(line, a.width))
+		}
+	}
+	for _, note := range a.notes[len(a.notes)-noteRows:] {
+		fmt.Fprintf(b, " %s\r\n", note)
+	}
+	if a.mode == modeSchedule {
+		fmt.Fprintf(b, "%s restart %s at (HH:MM or 30m): %s%s▌%s", cYellow, a.scheduleUnit, cReset, a.scheduleInput, cReset)
+		return
+	}
+	if a.mode == modeNote {
+		fmt.Fprintf(b, "%s note for %s (#words are tags): %s%s▌%s", cYellow, a.noteUnit, cReset, a.noteInput, cReset)
+		return
+	}
+	fmt.Fprintf(b, "%s %s | j/k move  / filter  D degraded  l logs  a latency  s start  t stop  r restart  S schedule  P pending  R reload  q quit%s", cGray, a.status, cReset)
+}
+
+func (a *app) drawLog(b *strings.Builder) {
+	pos := "tail"
+	switch {
+	case a.stopFollow != nil && a.journal.atTail():
+		pos = "following"
+	case a.stopFollow != nil:
+		pos = "following, paused while scrolled"
+	case !a.journal.atTail():
+		pos = "scrolled"
+	}
+	fmt.Fprintf(b, "%s%s%-*s%s\r\n", cBold, cBlue, a.width, " logs: "+a.journalUnit+" ("+pos+")", cReset)
+	for _, row := range a.journal.visible(a.width, a.logHeight()) {
+		fmt.Fprintf(b, "%s\r\n", row)
+	}
+	fmt.Fprintf(b, "%s j/k scroll  space/ctrl-u page  G tail  f follow  q back%s", cGray, cReset)
+}
+
+func (a *app) drawPending(b *strings.Builder) {
+	pending := pendingScheduled(a.units)
+	title := fmt.Sprintf(" scheduled actions — %d pending", len(pending))
+	fmt.Fprintf(b, "%s%s%-*s%s\r\n", cBold, cBlue, a.width, title, cReset)
+	rows := max(1, a.height-2)
+	for i, u := range pending {
+		if i >= rows {
+			break
+		}
+		line := fmt.Sprintf(" %-44s %s", format.Truncate(u.Name, 44), format.Truncate(u.Description, max(0, a.width-47)))
+		if i == a.pendSel {
+			fmt.Fprintf(b, "%s%-*s%s\r\n", cRev, a.width, format.Truncate(line, a.width), cReset)
+		} else {
+			fmt.Fprintf(b, "%s\r\n", format.Truncate(line, a.width))
+		}
+	}
+	if len(pending) == 0 {
+		fmt.Fprintf(b, "%s nothing scheduled — press S on a unit to schedule a restart%s\r\n", cGray, cReset)
+	}
+	fmt.Fprintf(b, "%s j/k move  x cancel  R refresh  q back%s", cGray, cReset)
+}
+
+func (a *app) drawConfirm(b *strings.Builder) {
+	title := fmt.Sprintf(" %s %d member units?", a.confirmVerb, len(a.confirmUnits))
+	if len(a.confirmUnits) == 1 {
+		title = fmt.Sprintf(" %s %s?", a.confirmVerb, a.confirmUnits[0])
+	}
+	fmt.Fprintf(b, "%s%s%-*s%s\r\n", cBold, cYellow, a.width, title, cReset)
+	for _, w := range a.confirmWarn {
+		fmt.Fprintf(b, " %s%s%s\r\n", cYellow, format.Truncate(w, a.width-1), cReset)
+	}
+	rows := max(1, a.height-2-len(a.confirmWarn))
+	for i, name := range a.confirmUnits {
+		if i >= rows {
+			fmt.Fprintf(b, "%s … and %d more%s\r\n", cGray, len(a.confirmUnits)-rows, cReset)
+			break
+		}
+		fmt.Fprintf(b, "  %s\r\n", format.Truncate(name, a.width-2))
+	}
+	fmt.Fprintf(b, "%s y confirm  n/esc cancel%s", cGray, cReset)
+}
+
+func stateIcon(u unit) string {
+	switch {
+	case u.Active == "active":
+		return cGreen + "●" + cReset
+	case u.Active == "failed" || u.Sub == "failed":
+		return cRed + "✗" + cReset
+	case u.Active == "activating" || u.Active == "deactivating":
+		return cYellow + "●" + cReset
+	default:
+		return cGray + "○" + cReset
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/iamdanielv/utils/internal/dockerapi"
+	"github.com/iamdanielv/utils/internal/errcode"
+	"github.com/iamdanielv/utils/internal/format"
+)
+
+// restartLoopThreshold is how many restarts mark a container as looping.
+// Docker's default backoff makes a genuinely crash-looping container
+// cross this within a couple of minutes.
+const restartLoopThreshold = 3
+
+// finding is one problem detected in the project.
+type finding struct {
+	Container string `json:"container"`
+	Service   string `json:"service"`
+	Check     string `json:"check"`
+	Detail    string `json:"detail"`
+}
+
+// containerSummary is the per-container state shown in the report.
+type containerSummary struct {
+	Name     string `json:"name"`
+	Service  string `json:"service"`
+	State    string `json:"state"`
+	Health   string `json:"health,omitempty"`
+	Restarts int    `json:"restarts"`
+}
+
+// report is the full result of a project check.
+type report struct {
+	Project    string             `json:"project"`
+	Containers []containerSummary `json:"containers"`
+	Findings   []finding          `json:"findings"`
+}
+
+// checkProject lists every container in the compose project (including
+// stopped ones) and inspects each for trouble.
+func checkProject(ctx context.Context, docker *dockerapi.Client, project string) (*report, error) {
+	filters := map[string][]string{
+		"label": {"com.docker.compose.project=" + project},
+	}
+	containers, err := docker.ListContainers(ctx, filters, true)
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Unavailable, err)
+	}
+	if len(containers) == 0 {
+		return nil, errcode.New(errcode.Config, "no containers found for project %q", project)
+	}
+
+	rep := &report{Project: project}
+	for _, ctr := range containers {
+		detail, err := docker.InspectContainer(ctx, ctr.ID)
+		if err != nil {
+			return nil, errcode.Wrap(errcode.Unavailable, err)
+		}
+		service := ctr.Labels["com.docker.compose.service"]
+		rep.Containers = append(rep.Containers, summarize(ctr, detail, service))
+		rep.Findings = append(rep.Findings, checkContainer(ctr.Name(), service, detail)...)
+	}
+	rep.Findings = append(rep.Findings, checkPortConflicts(containers)...)
+
+	sort.Slice(rep.Containers, func(i, j int) bool {
+		return rep.Containers[i].Name < rep.Containers[j].Name
+	})
+	return rep, nil
+}
+
+func summarize(ctr dockerapi.Container, detail *dockerapi.ContainerDetail, service string) containerSummary {
+	s := containerSummary{
+		Name:     ctr.Name(),
+		Service:  service,
+		State:    detail.State.Status,
+		Restarts: detail.RestartCount,
+	}
+	if detail.State.Health != nil {
+		s.Health = detail.State.Health.Status
+	}
+	return s
+}
+
+// checkContainer classifies problems visible from a single inspect.
+func checkContainer(name, service string, detail *dockerapi.ContainerDetail) []finding {
+	var out []finding
+	add := func(check, detailMsg string) {
+		out = append(out, finding{Container: name, Service: service, Check: check, Detail: detailMsg})
+	}
+
+	if h := detail.State.Health; h != nil && h.Status == "unhealthy" {
+		add("unhealthy", fmt.Sprintf("healthcheck failing (streak %d)", h.FailingStreak))
+	}
+	if detail.State.Restarting {
+		add("restart-loop", "container is currently restarting")
+	} else if detail.RestartCount >= restartLoopThreshold {
+		add("restart-loop", fmt.Sprintf("restarted %d times", detail.RestartCount))
+	}
+	if detail.State.OOMKilled {
+		limit := "no limit set"
+		if detail.HostConfig.Memory > 0 {
+			limit = "limit " + format.Bytes(uint64(detail.HostConfig.Memory))
+		}
+		add("oom-killed", "killed by the OOM killer ("+limit+")")
+	}
+	if detail.State.Status == "exited" && detail.State.ExitCode != 0 {
+		add("exited", fmt.Sprintf("exit code %d", detail.State.ExitCode))
+	}
+	return out
+}
+
+// checkPortConflicts finds host ports published by more than one
+// container. Only one of them can actually hold the port; the rest are
+// either failing or about to.
+func checkPortConflicts(containers []dockerapi.Container) []finding {
+	owners := map[int][]string{}
+	for _, ctr := range containers {
+		for _, p := range ctr.Ports {
+			if p.PublicPort == 0 {
+				continue
+			}
+			// The daemon reports a 0.0.0.0 and a :: row per binding;
+			// count each container once per port.
+			if !contains(owners[p.PublicPort], ctr.Name()) {
+				owners[p.PublicPort] = append(owners[p.PublicPort], ctr.Name())
+			}
+		}
+	}
+
+	var out []finding
+	ports := make([]int, 0, len(owners))
+	for port := range owners {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	for _, port := range ports {
+		names := owners[port]
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			out = append(out, finding{
+				Container: name,
+				Check:     "port-conflict",
+				Detail:    fmt.Sprintf("host port %d also published by %s", port, others(names, name)),
+			})
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func others(names []string, self string) string {
+	var rest []string
+	for _, n := range names {
+		if n != self {
+			rest = append(rest, n)
+		}
+	}
+	if len(rest) == 1 {
+		return rest[0]
+	}
+	return fmt.Sprintf("%d other containers", len(rest))
+}
+
+// printReport renders the report as aligned text for humans.
+func printReport(w io.Writer, rep *report) {
+	tw := tabwriter.NewWriter(w, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONTAINER\tSERVICE\tSTATE\tHEALTH\tRESTARTS")
+	for _, c := range rep.Containers {
+		health := c.Health
+		if health == "" {
+			health = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", c.Name, c.Service, c.State, health, c.Restarts)
+	}
+	tw.Flush()
+
+	if len(rep.Findings) == 0 {
+		fmt.Fprintf(w, "\nproject %s: no problems found\n", rep.Project)
+		return
+	}
+	fmt.Fprintf(w, "\n%d problem(s):\n", len(rep.Findings))
+	for _, f := range rep.Findings {
+		fmt.Fprintf(w, "  ✗ %s: %s: %s\n", f.Container, f.Check, f.Detail)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/iamdanielv/utils/internal/dockerapi"
+)
+
+func detailWith(mutate func(*dockerapi.ContainerDetail)) *dockerapi.ContainerDetail {
+	d := &dockerapi.ContainerDetail{}
+	d.State.Status = "running"
+	mutate(d)
+	return d
+}
+
+func TestCheckContainer(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail *dockerapi.ContainerDetail
+		checks []string
+	}{
+		{
+			name:   "healthy running",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {}),
+			checks: nil,
+		},
+		{
+			name: "unhealthy",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {
+				d.State.Health = &struct {
+					Status        string `json:"Status"`
+					FailingStreak int    `json:"FailingStreak"`
+				}{Status: "unhealthy", FailingStreak: 4}
+			}),
+			checks: []string{"unhealthy"},
+		},
+		{
+			name: "restart loop by count",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {
+				d.RestartCount = restartLoopThreshold
+			}),
+			checks: []string{"restart-loop"},
+		},
+		{
+			name: "restarting right now",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {
+				d.State.Restarting = true
+			}),
+			checks: []string{"restart-loop"},
+		},
+		{
+			name: "oom killed with limit",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {
+				d.State.OOMKilled = true
+				d.HostConfig.Memory = 256 << 20
+			}),
+			checks: []string{"oom-killed"},
+		},
+		{
+			name: "crashed",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {
+				d.State.Status = "exited"
+				d.State.ExitCode = 137
+			}),
+			checks: []string{"exited"},
+		},
+		{
+			name: "clean exit is fine",
+			detail: detailWith(func(d *dockerapi.ContainerDetail) {
+				d.State.Status = "exited"
+			}),
+			checks: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkContainer("web-1", "web", tt.detail)
+			if len(got) != len(tt.checks) {
+				t.Fatalf("got %d findings %v, want checks %v", len(got), got, tt.checks)
+			}
+			for i, f := range got {
+				if f.Check != tt.checks[i] {
+					t.Errorf("finding %d: check %q, want %q", i, f.Check, tt.checks[i])
+				}
+				if f.Container != "web-1" || f.Service != "web" {
+					t.Errorf("finding %d: wrong attribution: %+v", i, f)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPortConflicts(t *testing.T) {
+	mkContainer := func(name string, ports ...dockerapi.Port) dockerapi.Container {
+		return dockerapi.Container{Names: []string{"/" + name}, Ports: ports}
+	}
+	hostPort := func(public int) dockerapi.Port {
+		return dockerapi.Port{IP: "0.0.0.0", PrivatePort: 80, PublicPort: public, Type: "tcp"}
+	}
+
+	t.Run("no conflict", func(t *testing.T) {
+		got := checkPortConflicts([]dockerapi.Container{
+			mkContainer("a", hostPort(8080)),
+			mkContainer("b", hostPort(8081)),
+		})
+		if len(got) != 0 {
+			t.Fatalf("unexpected findings: %v", got)
+		}
+	})
+
+	t.Run("two containers on one port", func(t *testing.T) {
+		got := checkPortConflicts([]dockerapi.Container{
+			mkContainer("a", hostPort(8080)),
+			mkContainer("b", hostPort(8080)),
+		})
+		if len(got) != 2 {
+			t.Fatalf("got %d findings, want 2: %v", len(got), got)
+		}
+		for _, f := range got {
+			if f.Check != "port-conflict" {
+				t.Errorf("check = %q, want port-conflict", f.Check)
+			}
+		}
+	})
+
+	t.Run("dual-stack rows are not a conflict", func(t *testing.T) {
+		got := checkPortConflicts([]dockerapi.Container{
+			mkContainer("a",
+				dockerapi.Port{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+				dockerapi.Port{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			),
+		})
+		if len(got) != 0 {
+			t.Fatalf("unexpected findings: %v", got)
+		}
+	})
+
+	t.Run("unpublished ports ignored", func(t *testing.T) {
+		got := checkPortConflicts([]dockerapi.Container{
+			mkContainer("a", dockerapi.Port{PrivatePort: 80, Type: "tcp"}),
+			mkContainer("b", dockerapi.Port{PrivatePort: 80, Type: "tcp"}),
+		})
+		if len(got) != 0 {
+			t.Fatalf("unexpected findings: %v", got)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+// composecheck inspects a docker compose project and reports unhealthy
+// containers, restart loops, resource-limit pressure and host port
+// conflicts — a read-only companion to go-scale for humans and CI.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/iamdanielv/utils/internal/dockerapi"
+	"github.com/iamdanielv/utils/internal/errcode"
+)
+
+func main() {
+	log.SetPrefix("composecheck: ")
+	log.SetFlags(0)
+
+	project := flag.String("project", "", "compose project to check (required)")
+	jsonOut := flag.Bool("json", false, "emit findings as JSON instead of a table")
+	flag.Parse()
+	if *project == "" {
+		fmt.Fprintln(os.Stderr, "composecheck: -project is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	docker, err := dockerapi.New()
+	if err != nil {
+		errcode.Fatal(errcode.Wrap(errcode.Config, err))
+	}
+	ctx := context.Background()
+	if err := docker.Negotiate(ctx); err != nil {
+		errcode.Fatal(errcode.Wrap(errcode.Unavailable, err))
+	}
+
+	report, err := checkProject(ctx, docker, *project)
+	if err != nil {
+		errcode.Fatal(err)
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			errcode.Fatal(err)
+		}
+	} else {
+		printReport(os.Stdout, report)
+	}
+
+	// CI semantics: findings mean a non-zero exit.
+	if len(report.Findings) > 0 {
+		os.Exit(int(errcode.Partial))
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// alertmanagerClient pushes the autoscaler's operational alerts —
+// circuit open, crash loops, stuck at max replicas — to a Prometheus
+// Alertmanager, so notifications flow through whatever routing and
+// silencing rules the fleet already has.
+type alertmanagerClient struct {
+	url    string // Alertmanager base URL
+	client *http.Client
+}
+
+func newAlertmanagerClient(url string) *alertmanagerClient {
+	return &alertmanagerClient{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// amAlert is one entry in the POST /api/v2/alerts payload.
+type amAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+}
+
+// send posts one alert. A resolved alert is the same payload with
+// endsAt set, which is how Alertmanager is told the condition cleared.
+func (c *alertmanagerClient) send(a amAlert) error {
+	body, err := json.Marshal([]amAlert{a})
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Post(c.url+"/api/v2/alerts", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
+
+// alert raises (or resolves) a named alert for this target, fanning it
+// out to Alertmanager and the chat notifiers (each a no-op when not
+// configured). Posting happens in the background so a slow receiver
+// never blocks the poll loop. Callers fire only on state transitions;
+// the receivers handle dedup and routing.
+func (a *autoscaler) alert(name, summary string, firing bool) {
+	if a.notify != nil {
+		msg := summary
+		if !firing {
+			msg = "resolved: " + summary
+		}
+		a.notify.send(notification{
+			Time: a.now(), Project: a.cfg.project, Service: a.cfg.service,
+			Kind: "alert", Message: msg, Resolved: !firing,
+		})
+	}
+	if a.alerts == nil {
+		return
+	}
+	am := amAlert{
+		Labels: map[string]string{
+			"alertname": name,
+			"project":   a.cfg.project,
+			"service":   a.cfg.service,
+			"severity":  "warning",
+		},
+		Annotations: map[string]string{"summary": summary},
+		StartsAt:    a.now(),
+	}
+	if !firing {
+		ends := a.now()
+		am.EndsAt = &ends
+	}
+	go func() {
+		if err := a.alerts.send(am); err != nil {
+			log.Printf("✗ alertmanager: %v", err)
+		}
+	}()
+}
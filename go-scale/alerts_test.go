@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAlertmanagerSend(t *testing.T) {
+	var got []amAlert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/alerts" {
+			t.Errorf("path = %q, want /api/v2/alerts", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	c := newAlertmanagerClient(srv.URL)
+	firing := amAlert{
+		Labels:   map[string]string{"alertname": "GoScaleCrashLoop", "project": "demo", "service": "web"},
+		StartsAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := c.send(firing); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(got) != 1 || got[0].Labels["alertname"] != "GoScaleCrashLoop" {
+		t.Fatalf("posted alerts = %+v", got)
+	}
+	if got[0].EndsAt != nil {
+		t.Error("firing alert carried an endsAt")
+	}
+
+	ends := firing.StartsAt.Add(time.Minute)
+	firing.EndsAt = &ends
+	if err := c.send(firing); err != nil {
+		t.Fatalf("send resolved: %v", err)
+	}
+	if got[0].EndsAt == nil || !got[0].EndsAt.Equal(ends) {
+		t.Errorf("resolved alert endsAt = %v, want %v", got[0].EndsAt, ends)
+	}
+}
+
+func TestAlertmanagerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := newAlertmanagerClient(srv.URL)
+	if err := c.send(amAlert{Labels: map[string]string{"alertname": "X"}}); err == nil {
+		t.Fatal("want error on non-2xx status")
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"log"
+	"math"
+	"sort"
+)
+
+// Spike filtering. The stats endpoint occasionally produces a garbage
+// sample — 100% CPU for one read amid 5% readings, usually a glitched
+// cpu delta — and one such poll can start a scale-up streak. With
+// -spike-filter each signal keeps a window of recently accepted
+// samples, and a reading wildly inconsistent with that window is
+// replaced by the window's median instead of feeding the decision. A
+// spike that persists is believed after spikeMaxStreak polls, so real
+// load surges still get through with one or two polls of delay.
+
+const (
+	// spikeDeviations scales the window's median absolute deviation
+	// into the accepted band around the median.
+	spikeDeviations = 5.0
+	// spikeMaxStreak is how many consecutive polls may be discarded
+	// before the new level is accepted as real.
+	spikeMaxStreak = 2
+	// spikeMinSamples is the window size below which nothing is
+	// filtered; three points don't define "usual".
+	spikeMinSamples = 3
+)
+
+// spikeFilter smooths one signal; the zero pointer filters nothing.
+type spikeFilter struct {
+	name   string
+	size   int
+	window []float64 // recent accepted samples, newest last
+	streak int       // consecutive discards
+}
+
+func newSpikeFilter(name string, size int) *spikeFilter {
+	return &spikeFilter{name: name, size: size}
+}
+
+// filter returns the value the decision should use: v itself when it
+// is consistent with the recent window (or has persisted long enough
+// to believe), the window median when it looks like a glitch.
+func (f *spikeFilter) filter(v float64) float64 {
+	if f == nil {
+		return v
+	}
+	med, mad := medianMAD(f.window)
+	// The accepted band is a few deviations around the median, plus
+	// slack so a flat window (MAD zero) doesn't reject every move.
+	slack := math.Max(5, 0.5*math.Abs(med))
+	if len(f.window) >= spikeMinSamples &&
+		math.Abs(v-med) > spikeDeviations*mad+slack &&
+		f.streak < spikeMaxStreak {
+		f.streak++
+		log.Printf("i discarding anomalous %s sample %.1f (recent median %.1f)", f.name, v, med)
+		return med
+	}
+	f.streak = 0
+	f.window = append(f.window, v)
+	if len(f.window) > f.size {
+		f.window = f.window[1:]
+	}
+	return v
+}
+
+// medianMAD returns the median and the median absolute deviation of
+// the samples; both zero for an empty slice.
+func medianMAD(samples []float64) (med, mad float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	med = median(samples)
+	devs := make([]float64, len(samples))
+	for i, s := range samples {
+		devs[i] = math.Abs(s - med)
+	}
+	return med, median(devs)
+}
+
+func median(samples []float64) float64 {
+	s := make([]float64, len(samples))
+	copy(s, samples)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
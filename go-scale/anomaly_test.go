@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestSpikeFilterDiscardsGlitch(t *testing.T) {
+	f := newSpikeFilter("cpu", 10)
+	for _, v := range []float64{5, 6, 5, 4, 5} {
+		if got := f.filter(v); got != v {
+			t.Fatalf("filter(%g) = %g while warming up", v, got)
+		}
+	}
+	// A one-sample 100% amid 5% readings is a stats glitch: the median
+	// stands in for it.
+	if got := f.filter(100); got != 5 {
+		t.Errorf("filter(100) = %g, want the median 5", got)
+	}
+	// Back to normal: the glitch left no trace in the window.
+	if got := f.filter(6); got != 6 {
+		t.Errorf("filter(6) after the glitch = %g", got)
+	}
+}
+
+func TestSpikeFilterBelievesPersistentSpike(t *testing.T) {
+	f := newSpikeFilter("cpu", 10)
+	for _, v := range []float64{5, 6, 5, 4} {
+		f.filter(v)
+	}
+	// A real surge is discarded for spikeMaxStreak polls, then believed.
+	for i := 0; i < spikeMaxStreak; i++ {
+		if got := f.filter(95); got == 95 {
+			t.Fatalf("poll %d of the surge accepted early", i+1)
+		}
+	}
+	if got := f.filter(95); got != 95 {
+		t.Errorf("persistent surge still filtered: %g", got)
+	}
+}
+
+func TestSpikeFilterTracksGradualChange(t *testing.T) {
+	f := newSpikeFilter("mem", 5)
+	for v := 10.0; v <= 90; v += 5 {
+		if got := f.filter(v); got != v {
+			t.Fatalf("gradual ramp filtered at %g (got %g)", v, got)
+		}
+	}
+}
+
+func TestSpikeFilterNil(t *testing.T) {
+	var f *spikeFilter
+	if got := f.filter(42); got != 42 {
+		t.Errorf("nil filter changed the value: %g", got)
+	}
+}
+
+func TestMedianMAD(t *testing.T) {
+	med, mad := medianMAD([]float64{1, 2, 3, 4, 100})
+	if med != 3 || mad != 1 {
+		t.Errorf("medianMAD = %g, %g, want 3, 1", med, mad)
+	}
+	if med, mad := medianMAD(nil); med != 0 || mad != 0 {
+		t.Errorf("medianMAD(nil) = %g, %g", med, mad)
+	}
+	if got := median([]float64{4, 1, 3, 2}); got != 2.5 {
+		t.Errorf("median of even-sized slice = %g, want 2.5", got)
+	}
+}
@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiServer exposes the control API. Endpoints are namespaced per
+// compose project under /projects/<name>/, and each project can have
+// its own token so a team can be granted pause/resume rights for their
+// stack only. The admin token (if set) works everywhere.
+type apiServer struct {
+	// targets holds one autoscaler per scaled project/service pair; the
+	// set can grow and shrink at runtime through /targets.
+	targets *targetSet
+	// addTarget registers a new pair; nil disables runtime adds.
+	addTarget func(project, service string) error
+	// adminToken authorizes every endpoint; projectTokens authorize
+	// only the matching project namespace.
+	adminToken    string
+	projectTokens map[string]string
+	// pprof exposes /debug/pprof/ (admin token required) for profiling
+	// a long-running daemon.
+	pprof bool
+}
+
+// serveAPI runs the control API until the context is cancelled. It is
+// disabled unless -api-addr is set.
+func serveAPI(ctx context.Context, cfg *config, ts *targetSet, addTarget func(project, service string) error) {
+	api := &apiServer{
+		targets:       ts,
+		addTarget:     addTarget,
+		adminToken:    cfg.apiToken,
+		projectTokens: cfg.apiProjectTokens,
+		pprof:         cfg.pprofEnabled,
+	}
+
+	srv := &http.Server{Addr: cfg.apiAddr, Handler: api.handler()}
+	go func() {
+		<-ctx.Done()
+		shutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutCtx)
+	}()
+
+	log.Printf("control API listening on %s", cfg.apiAddr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Printf("✗ control API: %v", err)
+	}
+}
+
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/projects/", s.handleProject)
+	// Unnamespaced endpoints are kept for existing scripts and require
+	// the admin token. With one target they behave as before; with
+	// several, status reports all targets and pause/resume apply to all.
+	mux.HandleFunc("/status", s.adminOnly(s.statusAll))
+	mux.HandleFunc("/pause", s.adminOnly(s.pauseAll))
+	mux.HandleFunc("/resume", s.adminOnly(s.resumeAll))
+	mux.HandleFunc("/version", s.adminOnly(s.version))
+	mux.HandleFunc("/metrics", s.adminOnly(s.selfMetrics))
+	mux.HandleFunc("/targets", s.adminOnly(s.handleTargets))
+	// Profiling a daemon whose RSS has crept up over weeks needs live
+	// pprof data; handlers are registered explicitly (not via the
+	// net/http/pprof side-effect import) so they stay off the mux — and
+	// behind the admin token — unless asked for.
+	if s.pprof {
+		mux.HandleFunc("/debug/pprof/", s.adminOnly(pprof.Index))
+		mux.HandleFunc("/debug/pprof/cmdline", s.adminOnly(pprof.Cmdline))
+		mux.HandleFunc("/debug/pprof/profile", s.adminOnly(pprof.Profile))
+		mux.HandleFunc("/debug/pprof/symbol", s.adminOnly(pprof.Symbol))
+		mux.HandleFunc("/debug/pprof/trace", s.adminOnly(pprof.Trace))
+	}
+	return mux
+}
+
+// version reports the build stamped into the binary, so a support
+// request can say exactly which go-scale is running.
+func (s *apiServer) version(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"version": version,
+		"commit":  commit,
+		"built":   buildDate,
+	})
+}
+
+// selfMetrics serves the process's own counters and latency histograms
+// in the Prometheus text format.
+func (s *apiServer) selfMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	self.writeProm(w)
+}
+
+// target resolves a project (and, when a project runs several scaled
+// services, the service form value) to its autoscaler. A nil return
+// means the response has already been written.
+func (s *apiServer) target(w http.ResponseWriter, r *http.Request, project string) *autoscaler {
+	var matches []*autoscaler
+	for _, as := range s.targets.all() {
+		if as.cfg.project == project {
+			matches = append(matches, as)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		http.Error(w, "unknown project", http.StatusNotFound)
+		return nil
+	case len(matches) == 1:
+		return matches[0]
+	}
+	svc := r.FormValue("service")
+	if svc == "" {
+		http.Error(w, "project has several scaled services, pass service=<name>", http.StatusBadRequest)
+		return nil
+	}
+	for _, as := range matches {
+		if as.cfg.service == svc {
+			return as
+		}
+	}
+	http.Error(w, "unknown service", http.StatusNotFound)
+	return nil
+}
+
+// handleProject routes /projects/<name>/<action>, authorizing with the
+// project's token or the admin token.
+func (s *apiServer) handleProject(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/projects/")
+	project, action, ok := strings.Cut(rest, "/")
+	if !ok || project == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.authorized(r, project) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	as := s.target(w, r, project)
+	if as == nil {
+		return
+	}
+	switch action {
+	case "status":
+		s.status(w, r, as)
+	case "events":
+		s.events(w, r, as)
+	case "stream":
+		s.stream(w, r, as)
+	case "pause":
+		s.pause(w, r, as)
+	case "resume":
+		s.resume(w, r, as)
+	case "inject":
+		s.inject(w, r, as)
+	case "reload":
+		s.reloadConfig(w, r, as)
+	case "approve":
+		s.decide(w, r, as, true)
+	case "reject":
+		s.decide(w, r, as, false)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authorized reports whether the request carries the admin token or the
+// token configured for the given project. With no tokens configured at
+// all, the API is open (matching the previous behavior of an empty
+// -api-token).
+func (s *apiServer) authorized(r *http.Request, project string) bool {
+	if s.adminToken == "" && len(s.projectTokens) == 0 {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	if s.adminToken != "" && token == s.adminToken {
+		return true
+	}
+	want, ok := s.projectTokens[project]
+	return ok && token == want
+}
+
+// adminOnly mirrors authorized(): with no tokens configured at all the
+// API is open, but as soon as any token exists — admin or project —
+// the admin endpoints require the admin token. A project token must
+// never unlock them: they cut across every project.
+func (s *apiServer) adminOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		open := s.adminToken == "" && len(s.projectTokens) == 0
+		if !open && (s.adminToken == "" || r.Header.Get("Authorization") != "Bearer "+s.adminToken) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *apiServer) status(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	w.Header().Set("Content-Type", "application/json")
+	// ?event=<id> looks up one scale event by its correlation ID.
+	if id := r.FormValue("event"); id != "" {
+		event, ok := as.findEvent(id)
+		if !ok {
+			http.Error(w, "unknown event", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(event)
+		return
+	}
+	json.NewEncoder(w).Encode(statusObject(as))
+}
+
+func statusObject(as *autoscaler) map[string]any {
+	status := map[string]any{
+		"project":  as.cfg.project,
+		"service":  as.cfg.service,
+		"replicas": as.currentReplicas(),
+		"paused":   as.isPaused(),
+		"events":   as.recentEvents(),
+	}
+	if dec, ok := as.currentDecision(); ok {
+		status["pending"] = dec
+	}
+	return status
+}
+
+// statusAll keeps the legacy single-target response shape when one
+// target is configured, and returns an array with several.
+func (s *apiServer) statusAll(w http.ResponseWriter, r *http.Request) {
+	targets := s.targets.all()
+	if len(targets) == 1 {
+		s.status(w, r, targets[0])
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	all := make([]map[string]any, 0, len(targets))
+	for _, as := range targets {
+		all = append(all, statusObject(as))
+	}
+	json.NewEncoder(w).Encode(all)
+}
+
+// events returns the recent scale events as JSON (default) or CSV
+// (?format=csv), newest last.
+func (s *apiServer) events(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	events := as.recentEvents()
+	switch format := r.FormValue("format"); format {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		if err := writeEventsCSV(w, events); err != nil {
+			log.Printf("✗ events export: %v", err)
+		}
+	default:
+		http.Error(w, "unknown format "+format, http.StatusBadRequest)
+	}
+}
+
+// stream pushes scale events and pending decisions to the client as
+// server-sent events, so dashboards and bots can subscribe instead of
+// polling /events. Event names are "scale" and "pending"; the data
+// field carries the same JSON the other endpoints return.
+func (s *apiServer) stream(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	fl, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := as.stream.subscribe()
+	defer as.stream.unsubscribe(ch)
+	fmt.Fprint(w, ": connected\n\n")
+	fl.Flush()
+
+	// Keepalive comments hold idle connections open through proxies.
+	keepalive := time.NewTicker(30 * time.Second)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			fl.Flush()
+		case m := <-ch:
+			data, err := json.Marshal(m.data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.kind, data)
+			fl.Flush()
+		}
+	}
+}
+
+// decide approves or rejects the decision queued in approval mode.
+// An optional id form value pins the call to a specific event, so a
+// decision superseded mid-review cannot be approved by accident.
+func (s *apiServer) decide(w http.ResponseWriter, r *http.Request, as *autoscaler, approve bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	e, ok := as.resolveDecision(r.FormValue("id"), approve)
+	if !ok {
+		http.Error(w, "no matching pending decision", http.StatusNotFound)
+		return
+	}
+	verdict := "approved"
+	if !approve {
+		verdict = "rejected"
+	}
+	log.Printf("[%s] %s via control API", e.ID, verdict)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) pause(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	as.setPaused(true)
+	log.Printf("%s/%s paused via control API", as.cfg.project, as.cfg.service)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) pauseAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	for _, as := range s.targets.all() {
+		as.setPaused(true)
+	}
+	log.Printf("paused via control API")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *apiServer) resumeAll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	for _, as := range s.targets.all() {
+		as.setPaused(false)
+	}
+	log.Printf("resumed via control API")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// inject arms a metric override window for rehearsing scale behavior:
+// POST with spec=cpu=90,mem=50 and an optional polls count. An empty
+// spec clears any active window.
+func (s *apiServer) inject(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	spec := r.FormValue("spec")
+	if spec == "" {
+		as.setInjection(nil)
+		log.Printf("injected metrics cleared via control API")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	polls := 10
+	if raw := r.FormValue("polls"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			http.Error(w, "bad polls value", http.StatusBadRequest)
+			return
+		}
+		polls = n
+	}
+	inj, err := parseInjection(spec, polls)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	as.setInjection(inj)
+	log.Printf("injected metrics %q armed for %d polls via control API", spec, polls)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTargets lists, adds or removes scale targets at runtime, so a
+// multi-service daemon can pick up a new stack without a restart. POST
+// validates the pair first (service present in the compose config, or
+// nomad group resolvable); DELETE stops the target's goroutines and
+// drops it.
+func (s *apiServer) handleTargets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		list := make([]map[string]string, 0)
+		for _, as := range s.targets.all() {
+			list = append(list, map[string]string{"project": as.cfg.project, "service": as.cfg.service})
+		}
+		json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		project, service := r.FormValue("project"), r.FormValue("service")
+		if project == "" || service == "" {
+			http.Error(w, "project and service are required", http.StatusBadRequest)
+			return
+		}
+		if s.addTarget == nil {
+			http.Error(w, "runtime targets are not available", http.StatusNotImplemented)
+			return
+		}
+		if err := s.addTarget(project, service); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("target %s/%s added via control API", project, service)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		project, service := r.FormValue("project"), r.FormValue("service")
+		if project == "" || service == "" {
+			http.Error(w, "project and service are required", http.StatusBadRequest)
+			return
+		}
+		as, err := s.targets.remove(project, service)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if as == nil {
+			http.Error(w, "unknown target", http.StatusNotFound)
+			return
+		}
+		if as.stop != nil {
+			as.stop()
+		}
+		log.Printf("target %s/%s removed via control API", project, service)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// reloadConfig asks the poll loop to re-read the -config file, the
+// same path a SIGHUP takes. The diff of what changed lands in the log.
+func (s *apiServer) reloadConfig(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if as.cfg.configFile == "" {
+		http.Error(w, "no -config file to reload", http.StatusNotFound)
+		return
+	}
+	as.requestReload()
+	log.Printf("config reload requested via control API")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *apiServer) resume(w http.ResponseWriter, r *http.Request, as *autoscaler) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	as.setPaused(false)
+	log.Printf("%s/%s resumed via control API", as.cfg.project, as.cfg.service)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testAPIServer() *apiServer {
+	cfg := &config{project: "shop", service: "web"}
+	return &apiServer{
+		targets:       &targetSet{scalers: []*autoscaler{newAutoscaler(cfg, nil)}},
+		adminToken:    "admin-secret",
+		projectTokens: map[string]string{"shop": "shop-secret"},
+	}
+}
+
+func doRequest(s *apiServer, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.handler().ServeHTTP(w, req)
+	return w
+}
+
+func TestProjectTokenScopedToProject(t *testing.T) {
+	s := testAPIServer()
+
+	if w := doRequest(s, http.MethodPost, "/projects/shop/pause", "shop-secret"); w.Code != http.StatusNoContent {
+		t.Errorf("project token on own project: got %d, want 204", w.Code)
+	}
+	if !s.targets.all()[0].isPaused() {
+		t.Error("pause did not take effect")
+	}
+	if w := doRequest(s, http.MethodPost, "/projects/shop/resume", "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad token: got %d, want 401", w.Code)
+	}
+}
+
+func TestAdminTokenWorksEverywhere(t *testing.T) {
+	s := testAPIServer()
+
+	if w := doRequest(s, http.MethodGet, "/projects/shop/status", "admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("admin on project namespace: got %d, want 200", w.Code)
+	}
+	if w := doRequest(s, http.MethodGet, "/status", "admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("admin on legacy endpoint: got %d, want 200", w.Code)
+	}
+	if w := doRequest(s, http.MethodGet, "/status", "shop-secret"); w.Code != http.StatusUnauthorized {
+		t.Errorf("project token on legacy admin endpoint: got %d, want 401", w.Code)
+	}
+}
+
+func TestUnknownProject(t *testing.T) {
+	s := testAPIServer()
+	if w := doRequest(s, http.MethodGet, "/projects/other/status", "admin-secret"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown project: got %d, want 404", w.Code)
+	}
+}
+
+func TestMultiTargetRouting(t *testing.T) {
+	s := testAPIServer()
+	s.targets.add(newAutoscaler(&config{project: "shop", service: "worker"}, nil))
+	s.targets.add(newAutoscaler(&config{project: "blog", service: "web"}, nil))
+
+	// Two services under "shop": the service parameter picks one.
+	if w := doRequest(s, http.MethodPost, "/projects/shop/pause", "admin-secret"); w.Code != http.StatusBadRequest {
+		t.Errorf("ambiguous project: got %d, want 400", w.Code)
+	}
+	if w := doRequest(s, http.MethodPost, "/projects/shop/pause?service=worker", "admin-secret"); w.Code != http.StatusNoContent {
+		t.Errorf("pause with service param: got %d, want 204", w.Code)
+	}
+	if all := s.targets.all(); all[0].isPaused() || !all[1].isPaused() {
+		t.Error("pause hit the wrong target")
+	}
+	if w := doRequest(s, http.MethodPost, "/projects/shop/pause?service=db", "admin-secret"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown service: got %d, want 404", w.Code)
+	}
+
+	// A single-service project needs no parameter.
+	if w := doRequest(s, http.MethodGet, "/projects/blog/status", "admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("blog status: got %d, want 200", w.Code)
+	}
+
+	// Legacy admin pause applies to every target.
+	if w := doRequest(s, http.MethodPost, "/pause", "admin-secret"); w.Code != http.StatusNoContent {
+		t.Errorf("legacy pause: got %d, want 204", w.Code)
+	}
+	for i, as := range s.targets.all() {
+		if !as.isPaused() {
+			t.Errorf("target %d not paused by legacy endpoint", i)
+		}
+	}
+}
+
+func TestRuntimeTargets(t *testing.T) {
+	s := testAPIServer()
+	var added []string
+	s.addTarget = func(project, service string) error {
+		if s.targets.has(project, service) {
+			t.Fatalf("add called for existing target %s/%s", project, service)
+		}
+		added = append(added, project+"/"+service)
+		s.targets.add(newAutoscaler(&config{project: project, service: service}, nil))
+		return nil
+	}
+
+	if w := doRequest(s, http.MethodPost, "/targets?project=blog&service=web", "admin-secret"); w.Code != http.StatusCreated {
+		t.Errorf("add target: got %d, want 201", w.Code)
+	}
+	if len(added) != 1 || added[0] != "blog/web" {
+		t.Errorf("addTarget calls = %v", added)
+	}
+	if w := doRequest(s, http.MethodGet, "/targets", "admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("list targets: got %d, want 200", w.Code)
+	}
+	if w := doRequest(s, http.MethodPost, "/targets?project=blog", "admin-secret"); w.Code != http.StatusBadRequest {
+		t.Errorf("add without service: got %d, want 400", w.Code)
+	}
+
+	if w := doRequest(s, http.MethodDelete, "/targets?project=blog&service=web", "admin-secret"); w.Code != http.StatusNoContent {
+		t.Errorf("remove target: got %d, want 204", w.Code)
+	}
+	if s.targets.has("blog", "web") {
+		t.Error("removed target is still in the set")
+	}
+	if w := doRequest(s, http.MethodDelete, "/targets?project=blog&service=web", "admin-secret"); w.Code != http.StatusNotFound {
+		t.Errorf("remove unknown target: got %d, want 404", w.Code)
+	}
+	if w := doRequest(s, http.MethodDelete, "/targets?project=shop&service=web", "admin-secret"); w.Code != http.StatusConflict {
+		t.Errorf("remove last target: got %d, want 409", w.Code)
+	}
+	if w := doRequest(s, http.MethodPost, "/targets?project=a&service=b", "wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("add without admin token: got %d, want 401", w.Code)
+	}
+}
+
+func TestOpenWhenNoTokensConfigured(t *testing.T) {
+	s := testAPIServer()
+	s.adminToken = ""
+	s.projectTokens = nil
+	if w := doRequest(s, http.MethodGet, "/projects/shop/status", ""); w.Code != http.StatusOK {
+		t.Errorf("open API: got %d, want 200", w.Code)
+	}
+	if w := doRequest(s, http.MethodGet, "/status", ""); w.Code != http.StatusOK {
+		t.Errorf("open admin endpoint: got %d, want 200", w.Code)
+	}
+}
+
+func TestAdminLockedWithOnlyProjectTokens(t *testing.T) {
+	// Project tokens without an admin token must not leave the
+	// cross-project endpoints open.
+	s := testAPIServer()
+	s.adminToken = ""
+	for _, token := range []string{"", "shop-secret"} {
+		if w := doRequest(s, http.MethodGet, "/status", token); w.Code != http.StatusUnauthorized {
+			t.Errorf("admin endpoint with token %q: got %d, want 401", token, w.Code)
+		}
+		if w := doRequest(s, http.MethodPost, "/pause", token); w.Code != http.StatusUnauthorized {
+			t.Errorf("pause with token %q: got %d, want 401", token, w.Code)
+		}
+		if w := doRequest(s, http.MethodDelete, "/targets?project=shop&service=web", token); w.Code != http.StatusUnauthorized {
+			t.Errorf("target removal with token %q: got %d, want 401", token, w.Code)
+		}
+	}
+	// The project namespace keeps working with its own token.
+	if w := doRequest(s, http.MethodGet, "/projects/shop/status", "shop-secret"); w.Code != http.StatusOK {
+		t.Errorf("project status with project token: got %d, want 200", w.Code)
+	}
+}
+
+func TestPprofBehindFlagAndToken(t *testing.T) {
+	s := testAPIServer()
+	if w := doRequest(s, http.MethodGet, "/debug/pprof/", "admin-secret"); w.Code != http.StatusNotFound {
+		t.Errorf("pprof without -pprof: got %d, want 404", w.Code)
+	}
+
+	s.pprof = true
+	if w := doRequest(s, http.MethodGet, "/debug/pprof/", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("pprof without token: got %d, want 401", w.Code)
+	}
+	if w := doRequest(s, http.MethodGet, "/debug/pprof/", "admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("pprof with admin token: got %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// pendingDecision is a scale action held for operator sign-off in
+// approval mode. At most one is queued: the autoscaler's view of what
+// should happen right now.
+type pendingDecision struct {
+	Event    scaleEvent `json:"event"`
+	Proposed time.Time  `json:"proposed"`
+	Approved bool       `json:"approved"`
+}
+
+// queueDecision holds a decided scale action for approval. A pending
+// decision with the same target is left alone (the poll loop proposes
+// it again every interval); a different target supersedes it, unless
+// the operator already approved — approvals are never silently thrown
+// away.
+func (a *autoscaler) queueDecision(e scaleEvent) {
+	a.mu.Lock()
+	prev := a.pendingDec
+	if prev != nil && (prev.Approved || prev.Event.To == e.To) {
+		a.mu.Unlock()
+		return
+	}
+	a.pendingDec = &pendingDecision{Event: e, Proposed: time.Now()}
+	a.mu.Unlock()
+	if prev != nil {
+		log.Printf("[%s] superseded by [%s] before approval", prev.Event.ID, e.ID)
+	}
+	log.Printf("[%s] awaiting approval: scale %s %d -> %d (cpu %.1f%%, mem %.1f%%)",
+		e.ID, a.cfg.service, e.From, e.To, e.CPU, e.Mem)
+	a.stream.publish("pending", e)
+}
+
+// currentDecision returns the queued decision for the status API.
+func (a *autoscaler) currentDecision() (pendingDecision, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pendingDec == nil {
+		return pendingDecision{}, false
+	}
+	return *a.pendingDec, true
+}
+
+// resolveDecision approves or rejects the queued decision by event ID
+// (empty matches whatever is queued). Approved decisions stay queued
+// until the poll loop collects them; rejected ones are recorded and
+// dropped.
+func (a *autoscaler) resolveDecision(id string, approve bool) (scaleEvent, bool) {
+	a.mu.Lock()
+	if a.pendingDec == nil || (id != "" && a.pendingDec.Event.ID != id) {
+		a.mu.Unlock()
+		return scaleEvent{}, false
+	}
+	e := a.pendingDec.Event
+	if approve {
+		a.pendingDec.Approved = true
+		a.mu.Unlock()
+		return e, true
+	}
+	a.pendingDec = nil
+	a.mu.Unlock()
+	e.Outcome = "rejected"
+	a.recordEvent(e)
+	return e, true
+}
+
+// approvedDecision pops the queued decision once an operator approved
+// it, for the poll loop to execute.
+func (a *autoscaler) approvedDecision() (scaleEvent, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pendingDec == nil || !a.pendingDec.Approved {
+		return scaleEvent{}, false
+	}
+	e := a.pendingDec.Event
+	a.pendingDec = nil
+	return e, true
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestApprovalQueue(t *testing.T) {
+	a := &autoscaler{cfg: &config{approvalMode: true, service: "web"}}
+
+	if _, ok := a.approvedDecision(); ok {
+		t.Fatal("empty queue produced an approved decision")
+	}
+	if _, ok := a.resolveDecision("", true); ok {
+		t.Fatal("approved with nothing queued")
+	}
+
+	a.queueDecision(scaleEvent{ID: "aaaa", From: 2, To: 4})
+	dec, ok := a.currentDecision()
+	if !ok || dec.Event.ID != "aaaa" || dec.Approved {
+		t.Fatalf("currentDecision = %+v, %v", dec, ok)
+	}
+
+	// Same target re-proposed: the queued decision stays put.
+	a.queueDecision(scaleEvent{ID: "bbbb", From: 2, To: 4})
+	if dec, _ := a.currentDecision(); dec.Event.ID != "aaaa" {
+		t.Errorf("same-target proposal replaced the queued decision with %s", dec.Event.ID)
+	}
+
+	// Different target supersedes an unapproved decision.
+	a.queueDecision(scaleEvent{ID: "cccc", From: 2, To: 5})
+	if dec, _ := a.currentDecision(); dec.Event.ID != "cccc" {
+		t.Errorf("queued decision = %s, want cccc", dec.Event.ID)
+	}
+
+	// Approving with a stale ID misses; the right ID sticks.
+	if _, ok := a.resolveDecision("aaaa", true); ok {
+		t.Error("approved a superseded decision ID")
+	}
+	if _, ok := a.resolveDecision("cccc", true); !ok {
+		t.Fatal("approve by ID failed")
+	}
+
+	// An approved decision survives new proposals until collected.
+	a.queueDecision(scaleEvent{ID: "dddd", From: 2, To: 3})
+	e, ok := a.approvedDecision()
+	if !ok || e.ID != "cccc" {
+		t.Fatalf("approvedDecision = %+v, %v; want cccc", e, ok)
+	}
+	if _, ok := a.approvedDecision(); ok {
+		t.Error("approved decision handed out twice")
+	}
+}
+
+func TestApprovalReject(t *testing.T) {
+	a := &autoscaler{cfg: &config{approvalMode: true, service: "web"}}
+	a.queueDecision(scaleEvent{ID: "aaaa", From: 3, To: 2})
+	if _, ok := a.resolveDecision("", false); !ok {
+		t.Fatal("reject failed")
+	}
+	if _, ok := a.currentDecision(); ok {
+		t.Error("rejected decision still queued")
+	}
+	if e, ok := a.findEvent("aaaa"); !ok || e.Outcome != "rejected" {
+		t.Errorf("rejected event record = %+v, %v", e, ok)
+	}
+}
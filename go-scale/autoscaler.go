@@ -0,0 +1,776 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	engine "github.com/iamdanielv/utils/pkg/autoscaler"
+	"github.com/iamdanielv/utils/pkg/metrics"
+)
+
+// autoscaler owns the poll/decide/scale loop for one compose service.
+type autoscaler struct {
+	cfg        *config
+	docker     *dockerClient
+	nomad      *nomadClient // non-nil with -backend nomad; docker is unused then
+	guard      hostGuard
+	metric     metricSource
+	statsd     *statsdClient       // nil when not configured
+	influx     *influxExporter     // nil when not configured
+	drain      drainer             // nil when not configured
+	pid        *engine.PID         // nil in threshold mode
+	webhook    *decisionWebhook    // nil when not configured
+	alerts     *alertmanagerClient // nil when not configured
+	notify     *notifierSet        // nil when not configured
+	history    *loadHistory        // nil when not configured
+	now        func() time.Time    // time source; the simulator swaps in a fake clock
+	reload     chan struct{}       // asks the poll loop to re-read the config file
+	poke       chan struct{}       // asks the poll loop to evaluate immediately
+	exportW    io.Writer           // scale events appended as JSON lines; nil when not configured
+	heartbeatW io.Writer           // JSON heartbeats; nil means stdout
+	statsCache *statsCache         // nil when -stats-cache is off; shared across targets
+	metricPoll *metricPoll         // non-nil with -metric-interval; holds the background sample
+	stream     streamBroker        // fans events out to SSE subscribers
+	breaker    engine.Breaker      // opens after repeated docker/compose failures
+	stop       context.CancelFunc  // cancels this target's goroutines on runtime removal
+
+	// capacity is the per-replica footprint from the last stats poll,
+	// read by the -capacity-check scale-up gate. Poll goroutine only.
+	capacity capacityEstimate
+
+	// goodPolls counts successful polls toward -min-samples; a config
+	// reload starts the count over. Poll goroutine only.
+	goodPolls int
+
+	// expectReplicas is the count the autoscaler last saw or set, for
+	// spotting manual scaling (0 means unknown); manualUntil holds scale
+	// actions after one. Poll goroutine only.
+	expectReplicas int
+	manualUntil    time.Time
+
+	// knownHashes is the compose config-hash set from the previous
+	// poll; blackoutUntil holds scale actions after a hash we haven't
+	// seen appears. Poll goroutine only.
+	knownHashes   map[string]bool
+	blackoutUntil time.Time
+
+	// -spike-filter smoothers, one per signal; nil filters nothing.
+	// Poll goroutine only.
+	spikeCPU    *spikeFilter
+	spikeMem    *spikeFilter
+	spikeMetric *spikeFilter
+
+	// lastAccrual is when replica-hours were last added up.
+	// Poll goroutine only.
+	lastAccrual time.Time
+
+	mu            sync.Mutex
+	paused        bool
+	lastScale     time.Time
+	lowStreak     int
+	highStreak    int
+	lastDir       int // +1 up, -1 down, 0 no scale yet
+	cooldownBoost int // flip-flop backoff exponent
+	replicas      int
+	warnedNoCount bool
+	labelSig      string           // last applied goscale.* label set
+	deployHold    string           // non-empty while a deploy is recreating containers
+	unhealthyPct  float64          // share of replicas failing their healthcheck, last poll
+	crashStreak   int              // consecutive polls with a restart-cycling replica
+	crashLoop     bool             // latched once crashStreak reaches the limit
+	atMax         bool             // latched while high load is stuck at max replicas
+	lastBeat      time.Time        // last turn of the poll loop, for the systemd watchdog
+	inject        *injection       // nil when no override window is armed
+	recs          []replicaRec     // per-poll replica recommendations, for stabilization
+	events        []scaleEvent     // recent scale events, newest last
+	pendingDec    *pendingDecision // decision queued in approval mode
+}
+
+func newAutoscaler(cfg *config, docker *dockerClient) *autoscaler {
+	a := &autoscaler{
+		cfg:    cfg,
+		docker: docker,
+		guard:  hostGuard{loadMax: cfg.hostLoadMax, memFreeMin: cfg.hostMemFreeMin},
+		now:    time.Now,
+		reload: make(chan struct{}, 1),
+		poke:   make(chan struct{}, 1),
+		breaker: engine.Breaker{
+			Threshold:  cfg.breakerFailures,
+			ProbeEvery: cfg.breakerProbe,
+		},
+		lastBeat: time.Now(),
+	}
+	switch {
+	case cfg.providerName != "":
+		// Validated in parseFlags; an exec plugin disappearing from PATH
+		// between then and now still fails here.
+		src, err := metrics.New(cfg.providerName, cfg.providerSpec)
+		if err != nil {
+			log.Printf("✗ -metric-provider: %v", err)
+		} else {
+			a.metric = src
+		}
+	case cfg.metricCmd != "":
+		a.metric = metrics.NewExec(cfg.metricCmd)
+	case cfg.metricURL != "":
+		a.metric = metrics.NewHTTP(cfg.metricURL, cfg.metricJSONPath)
+	case cfg.redisAddr != "":
+		a.metric = newRedisMetric(cfg.redisAddr, cfg.redisPassword, cfg.redisKey)
+	case cfg.rabbitURL != "":
+		a.metric = newRabbitMetric(cfg.rabbitURL, cfg.rabbitVhost, cfg.rabbitQueue)
+	case cfg.nginxStatusURL != "":
+		a.metric = newNginxStubMetric(cfg.nginxStatusURL)
+	case cfg.traefikMetricsURL != "":
+		a.metric = newRateMetric(newTraefikMetric(cfg.traefikMetricsURL, cfg.traefikService))
+	case cfg.logPatternRE != nil:
+		a.metric = newLogPatternMetric(docker, cfg, cfg.logPatternRE)
+	}
+	if a.metric != nil && cfg.metricInterval > 0 {
+		a.metricPoll = &metricPoll{}
+	}
+	if cfg.spikeWindow > 0 {
+		a.spikeCPU = newSpikeFilter("cpu", cfg.spikeWindow)
+		a.spikeMem = newSpikeFilter("mem", cfg.spikeWindow)
+		a.spikeMetric = newSpikeFilter("metric", cfg.spikeWindow)
+	}
+	if cfg.influxURL != "" {
+		a.influx = newInfluxExporter(cfg.influxURL, cfg.influxToken)
+	}
+	if cfg.controller == "pid" {
+		a.pid = engine.NewPID(cfg.pidKp, cfg.pidKi, cfg.pidTarget)
+	}
+	if cfg.webhookURL != "" {
+		a.webhook = newDecisionWebhook(cfg.webhookURL, cfg.webhookTimeout)
+	}
+	if cfg.alertmanagerURL != "" {
+		a.alerts = newAlertmanagerClient(cfg.alertmanagerURL)
+	}
+	return a
+}
+
+func (a *autoscaler) run(ctx context.Context) {
+	log.Printf("watching %s/%s every %s (replicas %d-%d)",
+		a.cfg.project, a.cfg.service, a.cfg.interval, a.cfg.minReplicas, a.cfg.maxReplicas)
+
+	// A random initial phase de-synchronizes loops that share a daemon:
+	// the tickers keep their interval, so the offset persists and the
+	// stats bursts stop landing in unison.
+	if a.cfg.pollJitter > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(rand.Int63n(int64(a.cfg.pollJitter)))):
+		}
+	}
+
+	if a.metricPoll != nil {
+		go a.pollMetric(ctx)
+	}
+
+	ticker := time.NewTicker(a.cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		a.beat()
+		select {
+		case <-ctx.Done():
+			log.Printf("shutting down")
+			return
+		case <-a.reload:
+			a.applyConfigReload()
+		case <-a.poke:
+			log.Printf("i evaluating outside the poll interval")
+			a.evaluate(ctx)
+		case <-ticker.C:
+			a.evaluate(ctx)
+		}
+	}
+}
+
+// beat marks the poll loop as alive for the systemd watchdog.
+func (a *autoscaler) beat() {
+	a.mu.Lock()
+	a.lastBeat = time.Now()
+	a.mu.Unlock()
+}
+
+func (a *autoscaler) lastBeatTime() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lastBeat
+}
+
+// evaluate takes one stats poll across the service's containers and
+// applies the threshold policy.
+func (a *autoscaler) evaluate(ctx context.Context) {
+	if a.nomad != nil {
+		a.evaluateNomad(ctx)
+		return
+	}
+
+	// With the circuit open, skip the poll entirely except for the
+	// periodic probe; the daemon is down and more calls won't fix it.
+	if !a.breaker.Allow(a.now()) {
+		return
+	}
+	defer self.timeEval(time.Now())
+
+	// An operator-approved decision executes here rather than in the
+	// API handler, so every scale action stays on the poll goroutine.
+	if e, ok := a.approvedDecision(); ok {
+		a.execute(ctx, e)
+		return
+	}
+
+	containers, err := a.docker.getServiceContainers(ctx, a.cfg, a.cfg.service)
+	if err != nil {
+		log.Printf("✗ %v", err)
+		self.countError("docker")
+		a.dockerFailure()
+		return
+	}
+	if len(containers) == 0 {
+		log.Printf("✗ no containers found for service %q", a.cfg.service)
+		return
+	}
+	a.applyLabelOverrides(containers)
+	a.unhealthyPct = unhealthyPct(containers)
+	a.trackRestartLoop(containers)
+	a.trackConfigHash(containers, a.now())
+	hold := deployInProgress(containers, a.now())
+	if hold == "" {
+		// The roll itself has settled; the blackout keeps the hold up a
+		// while longer so a half-updated fleet isn't resized right away.
+		hold = a.blackoutReason(a.now())
+	}
+	if hold != "" && a.deployHold == "" {
+		log.Printf("i deploy detected, holding scale decisions: %s", hold)
+	}
+	a.deployHold = hold
+
+	var cpuSum, memSum float64
+	var sampled int
+	var points []containerPoint
+	for _, c := range containers {
+		s, cached := a.statsCache.get(c.ID)
+		var err error
+		if !cached {
+			fetchStart := time.Now()
+			s, err = a.docker.containerStats(ctx, c.ID)
+			self.timeStats(fetchStart)
+			if err == nil {
+				a.statsCache.put(c.ID, s)
+			}
+		}
+		if err != nil {
+			if err != errIncompleteSample {
+				log.Printf("✗ stats for %s: %v", shortID(c.ID), err)
+				self.countError("stats")
+			}
+			continue
+		}
+		if s.CPUEstimated && !a.warnedNoCount {
+			log.Printf("i stats schema has no CPU count; estimating with host CPUs")
+			a.warnedNoCount = true
+		}
+		cpuSum += s.CPUPercent
+		memSum += s.memPct(a.cfg.memAccounting)
+		sampled++
+		points = append(points, containerPoint{id: c.ID, sample: s})
+	}
+	if sampled == 0 {
+		log.Printf("✗ no usable stats samples this poll")
+		a.dockerFailure()
+		return
+	}
+	a.dockerSuccess()
+	if a.cfg.capacityCheck {
+		a.updateCapacityEstimate(points)
+	}
+
+	// Filtering happens before the injection override so drills see
+	// exactly the values they injected.
+	avgCPU := a.spikeCPU.filter(cpuSum / float64(sampled))
+	avgMem := a.spikeMem.filter(memSum / float64(sampled))
+	inject := a.takeInjection()
+	if inject != nil {
+		if inject.hasCPU {
+			avgCPU = inject.cpu
+		}
+		if inject.hasMem {
+			avgMem = inject.mem
+		}
+		log.Printf("i injected metrics active, %d polls left", inject.polls-1)
+	}
+	replicas := countReplicas(a.cfg, containers)
+	if replicas == 0 {
+		log.Printf("✗ no containers in a counted state for service %q", a.cfg.service)
+		return
+	}
+	a.trackManualScale(replicas)
+	a.setReplicas(replicas)
+	a.trackReplicaHours(replicas)
+
+	if a.cfg.verbose {
+		log.Printf("i %s: %d replicas, cpu %.1f%%, mem %.1f%%", a.cfg.service, replicas, avgCPU, avgMem)
+	}
+	a.statsd.gauge(a.cfg.service+".cpu", avgCPU)
+	a.statsd.gauge(a.cfg.service+".mem", avgMem)
+	a.statsd.gauge(a.cfg.service+".replicas", float64(replicas))
+	if a.influx != nil {
+		go a.influx.export(a.cfg.project, a.cfg.service, points, avgCPU, avgMem, replicas)
+	}
+
+	metricV, metricErr := a.collectMetric(ctx, inject)
+	if metricV != nil && (inject == nil || !inject.hasMetric) {
+		v := a.spikeMetric.filter(*metricV)
+		metricV = &v
+	}
+
+	a.emitHeartbeat(replicas, avgCPU, avgMem, metricV)
+
+	calm := a.decide(ctx, replicas, avgCPU, avgMem, metricV, metricErr)
+
+	// Image rolls piggyback on calm polls: never under high load and
+	// never inside the cooldown after a scale action, so a roll and a
+	// scaling decision can't fight over the same containers.
+	if a.cfg.refreshImages && calm && !a.isPaused() && a.deployHold == "" && a.now().Sub(a.lastScale) >= a.cfg.cooldown {
+		a.maybeRefreshImages(ctx, containers)
+	}
+
+	remaining := a.effectiveCooldown() - a.now().Sub(a.lastScale)
+	if remaining < 0 {
+		remaining = 0
+	}
+	a.stream.publish("sample", tuiSample{
+		Time: a.now(), CPU: avgCPU, Mem: avgMem,
+		Replicas: a.currentReplicas(), Cooldown: remaining, Paused: a.isPaused(),
+	})
+}
+
+// decide applies the scaling policy to one poll's averaged metrics:
+// scheduled bounds, the PID or threshold branch, and the streak
+// counters. metric carries the custom metric sample when one was taken;
+// metricErr marks a failed sample, which blocks scale-downs. It reports
+// whether the poll ended calm — no high load, no early policy branch —
+// which gates opportunistic image rolls. The simulator drives decide
+// directly with trace samples and a fake clock.
+func (a *autoscaler) decide(ctx context.Context, replicas int, avgCPU, avgMem float64, metric *float64, metricErr bool) bool {
+	if a.history != nil {
+		a.history.record(a.now(), math.Max(avgCPU, avgMem))
+		a.history.save()
+	}
+
+	// -min-samples holds every scale action until enough polls have
+	// sampled successfully. Unlike the streak counters this gates the
+	// scheduled bounds and unhealthy branches too: right after startup
+	// or a reload nothing has been cross-checked yet.
+	if a.goodPolls < a.cfg.minSamples {
+		a.goodPolls++
+		log.Printf("i warming up, %d/%d samples before scaling", a.goodPolls, a.cfg.minSamples)
+		return false
+	}
+
+	// Schedule rules guarantee capacity regardless of instantaneous
+	// load: enforce the current bounds before any metric-based decision.
+	minB, maxB := effectiveBounds(a.cfg, a.now())
+	if replicas < minB {
+		log.Printf("i scheduled minimum is %d, raising from %d", minB, replicas)
+		a.scale(ctx, replicas, minB, avgCPU, avgMem)
+		return false
+	}
+	if replicas > maxB {
+		log.Printf("i scheduled maximum is %d, lowering from %d", maxB, replicas)
+		a.scale(ctx, replicas, maxB, avgCPU, avgMem)
+		return false
+	}
+
+	// Extreme load is an outage in progress, and outage mitigation
+	// beats gradual ramping: jump straight to the maximum and let the
+	// normal policy walk back down once things calm.
+	if a.cfg.burstPct > 0 && math.Max(avgCPU, avgMem) >= a.cfg.burstPct && replicas < maxB {
+		log.Printf("i load %.1f%% is past the burst threshold %g%%, scaling straight to max %d",
+			math.Max(avgCPU, avgMem), a.cfg.burstPct, maxB)
+		a.lowStreak, a.highStreak = 0, 0
+		a.burstScale(ctx, replicas, maxB, avgCPU, avgMem)
+		return false
+	}
+
+	// A mostly-unhealthy fleet needs capacity even when it's idle: the
+	// sick replicas aren't taking load, so CPU/mem won't show the
+	// pressure. evaluate sets the fraction from the listing; the
+	// simulator has no containers and leaves it zero.
+	if a.cfg.unhealthyUpPct > 0 && a.unhealthyPct > a.cfg.unhealthyUpPct {
+		log.Printf("i %.0f%% of replicas are unhealthy (limit %g%%), scaling up", a.unhealthyPct, a.cfg.unhealthyUpPct)
+		a.scale(ctx, replicas, replicas+a.cfg.stepSize(replicas, true), avgCPU, avgMem)
+		return false
+	}
+
+	if a.pid != nil {
+		// PID mode tracks the setpoint on CPU/mem pressure alone; the
+		// step tiers and custom-metric thresholds don't apply.
+		target := a.pid.Next(math.Max(avgCPU, avgMem), replicas)
+		if a.cfg.verbose && target != replicas {
+			log.Printf("i pid wants %d -> %d replicas", replicas, target)
+		}
+		if target != replicas && a.scale(ctx, replicas, target, avgCPU, avgMem) {
+			a.pid.Reset()
+		}
+		return false
+	}
+
+	high := avgCPU >= a.cfg.cpuUp || avgMem >= a.cfg.memUp
+	low := avgCPU <= a.cfg.effectiveDown(a.cfg.cpuUp, a.cfg.cpuDown) &&
+		avgMem <= a.cfg.effectiveDown(a.cfg.memUp, a.cfg.memDown)
+	if metricErr {
+		// Without the custom signal we can still scale up on CPU/mem,
+		// but never down.
+		low = false
+	} else if metric != nil && a.cfg.metricUp > 0 {
+		// metric-up left at zero disables the thresholds, like the other
+		// optional gates; the source can still feed -rule-up/-rule-down.
+		// Without the guard any non-negative sample would read as high
+		// on every poll.
+		high = high || *metric >= a.cfg.metricUp
+		low = low && *metric <= a.cfg.effectiveDown(a.cfg.metricUp, a.cfg.metricDown)
+	}
+
+	// Expression rules, when given, replace the threshold verdicts
+	// outright: high and low are whatever -rule-up and -rule-down say. A
+	// rule that can't be evaluated this poll — it references metric and
+	// the sample failed, say — counts as not met, and a failed sample
+	// still blocks scale-downs either way.
+	if a.cfg.ruleUp != nil || a.cfg.ruleDown != nil {
+		vars := map[string]float64{
+			"cpu":       avgCPU,
+			"mem":       avgMem,
+			"replicas":  float64(replicas),
+			"unhealthy": a.unhealthyPct,
+			"min":       float64(minB),
+			"max":       float64(maxB),
+		}
+		if metric != nil && !metricErr {
+			vars["metric"] = *metric
+		}
+		if a.cfg.ruleUp != nil {
+			v, err := a.cfg.ruleUp.Eval(vars)
+			if err != nil && a.cfg.verbose {
+				log.Printf("i %v", err)
+			}
+			high = v
+		}
+		if a.cfg.ruleDown != nil {
+			v, err := a.cfg.ruleDown.Eval(vars)
+			if err != nil && a.cfg.verbose {
+				log.Printf("i %v", err)
+			}
+			low = v && !metricErr
+		}
+	}
+
+	// A forecast peak in the lead window both holds scale-downs and
+	// pre-scales, so capacity is there before the recurring spike.
+	peakAhead := false
+	if a.history != nil {
+		if fc, ok := a.history.forecast(a.now().Add(a.cfg.forecastLead)); ok &&
+			fc >= math.Min(a.cfg.cpuUp, a.cfg.memUp) {
+			peakAhead = true
+		}
+	}
+
+	switch {
+	case high:
+		a.lowStreak = 0
+		a.highStreak++
+		if a.highStreak < a.cfg.scaleUpChecks {
+			if a.cfg.verbose {
+				log.Printf("i high load %d/%d checks", a.highStreak, a.cfg.scaleUpChecks)
+			}
+			return false
+		}
+		to := replicas + a.cfg.stepSize(replicas, true)
+		if t, ok := a.cfg.steps.target(math.Max(avgCPU, avgMem), replicas, maxB); ok {
+			to = t
+		}
+		if a.scale(ctx, replicas, to, avgCPU, avgMem) {
+			a.highStreak = 0
+		}
+	case peakAhead:
+		a.lowStreak, a.highStreak = 0, 0
+		if replicas < maxB {
+			log.Printf("i pre-scaling ahead of expected load peak")
+			a.scale(ctx, replicas, replicas+1, avgCPU, avgMem)
+		}
+	case low:
+		a.highStreak = 0
+		a.lowStreak++
+		if a.lowStreak >= a.cfg.scaleDownChecks {
+			if a.scale(ctx, replicas, replicas-a.cfg.stepSize(replicas, false), avgCPU, avgMem) {
+				a.lowStreak = 0
+			}
+		} else if a.cfg.verbose {
+			log.Printf("i low load %d/%d checks", a.lowStreak, a.cfg.scaleDownChecks)
+		}
+	default:
+		a.lowStreak, a.highStreak = 0, 0
+		// A calm poll recommends the current count, which is what the
+		// stabilization windows read back when softening a change.
+		a.recordRecommendation(a.now(), replicas)
+	}
+
+	// Over threshold with nowhere left to grow is an operator problem;
+	// more polls won't fix it, so it goes to Alertmanager.
+	if stuck := high && replicas >= maxB; stuck != a.atMax {
+		a.atMax = stuck
+		a.alert("GoScaleAtMaxReplicas",
+			fmt.Sprintf("%s/%s is over threshold at its max of %d replicas", a.cfg.project, a.cfg.service, maxB), stuck)
+	}
+	return !high
+}
+
+// scale clamps the target, honors the cooldown and runs the compose
+// command. It reports whether a scale action was actually taken.
+func (a *autoscaler) scale(ctx context.Context, from, to int, cpu, mem float64) bool {
+	minR, maxR := effectiveBounds(a.cfg, a.now())
+	if to > maxR {
+		to = maxR
+	}
+	if to < minR {
+		to = minR
+	}
+	if to == from {
+		return false
+	}
+	if to = a.applyBehavior(from, to); to == from {
+		return false
+	}
+	if reason := a.suppressReason(a.now()); reason != "" {
+		log.Printf("i would scale %s %d -> %d, suppressed: %s", a.cfg.service, from, to, reason)
+		return false
+	}
+	if to > from && a.guard.enabled() {
+		if reason := a.guard.check(); reason != "" {
+			log.Printf("i holding scale-up: %s", reason)
+			return false
+		}
+	}
+	if to > from && a.cfg.capacityCheck {
+		if to = a.capacityClamp(from, to); to == from {
+			return false
+		}
+	}
+	if to = a.costClamp(from, to); to == from {
+		return false
+	}
+	if to < from && a.crashLoop && a.cfg.crashLoopHoldDowns {
+		log.Printf("i replicas are restart-cycling, holding scale-down at %d", from)
+		return false
+	}
+	if wait := a.effectiveCooldown() - a.now().Sub(a.lastScale); wait > 0 {
+		if a.cfg.verbose {
+			log.Printf("i in cooldown for %s, holding at %d replicas", wait.Round(time.Second), from)
+		}
+		return false
+	}
+	if reason := a.rateLimitReason(a.now(), from, to); reason != "" {
+		log.Printf("i rate limited, holding at %d replicas: %s", from, reason)
+		return false
+	}
+
+	// Past the gates: this attempt is a scale event with a correlation
+	// ID shared by its log lines, webhook call and API record.
+	event := scaleEvent{ID: newEventID(), Time: a.now(), From: from, To: to, CPU: cpu, Mem: mem}
+
+	if a.webhook != nil {
+		modified, allowed := a.webhook.review(ctx, decisionRequest{
+			EventID: event.ID,
+			Project: a.cfg.project, Service: a.cfg.service,
+			From: from, To: to, CPU: cpu, Mem: mem,
+			DecideAt: a.now().Format(time.RFC3339),
+		})
+		if !allowed {
+			event.Outcome = "vetoed"
+			a.recordEvent(event)
+			return false
+		}
+		if modified != to {
+			// A modified target still respects the replica bounds.
+			to = modified
+			if to > maxR {
+				to = maxR
+			}
+			if to < minR {
+				to = minR
+			}
+			if to == from {
+				return false
+			}
+		}
+	}
+
+	event.To = to
+	if a.cfg.approvalMode {
+		a.queueDecision(event)
+		return false
+	}
+	return a.execute(ctx, event)
+}
+
+// burstScale jumps straight to the scheduled maximum. Unlike scale it
+// skips the step tiers, stabilization, behavior policies, cooldown,
+// rate limits and the decision webhook — during an outage, waiting on
+// any of those costs capacity. The operator gates still hold: a pause
+// or maintenance window outranks load, and so does the host guard.
+func (a *autoscaler) burstScale(ctx context.Context, from, to int, cpu, mem float64) bool {
+	if reason := a.suppressReason(a.now()); reason != "" {
+		log.Printf("i would burst %s %d -> %d, suppressed: %s", a.cfg.service, from, to, reason)
+		return false
+	}
+	if a.guard.enabled() {
+		if reason := a.guard.check(); reason != "" {
+			log.Printf("i holding burst scale-up: %s", reason)
+			return false
+		}
+	}
+	// The budget is a ceiling the operator set; even an outage doesn't
+	// overdraw it.
+	if to = a.costClamp(from, to); to == from {
+		return false
+	}
+	event := scaleEvent{ID: newEventID(), Time: a.now(), From: from, To: to, CPU: cpu, Mem: mem}
+	if a.cfg.approvalMode {
+		a.queueDecision(event)
+		return false
+	}
+	return a.execute(ctx, event)
+}
+
+// execute carries out a decided scale event: hooks, draining, the
+// compose command and the outcome record. Callers are the poll loop,
+// either directly or via an operator-approved queued decision.
+func (a *autoscaler) execute(ctx context.Context, event scaleEvent) bool {
+	from, to := event.From, event.To
+
+	// The pre-scale hook runs once the action is certain (post-webhook,
+	// pre-drain), so cache warming starts before replicas change. A
+	// failing pre hook aborts the action: the hook is the operator's
+	// last word.
+	if a.cfg.preScaleHook != "" {
+		if err := runHook(a.cfg, a.cfg.preScaleHook, event); err != nil {
+			log.Printf("✗ [%s] pre-scale hook: %v", event.ID, err)
+			event.Outcome = "hook-failed"
+			a.recordEvent(event)
+			return false
+		}
+	}
+
+	if to < from && a.drain != nil {
+		// Compose removes the highest-numbered replicas, so those are
+		// the backends to drain before the containers go away.
+		for i := from; i > to; i-- {
+			waitDrained(ctx, a.drain, a.drainServerName(i), a.cfg.drainTimeout)
+		}
+	}
+
+	log.Printf("[%s] scaling %s: %d -> %d (cpu %.1f%%, mem %.1f%%)", event.ID, a.cfg.service, from, to, event.CPU, event.Mem)
+	if err := a.applyScale(ctx, event); err != nil {
+		log.Printf("✗ [%s] %v", event.ID, err)
+		event.Outcome = "failed"
+		a.recordEvent(event)
+		a.dockerFailure()
+		return false
+	}
+	a.dockerSuccess()
+	if a.cfg.verifyTimeout > 0 && !a.cfg.dryRun && !a.verifyScale(ctx, event) {
+		// The command was accepted but the replicas never got there.
+		// Cooldown still starts: hammering a cluster that can't place
+		// containers won't help.
+		event.Outcome = "unverified"
+		a.recordEvent(event)
+		a.lastScale = a.now()
+		// Wherever the count landed, it wasn't our doing or an
+		// operator's; don't let the next poll call it a manual scale.
+		a.expectReplicas = 0
+		if a.cfg.verifyRollback {
+			a.rollbackScale(ctx, event)
+		}
+		return false
+	}
+	event.Outcome = "applied"
+	a.recordEvent(event)
+	a.logProjectedCost(event)
+	a.expectReplicas = to
+	a.adjustCooldown(a.now(), to > from)
+	a.lastScale = a.now()
+	direction := "up"
+	if to < from {
+		direction = "down"
+	}
+	a.statsd.count(a.cfg.service+".scale."+direction, 1)
+	a.statsd.gauge(a.cfg.service+".target", float64(to))
+	a.scaleLinked(ctx, event.ID, to)
+	if a.cfg.postScaleHook != "" {
+		if err := runHook(a.cfg, a.cfg.postScaleHook, event); err != nil {
+			// The scale already happened; a broken post hook (LB update
+			// etc.) is the operator's problem to notice, not a rollback.
+			log.Printf("✗ [%s] post-scale hook: %v", event.ID, err)
+		}
+	}
+	return true
+}
+
+// drainServerName maps a compose replica number to the LB server name,
+// via the -drain-server template or the compose container name.
+func (a *autoscaler) drainServerName(i int) string {
+	if a.cfg.drainServer != "" {
+		return fmt.Sprintf(a.cfg.drainServer, i)
+	}
+	return fmt.Sprintf("%s-%s-%d", a.cfg.project, a.cfg.service, i)
+}
+
+func (a *autoscaler) isPaused() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.paused
+}
+
+func (a *autoscaler) setPaused(p bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.paused = p
+}
+
+// togglePaused flips the paused state and returns the new value.
+func (a *autoscaler) togglePaused() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.paused = !a.paused
+	return a.paused
+}
+
+// requestEvaluation asks the poll loop to evaluate now instead of
+// waiting out the interval, e.g. right after resuming from a pause.
+func (a *autoscaler) requestEvaluation() {
+	select {
+	case a.poke <- struct{}{}:
+	default: // one is already queued
+	}
+}
+
+func (a *autoscaler) setReplicas(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.replicas = n
+}
+
+func (a *autoscaler) currentReplicas() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.replicas
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// The backtest subcommand replays recorded production metrics through
+// a proposed configuration:
+//
+//	go-scale backtest -input recorded.jsonl -config new.conf
+//
+// The input is the JSONL stream -heartbeat-format json produced while
+// the old settings were live; -config (or plain flags) carries the
+// candidate thresholds. Nothing touches docker — the replay reuses the
+// simulator's fake clock — and instead of a timeline the output is a
+// summary: how many scale events the new settings would have produced,
+// the peak replica count, and how long the fleet would have sat over
+// threshold.
+
+// parseBacktestInput reads heartbeat JSON lines into a sim trace.
+// Offsets come from the recorded timestamps, relative to the first
+// kept line; lines recorded for a different service are skipped so a
+// multi-target stream can be backtested one service at a time.
+func parseBacktestInput(r io.Reader, service string) ([]simPoint, error) {
+	var points []simPoint
+	var base time.Time
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var hb heartbeat
+		if err := json.Unmarshal([]byte(line), &hb); err != nil {
+			return nil, fmt.Errorf("input line %d: %v", lineNo, err)
+		}
+		if hb.Service != "" && service != "" && hb.Service != service {
+			continue
+		}
+		if base.IsZero() {
+			base = hb.Time
+		}
+		if hb.Time.Before(base) {
+			return nil, fmt.Errorf("input line %d: timestamp %s goes backwards", lineNo, hb.Time.Format(time.RFC3339))
+		}
+		points = append(points, simPoint{at: hb.Time.Sub(base), cpu: hb.CPU, mem: hb.Mem, metric: hb.Metric})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// runBacktest implements the backtest subcommand: replay the recorded
+// stream through the current flag/config settings and summarize.
+func runBacktest(cfg *config) error {
+	if cfg.backtestInput == "" {
+		return fmt.Errorf("backtest needs -input with a recorded heartbeat JSONL file")
+	}
+	f, err := os.Open(cfg.backtestInput)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	points, err := parseBacktestInput(f, cfg.service)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return fmt.Errorf("%s: no usable samples for service %q", cfg.backtestInput, cfg.service)
+	}
+
+	results := simulate(cfg, points)
+	summarizeBacktest(os.Stdout, cfg, results)
+	return nil
+}
+
+// summarizeBacktest prints what the replay would have done. Each
+// point's weight is the gap to the next one; the last point carries no
+// duration since nothing is known past it.
+func summarizeBacktest(w io.Writer, cfg *config, results []simResult) {
+	var ups, downs, peak int
+	var over, total time.Duration
+	for i, r := range results {
+		if r.event != nil {
+			if r.event.To > r.event.From {
+				ups++
+			} else {
+				downs++
+			}
+		}
+		if r.replicas > peak {
+			peak = r.replicas
+		}
+		if i+1 < len(results) {
+			span := results[i+1].point.at - r.point.at
+			total += span
+			if r.point.cpu >= cfg.cpuUp || r.point.mem >= cfg.memUp {
+				over += span
+			}
+		}
+	}
+	last := results[len(results)-1]
+
+	fmt.Fprintf(w, "backtest %s/%s: %d samples over %s, starting at %d replicas\n",
+		cfg.project, cfg.service, len(results), last.point.at.Round(time.Second), cfg.minReplicas)
+	fmt.Fprintf(w, "%d scale events (%d up, %d down)\n", ups+downs, ups, downs)
+	fmt.Fprintf(w, "peak replicas %d, final %d\n", peak, last.replicas)
+	pct := 0.0
+	if total > 0 {
+		pct = 100 * float64(over) / float64(total)
+	}
+	fmt.Fprintf(w, "time over threshold %s of %s (%.1f%%)\n", over.Round(time.Second), total.Round(time.Second), pct)
+}
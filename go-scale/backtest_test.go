@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBacktestInput(t *testing.T) {
+	input := `
+{"time":"2024-03-01T12:00:00Z","project":"demo","service":"web","replicas":2,"cpu":10,"mem":20}
+{"time":"2024-03-01T12:00:30Z","project":"demo","service":"worker","replicas":1,"cpu":99,"mem":99}
+{"time":"2024-03-01T12:01:00Z","project":"demo","service":"web","replicas":2,"cpu":85,"mem":30,"metric":12.5}
+`
+	points, err := parseBacktestInput(strings.NewReader(input), "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("parsed %d points, want 2 (worker line skipped)", len(points))
+	}
+	if points[0].at != 0 || points[0].cpu != 10 {
+		t.Errorf("first point = %+v", points[0])
+	}
+	if points[1].at != time.Minute || points[1].cpu != 85 {
+		t.Errorf("second point = %+v", points[1])
+	}
+	if points[1].metric == nil || *points[1].metric != 12.5 {
+		t.Errorf("metric not carried over: %+v", points[1])
+	}
+}
+
+func TestParseBacktestInputBackwards(t *testing.T) {
+	input := `{"time":"2024-03-01T12:01:00Z","service":"web","cpu":10,"mem":20}
+{"time":"2024-03-01T12:00:00Z","service":"web","cpu":10,"mem":20}
+`
+	if _, err := parseBacktestInput(strings.NewReader(input), "web"); err == nil {
+		t.Fatal("out-of-order input accepted")
+	}
+}
+
+func TestParseBacktestInputBadLine(t *testing.T) {
+	if _, err := parseBacktestInput(strings.NewReader("not json\n"), "web"); err == nil {
+		t.Fatal("garbage input accepted")
+	}
+}
+
+func TestSummarizeBacktest(t *testing.T) {
+	cfg := &config{project: "demo", service: "web", minReplicas: 2, cpuUp: 80, memUp: 80}
+	results := []simResult{
+		{point: simPoint{at: 0, cpu: 90, mem: 10}, replicas: 3, event: &scaleEvent{From: 2, To: 3}},
+		{point: simPoint{at: time.Minute, cpu: 90, mem: 10}, replicas: 3},
+		{point: simPoint{at: 2 * time.Minute, cpu: 10, mem: 10}, replicas: 2, event: &scaleEvent{From: 3, To: 2}},
+		{point: simPoint{at: 3 * time.Minute, cpu: 10, mem: 10}, replicas: 2},
+	}
+	var buf bytes.Buffer
+	summarizeBacktest(&buf, cfg, results)
+	out := buf.String()
+	for _, want := range []string{
+		"2 scale events (1 up, 1 down)",
+		"peak replicas 3, final 2",
+		"time over threshold 2m0s of 3m0s (66.7%)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
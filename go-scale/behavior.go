@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HPA-style behavior configuration: per-direction stabilization
+// windows and change-rate policies, modeled on the Kubernetes
+// HorizontalPodAutoscaler's behavior block. A stabilization window
+// holds a change until every recommendation within the window agrees
+// with it; a policy caps how many replicas may change per period.
+// Both are independent of the cooldown and the oscillation rate
+// limits, which keep their own semantics.
+
+// scalePolicy caps replica change in one direction: at most value
+// replicas (or value percent of the current count) per period.
+type scalePolicy struct {
+	value   int
+	percent bool
+	period  time.Duration
+}
+
+// policyFlag collects repeatable -up-policy/-down-policy flags.
+// Syntax: "4/1m" allows 4 replicas per minute, "50%/1m" allows half
+// the current count per minute. With several policies the most
+// permissive wins, matching the HPA's default selectPolicy.
+type policyFlag []scalePolicy
+
+func (f *policyFlag) String() string {
+	var parts []string
+	for _, p := range *f {
+		unit := ""
+		if p.percent {
+			unit = "%"
+		}
+		parts = append(parts, fmt.Sprintf("%d%s/%s", p.value, unit, p.period))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *policyFlag) Set(v string) error {
+	amount, period, ok := strings.Cut(v, "/")
+	if !ok {
+		return fmt.Errorf("expected amount/period, e.g. 4/1m or 50%%/1m, got %q", v)
+	}
+	p := scalePolicy{percent: strings.HasSuffix(amount, "%")}
+	n, err := strconv.Atoi(strings.TrimSuffix(amount, "%"))
+	if err != nil || n <= 0 {
+		return fmt.Errorf("bad amount in %q", v)
+	}
+	p.value = n
+	if p.period, err = time.ParseDuration(period); err != nil || p.period <= 0 {
+		return fmt.Errorf("bad period in %q", v)
+	}
+	*f = append(*f, p)
+	return nil
+}
+
+// replicaRec is one poll's replica recommendation, read back by the
+// stabilization windows.
+type replicaRec struct {
+	t time.Time
+	n int
+}
+
+// recordRecommendation notes what a poll wanted the replica count to
+// be (the current count on calm polls), pruning entries older than
+// the longest stabilization window.
+func (a *autoscaler) recordRecommendation(now time.Time, n int) {
+	keep := a.cfg.upStabilization
+	if a.cfg.downStabilization > keep {
+		keep = a.cfg.downStabilization
+	}
+	if keep <= 0 {
+		return
+	}
+	recs := a.recs[:0]
+	for _, r := range a.recs {
+		if now.Sub(r.t) <= keep {
+			recs = append(recs, r)
+		}
+	}
+	a.recs = append(recs, replicaRec{t: now, n: n})
+}
+
+// stabilizedTarget applies the direction's stabilization window to a
+// proposed target: a scale-down won't go below any recommendation in
+// the window, a scale-up won't go above the lowest one. The windows
+// default to 0 (off); the current proposal was recorded already, so
+// an empty window is a no-op.
+func (a *autoscaler) stabilizedTarget(now time.Time, from, to int) int {
+	window := a.cfg.upStabilization
+	if to < from {
+		window = a.cfg.downStabilization
+	}
+	if window <= 0 {
+		return to
+	}
+	adj := to
+	for _, r := range a.recs {
+		if now.Sub(r.t) > window {
+			continue
+		}
+		if to < from && r.n > adj {
+			adj = r.n
+		}
+		if to > from && r.n < adj {
+			adj = r.n
+		}
+	}
+	// The window only softens the change, it never reverses it.
+	if to < from && adj > from {
+		adj = from
+	}
+	if to > from && adj < from {
+		adj = from
+	}
+	return adj
+}
+
+// policyAllowed reports how many replicas may still change in the
+// proposal's direction under the direction's policies, given what was
+// already applied within each policy's period. The second result is
+// false when no policy constrains this direction.
+func (a *autoscaler) policyAllowed(now time.Time, from, to int) (int, bool) {
+	policies := a.cfg.upPolicies
+	if to < from {
+		policies = a.cfg.downPolicies
+	}
+	if len(policies) == 0 {
+		return 0, false
+	}
+	best := 0
+	for _, p := range policies {
+		limit := p.value
+		if p.percent {
+			limit = int(math.Ceil(float64(from) * float64(p.value) / 100))
+		}
+		if allowed := limit - a.changedWithin(now, p.period, to > from); allowed > best {
+			best = allowed
+		}
+	}
+	return best, true
+}
+
+// changedWithin sums the replicas applied in one direction over the
+// trailing period, from the event ring.
+func (a *autoscaler) changedWithin(now time.Time, period time.Duration, up bool) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	changed := 0
+	for _, e := range a.events {
+		if e.Outcome != "applied" || now.Sub(e.Time) > period {
+			continue
+		}
+		if up && e.To > e.From {
+			changed += e.To - e.From
+		}
+		if !up && e.To < e.From {
+			changed += e.From - e.To
+		}
+	}
+	return changed
+}
+
+// applyBehavior runs both behavior mechanisms against a proposed
+// target and returns the adjusted one; from means "no change survives
+// the behavior rules this poll".
+func (a *autoscaler) applyBehavior(from, to int) int {
+	now := a.now()
+	a.recordRecommendation(now, to)
+	if st := a.stabilizedTarget(now, from, to); st != to {
+		if a.cfg.verbose {
+			log.Printf("i stabilization window softens %d -> %d to %d", from, to, st)
+		}
+		to = st
+	}
+	if to == from {
+		return from
+	}
+	allowed, limited := a.policyAllowed(now, from, to)
+	if !limited {
+		return to
+	}
+	want := to - from
+	if want < 0 {
+		want = -want
+	}
+	if allowed <= 0 {
+		log.Printf("i scale policy exhausted for this period, holding at %d", from)
+		return from
+	}
+	if want > allowed {
+		if to > from {
+			to = from + allowed
+		} else {
+			to = from - allowed
+		}
+		log.Printf("i scale policy clamps change to %d replicas (%d -> %d)", allowed, from, to)
+	}
+	return to
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyFlag(t *testing.T) {
+	var f policyFlag
+	if err := f.Set("4/1m"); err != nil {
+		t.Fatalf("Set(4/1m): %v", err)
+	}
+	if err := f.Set("50%/2m"); err != nil {
+		t.Fatalf("Set(50%%/2m): %v", err)
+	}
+	if f[0].percent || f[0].value != 4 || f[0].period != time.Minute {
+		t.Errorf("pods policy = %+v", f[0])
+	}
+	if !f[1].percent || f[1].value != 50 || f[1].period != 2*time.Minute {
+		t.Errorf("percent policy = %+v", f[1])
+	}
+	for _, bad := range []string{"4", "x/1m", "-2/1m", "0/1m", "4/0s", "4/later"} {
+		if err := f.Set(bad); err == nil {
+			t.Errorf("Set(%q) accepted", bad)
+		}
+	}
+}
+
+func TestStabilizationWindow(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{downStabilization: 5 * time.Minute}}
+
+	// Recent polls wanted 5 and 4 replicas; a drop to 2 is softened to
+	// the highest recommendation still in the window.
+	a.recordRecommendation(now.Add(-4*time.Minute), 5)
+	a.recordRecommendation(now.Add(-2*time.Minute), 4)
+	a.recordRecommendation(now, 2)
+	if got := a.stabilizedTarget(now, 5, 2); got != 5 {
+		t.Errorf("stabilizedTarget(5 -> 2) = %d, want 5", got)
+	}
+
+	// Once the high recommendations age out, the drop goes through.
+	later := now.Add(6 * time.Minute)
+	a.recordRecommendation(later, 2)
+	if got := a.stabilizedTarget(later, 5, 2); got != 2 {
+		t.Errorf("stabilizedTarget after window = %d, want 2", got)
+	}
+}
+
+func TestUpStabilizationWindow(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{upStabilization: 5 * time.Minute}}
+
+	// A calm poll in the window keeps the scale-up at the current count.
+	a.recordRecommendation(now.Add(-time.Minute), 2)
+	a.recordRecommendation(now, 4)
+	if got := a.stabilizedTarget(now, 2, 4); got != 2 {
+		t.Errorf("stabilizedTarget(2 -> 4) = %d, want 2", got)
+	}
+}
+
+func TestPolicyAllowed(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{upPolicies: policyFlag{{value: 4, period: time.Minute}}}}
+
+	if got, ok := a.policyAllowed(now, 2, 6); !ok || got != 4 {
+		t.Errorf("policyAllowed = %d, %v, want 4, true", got, ok)
+	}
+	a.recordEvent(scaleEvent{Time: now.Add(-30 * time.Second), From: 2, To: 5, Outcome: "applied"})
+	if got, _ := a.policyAllowed(now, 5, 9); got != 1 {
+		t.Errorf("policyAllowed after adding 3 = %d, want 1", got)
+	}
+
+	// The most permissive policy wins: 100%/1m of 5 replicas allows 5.
+	a.cfg.upPolicies = append(a.cfg.upPolicies, scalePolicy{value: 100, percent: true, period: time.Minute})
+	if got, _ := a.policyAllowed(now, 5, 9); got != 2 {
+		t.Errorf("policyAllowed with percent policy = %d, want 2", got)
+	}
+
+	// Downs are unconstrained without a down policy.
+	if _, ok := a.policyAllowed(now, 5, 3); ok {
+		t.Error("down direction constrained by up policies")
+	}
+}
+
+func TestApplyBehaviorClampsAndHolds(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{upPolicies: policyFlag{{value: 2, period: time.Minute}}}}
+	a.now = func() time.Time { return now }
+
+	if got := a.applyBehavior(2, 6); got != 4 {
+		t.Errorf("applyBehavior(2 -> 6) = %d, want clamp to 4", got)
+	}
+	a.recordEvent(scaleEvent{Time: now, From: 2, To: 4, Outcome: "applied"})
+	if got := a.applyBehavior(4, 5); got != 4 {
+		t.Errorf("applyBehavior with exhausted policy = %d, want hold at 4", got)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// The stats decode path runs once per container per poll, so its
+// allocation rate dominates a large fleet's steady state.
+func BenchmarkStatsDecode(b *testing.B) {
+	d := modernStatsDecoder{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.Decode(strings.NewReader(cgroupV2Stats)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// One poll's worth of decoding for a 100-container service.
+func BenchmarkStatsDecode100Containers(b *testing.B) {
+	d := modernStatsDecoder{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for c := 0; c < 100; c++ {
+			if _, err := d.Decode(strings.NewReader(cgroupV1Stats)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// rateLimitReason walks the full event ring on every scale attempt.
+func BenchmarkRateLimitReason(b *testing.B) {
+	a := &autoscaler{cfg: &config{maxEventsPerHour: 100, maxAddedPer10m: 100}}
+	now := time.Now()
+	for i := 0; i < eventHistorySize; i++ {
+		a.recordEvent(scaleEvent{Time: now.Add(-time.Duration(i) * time.Minute), From: 2, To: 3, Outcome: "applied"})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.rateLimitReason(now, 2, 3)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// The circuit breaker itself lives in pkg/autoscaler; this file wires
+// it to the alerting and statsd plumbing. It is only touched from the
+// poll goroutine, so it needs no lock.
+
+// dockerFailure records a failed daemon interaction, raising the alert
+// when the streak opens the circuit.
+func (a *autoscaler) dockerFailure() {
+	if a.breaker.Failure(a.now()) {
+		log.Printf("✗ circuit open for %s/%s: %d consecutive docker/compose failures, probing every %s",
+			a.cfg.project, a.cfg.service, a.breaker.Threshold, a.breaker.ProbeEvery)
+		a.statsd.gauge(a.cfg.service+".breaker", 1)
+		a.alert("GoScaleDockerFailing",
+			fmt.Sprintf("%s/%s: repeated docker/compose failures, circuit open", a.cfg.project, a.cfg.service), true)
+	}
+}
+
+// dockerSuccess records a working daemon interaction, announcing the
+// recovery when it closes an open circuit.
+func (a *autoscaler) dockerSuccess() {
+	if a.breaker.Success() {
+		log.Printf("✓ docker recovered for %s/%s, circuit closed", a.cfg.project, a.cfg.service)
+		a.statsd.gauge(a.cfg.service+".breaker", 0)
+		a.alert("GoScaleDockerFailing",
+			fmt.Sprintf("%s/%s: repeated docker/compose failures, circuit open", a.cfg.project, a.cfg.service), false)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Approval mode turns the scale action into an inspectable queued
+// decision, so these tests can see where a burst wanted to go without
+// running compose.
+func burstTestScaler() *autoscaler {
+	a := &autoscaler{cfg: &config{
+		burstPct: 95, approvalMode: true,
+		minReplicas: 1, maxReplicas: 8,
+		cpuUp: 80, cpuDown: 20, memUp: 80, memDown: 20,
+		scaleUpChecks: 3, scaleDownChecks: 3,
+	}}
+	a.now = time.Now
+	return a
+}
+
+func TestBurstScalesToMax(t *testing.T) {
+	a := burstTestScaler()
+	a.decide(context.Background(), 2, 97, 10, nil, false)
+	d, ok := a.currentDecision()
+	if !ok || d.Event.To != 8 {
+		t.Fatalf("decision after burst = %+v, %v; want target 8", d, ok)
+	}
+	// No streak requirement applied: one extreme poll was enough.
+	if a.highStreak != 0 {
+		t.Errorf("highStreak = %d after burst", a.highStreak)
+	}
+}
+
+func TestBurstBelowThresholdUsesNormalPolicy(t *testing.T) {
+	a := burstTestScaler()
+	a.decide(context.Background(), 2, 90, 10, nil, false)
+	if _, ok := a.currentDecision(); ok {
+		t.Fatal("high-but-not-extreme load scaled before the streak built up")
+	}
+	if a.highStreak != 1 {
+		t.Errorf("highStreak = %d, want 1", a.highStreak)
+	}
+}
+
+func TestBurstAtMaxDoesNothing(t *testing.T) {
+	a := burstTestScaler()
+	a.decide(context.Background(), 8, 97, 10, nil, false)
+	if d, ok := a.currentDecision(); ok {
+		t.Fatalf("burst at max queued %+v", d)
+	}
+}
+
+func TestBurstHonorsOperatorPause(t *testing.T) {
+	a := burstTestScaler()
+	a.setPaused(true)
+	if a.burstScale(context.Background(), 2, 8, 97, 10) {
+		t.Fatal("burst scaled through an operator pause")
+	}
+	if _, ok := a.currentDecision(); ok {
+		t.Fatal("suppressed burst still queued a decision")
+	}
+}
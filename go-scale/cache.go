@@ -0,0 +1,63 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// statsCache is a short-lived per-container cache of stats samples,
+// shared by every target in the process. Two services scaled on the
+// same daemon list overlapping containers (one-off containers, shared
+// dependencies), and a second go-scale loop re-polling the same ID
+// within a poll interval learns nothing new — it only doubles the load
+// on dockerd. Entries expire after the TTL; a zero TTL disables the
+// cache entirely.
+type statsCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]statsCacheEntry
+}
+
+type statsCacheEntry struct {
+	sample *statsSample
+	at     time.Time
+}
+
+func newStatsCache(ttl time.Duration) *statsCache {
+	return &statsCache{ttl: ttl, entries: map[string]statsCacheEntry{}}
+}
+
+// get returns the cached sample for a container when it is still
+// fresh, dropping it when it expired.
+func (c *statsCache) get(id string) (*statsSample, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(e.at) > c.ttl {
+		delete(c.entries, id)
+		return nil, false
+	}
+	return e.sample, true
+}
+
+// put stores one fresh sample, sweeping expired entries while it holds
+// the lock so stopped containers don't accumulate.
+func (c *statsCache) put(id string, s *statsSample) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if time.Since(e.at) > c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[id] = statsCacheEntry{sample: s, at: time.Now()}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatsCache(t *testing.T) {
+	c := newStatsCache(time.Hour)
+	if _, ok := c.get("abc"); ok {
+		t.Fatal("empty cache returned a sample")
+	}
+	s := &statsSample{CPUPercent: 42}
+	c.put("abc", s)
+	got, ok := c.get("abc")
+	if !ok || got != s {
+		t.Errorf("get = %v, %v, want the cached sample", got, ok)
+	}
+	if _, ok := c.get("other"); ok {
+		t.Error("cache returned a sample for a different container")
+	}
+}
+
+func TestStatsCacheExpiry(t *testing.T) {
+	c := newStatsCache(time.Nanosecond)
+	c.put("abc", &statsSample{})
+	time.Sleep(time.Millisecond)
+	if _, ok := c.get("abc"); ok {
+		t.Error("expired sample returned")
+	}
+	// put sweeps stale entries so stopped containers don't accumulate.
+	c.put("def", &statsSample{})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, stale := c.entries["abc"]; stale {
+		t.Error("stale entry survived the put sweep")
+	}
+}
+
+func TestStatsCacheDisabled(t *testing.T) {
+	var c *statsCache // no -stats-cache: every call is a miss, no panic
+	c.put("abc", &statsSample{})
+	if _, ok := c.get("abc"); ok {
+		t.Error("nil cache returned a sample")
+	}
+}
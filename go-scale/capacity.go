@@ -0,0 +1,96 @@
+package main
+
+import (
+	"log"
+	"runtime"
+
+	"github.com/iamdanielv/utils/internal/format"
+)
+
+// Capacity-aware scale-ups: before adding replicas, -capacity-check
+// estimates whether they actually fit the host's spare memory and CPU,
+// and reduces or refuses the step when they don't. The per-replica
+// footprint comes from what the existing replicas show in their stats:
+// the configured memory limit when one is set (a new replica may grow
+// to it), otherwise the observed working set, and the CPU cores one
+// replica burns. Better to hold at the current count with a clear log
+// line than to let the kernel OOM-kill whatever loses the race.
+
+// capacityEstimate is the observed per-replica footprint, refreshed
+// every stats poll. Only the poll goroutine touches it.
+type capacityEstimate struct {
+	memPerReplica   uint64
+	coresPerReplica float64
+}
+
+// updateCapacityEstimate derives the footprint from one poll's
+// samples, taking the largest replica as the conservative estimate. A
+// container without a memory limit reports the host total as its
+// limit; the working set stands in for those.
+func (a *autoscaler) updateCapacityEstimate(points []containerPoint) {
+	hostMem, err := readMemTotal("/proc/meminfo")
+	if err != nil {
+		hostMem = 0
+	}
+	var est capacityEstimate
+	for _, p := range points {
+		mem := p.sample.MemWorkingSet
+		if limit := p.sample.MemLimit; limit > 0 && (hostMem == 0 || limit < hostMem) {
+			mem = limit
+		}
+		if mem > est.memPerReplica {
+			est.memPerReplica = mem
+		}
+		if cores := p.sample.CPUPercent / 100; cores > est.coresPerReplica {
+			est.coresPerReplica = cores
+		}
+	}
+	a.capacity = est
+}
+
+// capacityFit returns how many replicas (between from and to) fit the
+// spare capacity. An availMem of 0 or a negative idleCores skips that
+// axis, so a broken /proc fails open.
+func capacityFit(est capacityEstimate, availMem uint64, idleCores float64, from, to int) int {
+	fit := to
+	if est.memPerReplica > 0 && availMem > 0 {
+		if n := from + int(availMem/est.memPerReplica); n < fit {
+			fit = n
+		}
+	}
+	if est.coresPerReplica > 0 && idleCores >= 0 {
+		if n := from + int(idleCores/est.coresPerReplica); n < fit {
+			fit = n
+		}
+	}
+	if fit < from {
+		fit = from
+	}
+	return fit
+}
+
+// capacityClamp applies the capacity check to a proposed scale-up and
+// logs which constraint got in the way. It returns from when nothing
+// more fits.
+func (a *autoscaler) capacityClamp(from, to int) int {
+	availMem, err := readMemAvailable("/proc/meminfo")
+	if err != nil {
+		availMem = 0
+	}
+	idleCores := -1.0
+	if load, err := readLoadAvg("/proc/loadavg"); err == nil {
+		idleCores = float64(runtime.NumCPU()) - load
+	}
+	fit := capacityFit(a.capacity, availMem, idleCores, from, to)
+	if fit == to {
+		return to
+	}
+	if fit == from {
+		log.Printf("i capacity check holds scale-up at %d: %s available, ~%s and ~%.2f cores per replica",
+			from, format.Bytes(availMem), format.Bytes(a.capacity.memPerReplica), a.capacity.coresPerReplica)
+		return from
+	}
+	log.Printf("i capacity check caps scale-up at %d (wanted %d): %s available, ~%s and ~%.2f cores per replica",
+		fit, to, format.Bytes(availMem), format.Bytes(a.capacity.memPerReplica), a.capacity.coresPerReplica)
+	return fit
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestCapacityFit(t *testing.T) {
+	est := capacityEstimate{memPerReplica: 512 << 20, coresPerReplica: 0.5}
+
+	// 2 GiB available and 4 idle cores: 4 more replicas fit either way.
+	if got := capacityFit(est, 2<<30, 4, 2, 5); got != 5 {
+		t.Errorf("roomy host fit = %d, want 5", got)
+	}
+	// 600 MiB available fits only one more replica.
+	if got := capacityFit(est, 600<<20, 4, 2, 5); got != 3 {
+		t.Errorf("memory-bound fit = %d, want 3", got)
+	}
+	// 0.6 idle cores fits only one more replica.
+	if got := capacityFit(est, 2<<30, 0.6, 2, 5); got != 3 {
+		t.Errorf("cpu-bound fit = %d, want 3", got)
+	}
+	// Nothing fits: the answer is the current count, never below it.
+	if got := capacityFit(est, 100<<20, 0.1, 2, 5); got != 2 {
+		t.Errorf("full host fit = %d, want 2", got)
+	}
+}
+
+func TestCapacityFitFailsOpen(t *testing.T) {
+	est := capacityEstimate{memPerReplica: 512 << 20, coresPerReplica: 0.5}
+	// Unreadable meminfo (0) and loadavg (-1) skip their axes.
+	if got := capacityFit(est, 0, -1, 2, 5); got != 5 {
+		t.Errorf("fit without host readings = %d, want 5", got)
+	}
+	// No estimate yet (first poll): nothing to check against.
+	if got := capacityFit(capacityEstimate{}, 100<<20, 0.1, 2, 5); got != 5 {
+		t.Errorf("fit without estimate = %d, want 5", got)
+	}
+}
+
+func TestUpdateCapacityEstimate(t *testing.T) {
+	a := &autoscaler{cfg: &config{}}
+	a.updateCapacityEstimate([]containerPoint{
+		{id: "a", sample: &statsSample{MemWorkingSet: 100 << 20, MemLimit: 1 << 62, CPUPercent: 30}},
+		{id: "b", sample: &statsSample{MemWorkingSet: 200 << 20, MemLimit: 1 << 62, CPUPercent: 80}},
+	})
+	// Limits at 2^62 read as "unlimited", so the working set wins.
+	if a.capacity.memPerReplica != 200<<20 {
+		t.Errorf("memPerReplica = %d, want %d", a.capacity.memPerReplica, 200<<20)
+	}
+	if a.capacity.coresPerReplica != 0.8 {
+		t.Errorf("coresPerReplica = %g, want 0.8", a.capacity.coresPerReplica)
+	}
+
+	// A real configured limit beats the working set.
+	a.updateCapacityEstimate([]containerPoint{
+		{id: "a", sample: &statsSample{MemWorkingSet: 100 << 20, MemLimit: 512 << 20, CPUPercent: 30}},
+	})
+	if a.capacity.memPerReplica != 512<<20 {
+		t.Errorf("memPerReplica with limit = %d, want %d", a.capacity.memPerReplica, 512<<20)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/iamdanielv/utils/internal/errcode"
+	"github.com/iamdanielv/utils/pkg/scaler"
+)
+
+// connectDocker opens the engine API and negotiates a version, mapping
+// the usual failure modes (socket missing, permission denied) onto the
+// exit codes errcode assigns them.
+func connectDocker(ctx context.Context) (*dockerClient, error) {
+	docker, err := newDockerClient()
+	if err != nil {
+		return nil, errcode.Wrap(errcode.Config, err)
+	}
+	if err := docker.negotiateVersion(ctx); err != nil {
+		kind := errcode.Unavailable
+		if errors.Is(err, os.ErrPermission) {
+			kind = errcode.Permission
+		}
+		return nil, errcode.Wrap(kind, fmt.Errorf("docker version negotiation: %w", err))
+	}
+	return docker, nil
+}
+
+// metricSourceConfigured reports whether any of the custom-signal flags
+// picked a source; it mirrors the switch in newAutoscaler.
+func (cfg *config) metricSourceConfigured() bool {
+	return cfg.providerName != "" || cfg.metricCmd != "" || cfg.metricURL != "" ||
+		cfg.redisAddr != "" || cfg.rabbitURL != "" || cfg.nginxStatusURL != "" ||
+		cfg.traefikMetricsURL != "" || cfg.logPatternRE != nil
+}
+
+// validateThresholds is the docker-free part of validate: flag values
+// that can never work together, caught before any daemon contact.
+func validateThresholds(cfg *config) error {
+	if err := validateReload(cfg); err != nil {
+		return errcode.Wrap(errcode.Config, err)
+	}
+	if cfg.metricUp != 0 && cfg.metricDown >= cfg.metricUp {
+		return errcode.New(errcode.Config, "metric-down %g is not below metric-up %g", cfg.metricDown, cfg.metricUp)
+	}
+	// A metric source without -metric-up (and no rules to consume the
+	// signal) would be sampled and then ignored on every poll.
+	if cfg.metricSourceConfigured() && cfg.metricUp == 0 && cfg.ruleUp == nil && cfg.ruleDown == nil {
+		return errcode.New(errcode.Config, "a metric source is configured but -metric-up is unset and no rule references it")
+	}
+	return nil
+}
+
+// runValidate is the `validate` subcommand: threshold sanity first (no
+// docker needed, so a bad flag set fails fast), then engine
+// reachability, the compose CLI, and finally that each target's compose
+// config parses and its service exists.
+func runValidate(cfg *config) error {
+	if err := validateThresholds(cfg); err != nil {
+		return err
+	}
+	fmt.Println("✓ thresholds are sane")
+
+	ctx := context.Background()
+	if cfg.backend == "nomad" {
+		nomad := newNomadClient(cfg.nomadAddr, cfg.nomadToken, cfg.nomadNamespace)
+		for _, t := range cfg.targets {
+			count, _, err := nomad.GroupCount(ctx, t.project, t.service)
+			if err != nil {
+				return errcode.Wrap(errcode.Unavailable, err)
+			}
+			fmt.Printf("✓ nomad job %s group %s: count %d\n", t.project, t.service, count)
+		}
+		return nil
+	}
+	docker, err := connectDocker(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ docker engine reachable (API %s)\n", docker.APIVersion)
+
+	// The scale action shells out to the compose CLI (plugin or
+	// standalone docker-compose); prove we can run it before promising
+	// we can scale.
+	bin, argv := scaler.ComposeCommand([]string{"compose", "version", "--short"})
+	out, err := exec.Command(bin, argv...).CombinedOutput()
+	if err != nil {
+		return errcode.New(errcode.Config, "compose CLI not runnable: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	fmt.Printf("✓ compose CLI %s\n", strings.TrimSpace(string(out)))
+
+	for _, t := range cfg.targets {
+		tcfg := *cfg
+		tcfg.project, tcfg.service = t.project, t.service
+		bin, args := scaler.ComposeCommand(append(composeArgs(&tcfg), "config", "--quiet"))
+		if out, err := exec.Command(bin, args...).CombinedOutput(); err != nil {
+			return errcode.New(errcode.Config, "compose config for %s: %v: %s", t.project, err, strings.TrimSpace(string(out)))
+		}
+		fmt.Printf("✓ compose config for %s parses\n", t.project)
+
+		// With the profiles applied, the config listing is the source of
+		// truth for whether the target service exists at all — a service
+		// gated behind an unnamed profile simply isn't in it.
+		bin, args = scaler.ComposeCommand(append(composeArgs(&tcfg), "config", "--services"))
+		if out, err := exec.Command(bin, args...).CombinedOutput(); err == nil {
+			found := false
+			for _, s := range strings.Fields(string(out)) {
+				if s == t.service {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errcode.New(errcode.Config, "service %q is not in the compose config for %s (missing -profile?)", t.service, t.project)
+			}
+			fmt.Printf("✓ service %s is in the compose config\n", t.service)
+		}
+
+		containers, err := docker.getServiceContainers(ctx, &tcfg, t.service)
+		if err != nil {
+			return errcode.Wrap(errcode.Unavailable, err)
+		}
+		if len(containers) == 0 {
+			fmt.Printf("i %s/%s has no containers yet (service not started?)\n", t.project, t.service)
+		} else {
+			fmt.Printf("✓ %s/%s: %d replica(s) running\n", t.project, t.service, len(containers))
+		}
+	}
+	return nil
+}
+
+// statusReport is one target's snapshot in the `status` output.
+type statusReport struct {
+	Project  string  `json:"project"`
+	Service  string  `json:"service"`
+	Replicas int     `json:"replicas"`
+	CPU      float64 `json:"cpu"`
+	Mem      float64 `json:"mem"`
+}
+
+// runStatus is the `status` subcommand: one stats poll per target,
+// printed as JSON. A single target prints an object, several print an
+// array, mirroring the control API's legacy/project split.
+func runStatus(cfg *config) error {
+	ctx := context.Background()
+
+	var reports []statusReport
+	if cfg.backend == "nomad" {
+		nomad := newNomadClient(cfg.nomadAddr, cfg.nomadToken, cfg.nomadNamespace)
+		for _, t := range cfg.targets {
+			count, memMiB, err := nomad.GroupCount(ctx, t.project, t.service)
+			if err != nil {
+				return errcode.Wrap(errcode.Unavailable, err)
+			}
+			rep := statusReport{Project: t.project, Service: t.service, Replicas: count}
+			allocs, err := nomad.RunningAllocs(ctx, t.project, t.service)
+			if err != nil {
+				return errcode.Wrap(errcode.Unavailable, err)
+			}
+			var cpuSum, memSum float64
+			var sampled int
+			for _, id := range allocs {
+				cpu, memBytes, err := nomad.AllocUsage(ctx, id)
+				if err != nil {
+					continue
+				}
+				cpuSum += cpu
+				if memMiB > 0 {
+					memSum += float64(memBytes) / float64(uint64(memMiB)<<20) * 100.0
+				}
+				sampled++
+			}
+			if sampled > 0 {
+				rep.CPU = cpuSum / float64(sampled)
+				rep.Mem = memSum / float64(sampled)
+			}
+			reports = append(reports, rep)
+		}
+		return writeReports(reports)
+	}
+
+	docker, err := connectDocker(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range cfg.targets {
+		tcfg := *cfg
+		tcfg.project, tcfg.service = t.project, t.service
+		containers, err := docker.getServiceContainers(ctx, &tcfg, t.service)
+		if err != nil {
+			return errcode.Wrap(errcode.Unavailable, err)
+		}
+		rep := statusReport{Project: t.project, Service: t.service, Replicas: countReplicas(&tcfg, containers)}
+		var cpuSum, memSum float64
+		var sampled int
+		for _, c := range containers {
+			s, err := docker.containerStats(ctx, c.ID)
+			if err != nil {
+				continue
+			}
+			cpuSum += s.CPUPercent
+			memSum += s.memPct(cfg.memAccounting)
+			sampled++
+		}
+		if sampled > 0 {
+			rep.CPU = cpuSum / float64(sampled)
+			rep.Mem = memSum / float64(sampled)
+		}
+		reports = append(reports, rep)
+	}
+	return writeReports(reports)
+}
+
+// writeReports prints status reports as indented JSON: an object for a
+// single target, an array for several.
+func writeReports(reports []statusReport) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if len(reports) == 1 {
+		return enc.Encode(reports[0])
+	}
+	return enc.Encode(reports)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	engine "github.com/iamdanielv/utils/pkg/autoscaler"
+)
+
+// validConfig is a minimal flag set that passes validateReload.
+func validConfig() *config {
+	return &config{
+		minReplicas:     1,
+		maxReplicas:     5,
+		cpuUp:           80,
+		cpuDown:         20,
+		memUp:           80,
+		memDown:         20,
+		scaleUpChecks:   1,
+		scaleDownChecks: 1,
+	}
+}
+
+func TestValidateThresholds(t *testing.T) {
+	rule, err := engine.ParseRule("metric > 100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		mutate  func(*config)
+		wantErr bool
+	}{
+		{"no metric source", func(cfg *config) {}, false},
+		{"metric-down above metric-up", func(cfg *config) {
+			cfg.metricUp, cfg.metricDown = 10, 20
+		}, true},
+		{"source without metric-up", func(cfg *config) {
+			cfg.redisAddr = "localhost:6379"
+		}, true},
+		{"source with metric-up", func(cfg *config) {
+			cfg.redisAddr = "localhost:6379"
+			cfg.metricUp = 500
+		}, false},
+		{"source feeding a rule", func(cfg *config) {
+			cfg.metricCmd = "queue-depth"
+			cfg.ruleUp = rule
+		}, false},
+	}
+	for _, tt := range tests {
+		cfg := validConfig()
+		tt.mutate(cfg)
+		err := validateThresholds(cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// flipFlopWindow is how soon after a scale action a reversal has to
+// land to count as a flip-flop.
+const flipFlopWindow = 10 * time.Minute
+
+// maxCooldownBoost caps the backoff at cooldown * 2^5.
+const maxCooldownBoost = 5
+
+// effectiveCooldown is the configured cooldown lengthened by the
+// current flip-flop backoff.
+func (a *autoscaler) effectiveCooldown() time.Duration {
+	return a.cfg.cooldown * time.Duration(1<<a.cooldownBoost)
+}
+
+// adjustCooldown updates the backoff after an applied scale action.
+// A reversal within flipFlopWindow of the previous action means the
+// thresholds are fighting each other, so the cooldown doubles; any
+// action that is not a reversal means things settled and the backoff
+// resets.
+func (a *autoscaler) adjustCooldown(now time.Time, up bool) {
+	dir := -1
+	if up {
+		dir = 1
+	}
+	prev := a.lastDir
+	a.lastDir = dir
+	if !a.cfg.adaptiveCooldown {
+		return
+	}
+	if prev != 0 && dir != prev && now.Sub(a.lastScale) <= flipFlopWindow {
+		if a.cooldownBoost < maxCooldownBoost {
+			a.cooldownBoost++
+		}
+		log.Printf("i flip-flop detected within %s of the last action, cooldown backed off to %s",
+			flipFlopWindow, a.effectiveCooldown())
+		return
+	}
+	if a.cooldownBoost > 0 {
+		a.cooldownBoost = 0
+		log.Printf("i scaling settled, cooldown back to %s", a.cfg.cooldown)
+	}
+}
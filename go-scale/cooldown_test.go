@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveCooldown(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{cooldown: time.Minute, adaptiveCooldown: true}}
+
+	// First action: nothing to compare against.
+	a.adjustCooldown(now, true)
+	a.lastScale = now
+	if got := a.effectiveCooldown(); got != time.Minute {
+		t.Errorf("cooldown after first action = %s", got)
+	}
+
+	// Quick reversal doubles the cooldown; a second one doubles again.
+	a.adjustCooldown(now.Add(time.Minute), false)
+	a.lastScale = now.Add(time.Minute)
+	if got := a.effectiveCooldown(); got != 2*time.Minute {
+		t.Errorf("cooldown after one flip-flop = %s, want 2m", got)
+	}
+	a.adjustCooldown(now.Add(2*time.Minute), true)
+	a.lastScale = now.Add(2 * time.Minute)
+	if got := a.effectiveCooldown(); got != 4*time.Minute {
+		t.Errorf("cooldown after two flip-flops = %s, want 4m", got)
+	}
+
+	// Same-direction action settles the backoff.
+	a.adjustCooldown(now.Add(3*time.Minute), true)
+	if got := a.effectiveCooldown(); got != time.Minute {
+		t.Errorf("cooldown after settling = %s, want 1m", got)
+	}
+
+	// A reversal long after the last action is not a flip-flop.
+	a.lastScale = now.Add(3 * time.Minute)
+	a.adjustCooldown(now.Add(3*time.Minute+flipFlopWindow+time.Second), false)
+	if got := a.effectiveCooldown(); got != time.Minute {
+		t.Errorf("cooldown after slow reversal = %s, want 1m", got)
+	}
+}
+
+func TestAdaptiveCooldownCap(t *testing.T) {
+	a := &autoscaler{cfg: &config{cooldown: time.Minute, adaptiveCooldown: true}}
+	now := time.Now()
+	up := true
+	for i := 0; i < 2*maxCooldownBoost; i++ {
+		a.adjustCooldown(now, up)
+		a.lastScale, up = now, !up
+	}
+	if got := a.effectiveCooldown(); got != time.Minute<<maxCooldownBoost {
+		t.Errorf("cooldown = %s, want capped at %s", got, time.Minute<<maxCooldownBoost)
+	}
+}
+
+func TestAdaptiveCooldownDisabled(t *testing.T) {
+	a := &autoscaler{cfg: &config{cooldown: time.Minute}}
+	now := time.Now()
+	a.adjustCooldown(now, true)
+	a.lastScale = now
+	a.adjustCooldown(now.Add(time.Second), false)
+	if got := a.effectiveCooldown(); got != time.Minute {
+		t.Errorf("cooldown = %s, want unchanged 1m", got)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// Cost-aware scaling. -replica-cost attaches a price per replica-hour
+// (in whatever unit the bill arrives in) and -cost-budget an hourly
+// ceiling for the fleet. The budget caps scale-up targets rather than
+// forcing scale-downs: capacity already running stays until the normal
+// policy removes it. Replica-hours accrue per service on the /metrics
+// endpoint whether or not a cost is configured, so the bill can be
+// reconstructed after the fact.
+
+// costClamp caps a scale-up target at the largest fleet the hourly
+// budget covers. It never pushes the target below the current count —
+// the budget shapes growth, it doesn't evict replicas.
+func (a *autoscaler) costClamp(from, to int) int {
+	if a.cfg.replicaCost <= 0 || a.cfg.costBudget <= 0 || to <= from {
+		return to
+	}
+	affordable := int(a.cfg.costBudget / a.cfg.replicaCost)
+	if to <= affordable {
+		return to
+	}
+	if affordable < from {
+		affordable = from
+	}
+	log.Printf("i budget %g/h covers %d replicas at %g/h each, capping scale-up at %d (wanted %d)",
+		a.cfg.costBudget, affordable, a.cfg.replicaCost, affordable, to)
+	return affordable
+}
+
+// logProjectedCost notes what the fleet costs after an applied
+// scale-up.
+func (a *autoscaler) logProjectedCost(event scaleEvent) {
+	if a.cfg.replicaCost <= 0 || event.To <= event.From {
+		return
+	}
+	log.Printf("i [%s] projected cost: %d replicas at %g/h = %g/h",
+		event.ID, event.To, a.cfg.replicaCost, float64(event.To)*a.cfg.replicaCost)
+}
+
+// trackReplicaHours accrues replica-hours between polls. Wall clock,
+// like the rest of the self-metrics.
+func (a *autoscaler) trackReplicaHours(replicas int) {
+	now := time.Now()
+	if !a.lastAccrual.IsZero() {
+		self.addReplicaHours(a.cfg.service, float64(replicas)*now.Sub(a.lastAccrual).Hours())
+	}
+	a.lastAccrual = now
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestCostClamp(t *testing.T) {
+	a := &autoscaler{cfg: &config{replicaCost: 2, costBudget: 10}}
+
+	// Budget 10/h at 2/h each covers 5 replicas.
+	if got := a.costClamp(2, 4); got != 4 {
+		t.Errorf("within budget: costClamp(2, 4) = %d", got)
+	}
+	if got := a.costClamp(2, 8); got != 5 {
+		t.Errorf("over budget: costClamp(2, 8) = %d, want 5", got)
+	}
+
+	// Already over budget (operator scaled by hand, or the budget was
+	// lowered): the clamp never forces a scale-down.
+	if got := a.costClamp(6, 8); got != 6 {
+		t.Errorf("over-budget fleet: costClamp(6, 8) = %d, want 6", got)
+	}
+
+	// Scale-downs pass through untouched.
+	if got := a.costClamp(4, 2); got != 2 {
+		t.Errorf("scale-down: costClamp(4, 2) = %d", got)
+	}
+
+	// Unconfigured: no clamping.
+	a.cfg.costBudget = 0
+	if got := a.costClamp(2, 8); got != 8 {
+		t.Errorf("no budget: costClamp(2, 8) = %d", got)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Restart-loop detection. A replica stuck in a crash loop shows up in
+// the listing as restarting (or briefly exited between attempts); once
+// that holds for -crashloop-polls consecutive polls we call it a loop,
+// raise the alert and — unless disabled — hold scale-downs, because
+// removing capacity during a crash loop makes the outage worse.
+
+// restartLooping reports whether any replica currently looks like it is
+// restart-cycling.
+func restartLooping(containers []containerInfo) bool {
+	for _, c := range containers {
+		if c.State == "restarting" || strings.Contains(c.Status, "Restarting") {
+			return true
+		}
+	}
+	return false
+}
+
+// trackRestartLoop folds one poll's listing into the crash-loop state,
+// announcing entry and recovery.
+func (a *autoscaler) trackRestartLoop(containers []containerInfo) {
+	if a.cfg.crashLoopPolls == 0 {
+		return
+	}
+	if !restartLooping(containers) {
+		a.crashStreak = 0
+		if a.crashLoop {
+			a.crashLoop = false
+			log.Printf("✓ %s/%s stopped restart-cycling", a.cfg.project, a.cfg.service)
+			a.statsd.gauge(a.cfg.service+".crashloop", 0)
+			a.alert("GoScaleCrashLoop",
+				fmt.Sprintf("%s/%s replicas are restart-cycling", a.cfg.project, a.cfg.service), false)
+		}
+		return
+	}
+	a.crashStreak++
+	if a.crashStreak >= a.cfg.crashLoopPolls && !a.crashLoop {
+		a.crashLoop = true
+		log.Printf("✗ %s/%s is restart-cycling (%d consecutive polls); scale-downs held", a.cfg.project, a.cfg.service, a.crashStreak)
+		a.statsd.gauge(a.cfg.service+".crashloop", 1)
+		a.alert("GoScaleCrashLoop",
+			fmt.Sprintf("%s/%s replicas are restart-cycling", a.cfg.project, a.cfg.service), true)
+	}
+}
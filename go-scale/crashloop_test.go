@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestTrackRestartLoop(t *testing.T) {
+	a := &autoscaler{cfg: &config{crashLoopPolls: 2, crashLoopHoldDowns: true}}
+	restarting := []containerInfo{
+		{State: "running", Status: "Up 2 hours"},
+		{State: "restarting", Status: "Restarting (1) 5 seconds ago"},
+	}
+	healthy := []containerInfo{
+		{State: "running", Status: "Up 2 hours"},
+		{State: "running", Status: "Up 10 seconds"},
+	}
+
+	a.trackRestartLoop(restarting)
+	if a.crashLoop {
+		t.Fatal("latched after a single poll, want 2")
+	}
+	a.trackRestartLoop(restarting)
+	if !a.crashLoop {
+		t.Fatal("not latched after reaching crashLoopPolls")
+	}
+	a.trackRestartLoop(healthy)
+	if a.crashLoop || a.crashStreak != 0 {
+		t.Fatalf("did not clear on recovery: loop=%v streak=%d", a.crashLoop, a.crashStreak)
+	}
+
+	// Disabled tracking never latches.
+	a = &autoscaler{cfg: &config{}}
+	for i := 0; i < 10; i++ {
+		a.trackRestartLoop(restarting)
+	}
+	if a.crashLoop {
+		t.Fatal("disabled tracking latched")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// configHashLabel is set by compose on every container it creates; a
+// deploy that is mid-roll leaves old and new hashes running side by
+// side.
+const configHashLabel = "com.docker.compose.config-hash"
+
+// deploySettleWindow is how long after a container's creation the
+// service still counts as deploying. Long enough for compose to finish
+// a rolling recreate, short enough not to block scaling for ages.
+const deploySettleWindow = 45 * time.Second
+
+// deployInProgress reports why a deploy appears to be recreating the
+// service's containers, or "" when the service looks settled. Scaling
+// commands issued mid-deploy race `docker compose up` over the same
+// containers, so decisions are held until this clears.
+func deployInProgress(containers []containerInfo, now time.Time) string {
+	hashes := map[string]bool{}
+	for _, c := range containers {
+		if h := c.Labels[configHashLabel]; h != "" {
+			hashes[h] = true
+		}
+		switch c.State {
+		case "created", "restarting":
+			return fmt.Sprintf("container %s is %s", c.Name(), c.State)
+		}
+		if age := now.Sub(time.Unix(c.Created, 0)); age >= 0 && age < deploySettleWindow {
+			return fmt.Sprintf("container %s created %s ago", c.Name(), age.Round(time.Second))
+		}
+	}
+	if len(hashes) > 1 {
+		return "old and new compose config hashes running side by side"
+	}
+	return ""
+}
+
+// trackConfigHash watches the config-hash labels across polls and arms
+// a -deploy-blackout window when a hash we haven't seen before shows
+// up. deployInProgress catches a roll it can observe; this catches the
+// fast flip where the whole fleet lands on the new hash between two
+// polls. The first poll after startup seeds the set without a blackout
+// — a daemon start is not a deploy.
+func (a *autoscaler) trackConfigHash(containers []containerInfo, now time.Time) {
+	current := map[string]bool{}
+	for _, c := range containers {
+		if h := c.Labels[configHashLabel]; h != "" {
+			current[h] = true
+		}
+	}
+	if len(current) == 0 {
+		return
+	}
+	if len(a.knownHashes) > 0 && a.cfg.deployBlackout > 0 {
+		for h := range current {
+			if !a.knownHashes[h] {
+				a.blackoutUntil = now.Add(a.cfg.deployBlackout)
+				log.Printf("i new compose config hash on %s, holding scale actions for %s", a.cfg.service, a.cfg.deployBlackout)
+				break
+			}
+		}
+	}
+	a.knownHashes = current
+}
+
+// blackoutReason reports the active post-deploy blackout, if any.
+func (a *autoscaler) blackoutReason(now time.Time) string {
+	if now.Before(a.blackoutUntil) {
+		return "post-deploy blackout until " + a.blackoutUntil.Format("15:04:05")
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeployInProgress(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	settled := func(hash string) containerInfo {
+		return containerInfo{
+			ID:      "abcdef123456",
+			Names:   []string{"/demo-web-1"},
+			State:   "running",
+			Created: now.Add(-10 * time.Minute).Unix(),
+			Labels:  map[string]string{configHashLabel: hash},
+		}
+	}
+
+	if got := deployInProgress(nil, now); got != "" {
+		t.Errorf("no containers: %q", got)
+	}
+	if got := deployInProgress([]containerInfo{settled("aaa"), settled("aaa")}, now); got != "" {
+		t.Errorf("settled service: %q", got)
+	}
+
+	// Old and new config hashes side by side: a rolling recreate.
+	if got := deployInProgress([]containerInfo{settled("aaa"), settled("bbb")}, now); !strings.Contains(got, "config hashes") {
+		t.Errorf("mixed hashes: %q", got)
+	}
+
+	// A container created moments ago hasn't settled yet.
+	fresh := settled("aaa")
+	fresh.Created = now.Add(-5 * time.Second).Unix()
+	if got := deployInProgress([]containerInfo{fresh}, now); !strings.Contains(got, "created") {
+		t.Errorf("fresh container: %q", got)
+	}
+
+	// Compose has created but not yet started the replacement.
+	created := settled("aaa")
+	created.State = "created"
+	if got := deployInProgress([]containerInfo{settled("aaa"), created}, now); !strings.Contains(got, "is created") {
+		t.Errorf("created container: %q", got)
+	}
+}
+
+func TestDeployBlackout(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	with := func(hash string) []containerInfo {
+		return []containerInfo{{
+			ID:      "abcdef123456",
+			Names:   []string{"/demo-web-1"},
+			State:   "running",
+			Created: now.Add(-10 * time.Minute).Unix(),
+			Labels:  map[string]string{configHashLabel: hash},
+		}}
+	}
+	as := newAutoscaler(&config{project: "demo", service: "web", deployBlackout: 2 * time.Minute}, nil)
+
+	// The first poll seeds the hash set: startup is not a deploy.
+	as.trackConfigHash(with("aaa"), now)
+	if got := as.blackoutReason(now); got != "" {
+		t.Errorf("startup poll armed a blackout: %q", got)
+	}
+
+	// A hash flip between polls arms the blackout, once.
+	as.trackConfigHash(with("bbb"), now.Add(time.Minute))
+	if got := as.blackoutReason(now.Add(time.Minute)); !strings.Contains(got, "blackout") {
+		t.Errorf("new hash did not arm the blackout: %q", got)
+	}
+	if got := as.blackoutReason(now.Add(4 * time.Minute)); got != "" {
+		t.Errorf("blackout did not expire: %q", got)
+	}
+
+	// The same hash again is not a new deploy.
+	as.trackConfigHash(with("bbb"), now.Add(5*time.Minute))
+	if got := as.blackoutReason(now.Add(5 * time.Minute)); got != "" {
+		t.Errorf("repeat hash re-armed the blackout: %q", got)
+	}
+
+	// -deploy-blackout 0 disables the window entirely.
+	as.cfg.deployBlackout = 0
+	as.trackConfigHash(with("ccc"), now.Add(6*time.Minute))
+	if got := as.blackoutReason(now.Add(6 * time.Minute)); got != "" {
+		t.Errorf("disabled blackout still armed: %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/iamdanielv/utils/internal/dockerapi"
+)
+
+// dockerClient wraps the shared Engine API client with the autoscaler's
+// stats decoding.
+type dockerClient struct {
+	*dockerapi.Client
+	decoder statsDecoder
+}
+
+// containerInfo is the shared container listing type.
+type containerInfo = dockerapi.Container
+
+func newDockerClient() (*dockerClient, error) {
+	c, err := dockerapi.New()
+	if err != nil {
+		return nil, err
+	}
+	return &dockerClient{Client: c}, nil
+}
+
+// negotiateVersion asks the daemon which API version it speaks and
+// picks a matching stats decoder. Older daemons get the legacy decoder
+// instead of a schema we would half-understand.
+func (c *dockerClient) negotiateVersion(ctx context.Context) error {
+	if err := c.Negotiate(ctx); err != nil {
+		return err
+	}
+	c.decoder = decoderForVersion(c.APIVersion)
+	return nil
+}
+
+// getServiceContainers lists the containers backing a compose service.
+// The project label is always part of the filter so two stacks with a
+// "web" service don't get counted together; -list-filter adds raw
+// engine filters and -running-only narrows to running containers.
+func (c *dockerClient) getServiceContainers(ctx context.Context, cfg *config, service string) ([]containerInfo, error) {
+	filters := map[string][]string{
+		"label": {
+			"com.docker.compose.project=" + cfg.project,
+			"com.docker.compose.service=" + service,
+		},
+	}
+	for _, f := range cfg.listFilters {
+		k, v, _ := strings.Cut(f, "=")
+		filters[k] = append(filters[k], v)
+	}
+	if cfg.runningOnly {
+		filters["status"] = append(filters["status"], "running")
+	}
+	return c.ListContainers(ctx, filters, !cfg.runningOnly)
+}
+
+// countReplicas reports how many listed containers count as a replica.
+// Exited and created containers still show up in the listing (deploy
+// detection and image refresh need them) but shouldn't skew the scale
+// math; -count-states widens the definition when a workload wants, say,
+// restarting containers counted too.
+func countReplicas(cfg *config, containers []containerInfo) int {
+	states := cfg.countStates
+	if states == "" {
+		states = "running"
+	}
+	n := 0
+	for _, c := range containers {
+		for _, s := range strings.Split(states, ",") {
+			if c.State == strings.TrimSpace(s) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// unhealthyPct returns the percentage of running replicas currently
+// failing their docker healthcheck. Containers without a healthcheck
+// never report unhealthy, so they count as fine.
+func unhealthyPct(containers []containerInfo) float64 {
+	total, bad := 0, 0
+	for _, c := range containers {
+		if c.State != "running" {
+			continue
+		}
+		total++
+		if strings.Contains(c.Status, "unhealthy") {
+			bad++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(bad) / float64(total)
+}
+
+// findContainer resolves a container name to its list entry, or nil
+// when no such container exists.
+func (c *dockerClient) findContainer(ctx context.Context, name string) (*containerInfo, error) {
+	list, err := c.ListContainers(ctx, map[string][]string{"name": {name}}, true)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Name() == name {
+			return &list[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// containerStats takes a one-shot stats sample for a container and runs
+// it through the negotiated decoder. The body is streamed straight into
+// the decoder rather than buffered: the payload is read exactly once
+// and only the fields we use are kept.
+func (c *dockerClient) containerStats(ctx context.Context, id string) (*statsSample, error) {
+	body, err := c.Get(ctx, "/containers/"+id+"/stats?stream=false")
+	if err != nil {
+		return nil, fmt.Errorf("stats %s: %w", shortID(id), err)
+	}
+	defer body.Close()
+	return c.decoder.Decode(body)
+}
+
+func shortID(id string) string { return dockerapi.ShortID(id) }
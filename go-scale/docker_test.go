@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestCountReplicas(t *testing.T) {
+	containers := []containerInfo{
+		{State: "running"},
+		{State: "running"},
+		{State: "exited"},
+		{State: "created"},
+		{State: "restarting"},
+	}
+
+	cfg := &config{}
+	if got := countReplicas(cfg, containers); got != 2 {
+		t.Errorf("default counting = %d, want 2 (running only)", got)
+	}
+
+	cfg.countStates = "running, restarting"
+	if got := countReplicas(cfg, containers); got != 3 {
+		t.Errorf("running+restarting = %d, want 3", got)
+	}
+}
+
+func TestUnhealthyPct(t *testing.T) {
+	containers := []containerInfo{
+		{State: "running", Status: "Up 2 hours (healthy)"},
+		{State: "running", Status: "Up 5 minutes (unhealthy)"},
+		{State: "running", Status: "Up 3 hours"}, // no healthcheck
+		{State: "exited", Status: "Exited (1) 2 minutes ago"},
+	}
+	if got := unhealthyPct(containers); got < 33.2 || got > 33.4 {
+		t.Errorf("unhealthyPct = %g, want ~33.3", got)
+	}
+	if got := unhealthyPct(nil); got != 0 {
+		t.Errorf("unhealthyPct(nil) = %g, want 0", got)
+	}
+}
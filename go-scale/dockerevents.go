@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+	"time"
+)
+
+// Event-driven reconciliation: instead of waiting up to a full poll
+// interval to notice lost capacity, each target subscribes to the
+// engine's event stream (die, start, health_status, filtered to its own
+// project/service labels) and pokes the poll loop the moment something
+// happens. The poke channel coalesces bursts into a single evaluation.
+
+// watchDockerEvents runs the subscription until the context ends,
+// reconnecting with doubling backoff when the stream drops.
+func (a *autoscaler) watchDockerEvents(ctx context.Context) {
+	backoff := 5 * time.Second
+	for {
+		err := a.streamDockerEvents(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("✗ docker events: %v (reconnecting in %s)", err, backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < time.Minute {
+			backoff *= 2
+		}
+	}
+}
+
+// streamDockerEvents opens one /events stream and pokes the poll loop
+// per event until the stream breaks.
+func (a *autoscaler) streamDockerEvents(ctx context.Context) error {
+	filters := map[string][]string{
+		"type":  {"container"},
+		"event": {"die", "start", "health_status"},
+		"label": {
+			"com.docker.compose.project=" + a.cfg.project,
+			"com.docker.compose.service=" + a.cfg.service,
+		},
+	}
+	raw, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	q := url.Values{}
+	q.Set("filters", string(raw))
+	body, err := a.docker.GetStream(ctx, "/events?"+q.Encode())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	for {
+		var ev struct {
+			Action string `json:"Action"`
+			Actor  struct {
+				Attributes map[string]string `json:"Attributes"`
+			} `json:"Actor"`
+		}
+		if err := dec.Decode(&ev); err != nil {
+			return err
+		}
+		if a.cfg.verbose {
+			log.Printf("i docker event %q for %s, re-evaluating", ev.Action, ev.Actor.Attributes["name"])
+		}
+		a.requestEvaluation()
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// drainer marks a load balancer backend as draining before a replica is
+// removed, so in-flight connections finish instead of being cut.
+type drainer interface {
+	Name() string
+	// Drain stops new connections to the server. Implementations that
+	// cannot (read-only APIs) return nil and only watch connections.
+	Drain(ctx context.Context, server string) error
+	// Connections reports the open connection count for the server.
+	Connections(ctx context.Context, server string) (int, error)
+	// Release undoes Drain, for when the scale-down did not happen.
+	Release(ctx context.Context, server string) error
+}
+
+// newDrainer builds the configured drainer, or nil when draining is
+// disabled.
+func newDrainer(cfg *config, docker *dockerClient) (drainer, error) {
+	switch cfg.drainMode {
+	case "":
+		return nil, nil
+	case "haproxy":
+		if cfg.haproxyAddr == "" || cfg.haproxyBackend == "" {
+			return nil, fmt.Errorf("drain mode haproxy needs -drain-haproxy-addr and -drain-haproxy-backend")
+		}
+		return &haproxyDrainer{addr: cfg.haproxyAddr, backend: cfg.haproxyBackend}, nil
+	case "nginx":
+		if cfg.nginxAPIURL == "" || cfg.nginxUpstream == "" {
+			return nil, fmt.Errorf("drain mode nginx needs -drain-nginx-api and -drain-nginx-upstream")
+		}
+		return &nginxDrainer{api: strings.TrimRight(cfg.nginxAPIURL, "/"), upstream: cfg.nginxUpstream,
+			client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "traefik":
+		if cfg.traefikMetricsURL == "" || cfg.traefikService == "" {
+			return nil, fmt.Errorf("drain mode traefik needs -traefik-metrics-url and -traefik-service")
+		}
+		return &traefikDrainer{url: cfg.traefikMetricsURL, service: cfg.traefikService,
+			client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "signal":
+		return &containerDrainer{docker: docker, signal: cfg.drainSignal}, nil
+	case "http":
+		if cfg.drainHTTPURL == "" {
+			return nil, fmt.Errorf("drain mode http needs -drain-http-url")
+		}
+		return &containerDrainer{docker: docker, url: cfg.drainHTTPURL,
+			client: &http.Client{Timeout: 10 * time.Second}}, nil
+	default:
+		return nil, fmt.Errorf("unknown drain mode %q", cfg.drainMode)
+	}
+}
+
+// waitDrained drains the victim server and polls its connection count
+// until it reaches zero or the timeout passes. Timeouts are logged, not
+// fatal: after the grace period the replica goes away regardless.
+func waitDrained(ctx context.Context, d drainer, server string, timeout time.Duration) {
+	if err := d.Drain(ctx, server); err != nil {
+		log.Printf("✗ drain %s via %s: %v", server, d.Name(), err)
+		return
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		n, err := d.Connections(ctx, server)
+		if err != nil {
+			log.Printf("✗ drain %s: %v", server, err)
+			return
+		}
+		if n == 0 {
+			log.Printf("i %s drained via %s", server, d.Name())
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Printf("i drain timeout for %s: %d connections still open", server, n)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// haproxyDrainer drives the HAProxy runtime API over its stats socket
+// (TCP or unix).
+type haproxyDrainer struct {
+	addr    string
+	backend string
+}
+
+func (d *haproxyDrainer) Name() string { return "haproxy" }
+
+// command runs one runtime API command and returns the response.
+func (d *haproxyDrainer) command(ctx context.Context, cmd string) (string, error) {
+	network := "tcp"
+	if strings.HasPrefix(d.addr, "/") {
+		network = "unix"
+	}
+	var dial net.Dialer
+	conn, err := dial.DialContext(ctx, network, d.addr)
+	if err != nil {
+		return "", fmt.Errorf("haproxy socket: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := fmt.Fprintf(conn, "%s\n", cmd); err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		b.WriteString(scanner.Text())
+		b.WriteByte('\n')
+	}
+	return b.String(), scanner.Err()
+}
+
+func (d *haproxyDrainer) Drain(ctx context.Context, server string) error {
+	out, err := d.command(ctx, fmt.Sprintf("set server %s/%s state drain", d.backend, server))
+	if err != nil {
+		return err
+	}
+	if msg := strings.TrimSpace(out); msg != "" {
+		return fmt.Errorf("haproxy: %s", msg)
+	}
+	return nil
+}
+
+func (d *haproxyDrainer) Release(ctx context.Context, server string) error {
+	_, err := d.command(ctx, fmt.Sprintf("set server %s/%s state ready", d.backend, server))
+	return err
+}
+
+func (d *haproxyDrainer) Connections(ctx context.Context, server string) (int, error) {
+	out, err := d.command(ctx, "show stat")
+	if err != nil {
+		return 0, err
+	}
+	n, err := haproxyCurrentSessions(out, d.backend, server)
+	if err != nil {
+		return 0, fmt.Errorf("haproxy: %w", err)
+	}
+	return n, nil
+}
+
+// haproxyCurrentSessions pulls the scur column for one server out of
+// "show stat" CSV output.
+func haproxyCurrentSessions(csv, backend, server string) (int, error) {
+	const scurColumn = 4 // pxname,svname,qcur,qmax,scur,...
+	for _, line := range strings.Split(csv, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) <= scurColumn || fields[0] != backend || fields[1] != server {
+			continue
+		}
+		return strconv.Atoi(fields[scurColumn])
+	}
+	return 0, fmt.Errorf("server %s/%s not in show stat output", backend, server)
+}
+
+// nginxDrainer drives the NGINX Plus API.
+type nginxDrainer struct {
+	api      string // e.g. http://lb:8080/api/9
+	upstream string
+	client   *http.Client
+}
+
+func (d *nginxDrainer) Name() string { return "nginx" }
+
+type nginxPeer struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Active int    `json:"active"`
+}
+
+func (d *nginxDrainer) peers(ctx context.Context) ([]nginxPeer, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		d.api+"/http/upstreams/"+d.upstream+"/servers", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nginx api: %s", resp.Status)
+	}
+	var peers []nginxPeer
+	if err := json.NewDecoder(resp.Body).Decode(&peers); err != nil {
+		return nil, err
+	}
+	return peers, nil
+}
+
+func (d *nginxDrainer) peer(ctx context.Context, server string) (nginxPeer, error) {
+	peers, err := d.peers(ctx)
+	if err != nil {
+		return nginxPeer{}, err
+	}
+	for _, p := range peers {
+		if p.Name == server || strings.HasPrefix(p.Name, server+":") {
+			return p, nil
+		}
+	}
+	return nginxPeer{}, fmt.Errorf("nginx api: no server %q in upstream %s", server, d.upstream)
+}
+
+func (d *nginxDrainer) setDrain(ctx context.Context, server string, drain bool) error {
+	p, err := d.peer(ctx, server)
+	if err != nil {
+		return err
+	}
+	body := strings.NewReader(fmt.Sprintf(`{"drain":%t}`, drain))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch,
+		fmt.Sprintf("%s/http/upstreams/%s/servers/%d", d.api, d.upstream, p.ID), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nginx api: %s", resp.Status)
+	}
+	return nil
+}
+
+func (d *nginxDrainer) Drain(ctx context.Context, server string) error {
+	return d.setDrain(ctx, server, true)
+}
+
+func (d *nginxDrainer) Release(ctx context.Context, server string) error {
+	return d.setDrain(ctx, server, false)
+}
+
+func (d *nginxDrainer) Connections(ctx context.Context, server string) (int, error) {
+	p, err := d.peer(ctx, server)
+	if err != nil {
+		return 0, err
+	}
+	return p.Active, nil
+}
+
+// containerDrainer signals the victim container directly instead of a
+// load balancer: a unix signal (mode "signal") or an HTTP drain
+// endpoint (mode "http"). There is no connection count to watch, so
+// Connections reports 1 until the container exits on its own — an app
+// that shuts down once drained ends the wait early, one that keeps
+// running just uses the full grace period before compose removes it.
+type containerDrainer struct {
+	docker *dockerClient
+	signal string // unix signal for mode "signal"
+	url    string // printf template with the container name, for mode "http"
+	client *http.Client
+}
+
+func (d *containerDrainer) Name() string {
+	if d.signal != "" {
+		return "signal"
+	}
+	return "http"
+}
+
+func (d *containerDrainer) Drain(ctx context.Context, server string) error {
+	if d.signal != "" {
+		c, err := d.docker.findContainer(ctx, server)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("no container named %s", server)
+		}
+		return d.docker.Kill(ctx, c.ID, d.signal)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(d.url, server), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("drain endpoint: %s", resp.Status)
+	}
+	return nil
+}
+
+func (d *containerDrainer) Release(ctx context.Context, server string) error { return nil }
+
+func (d *containerDrainer) Connections(ctx context.Context, server string) (int, error) {
+	c, err := d.docker.findContainer(ctx, server)
+	if err != nil {
+		return 0, err
+	}
+	if c == nil || c.State != "running" {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// traefikDrainer cannot mark a backend down (the Traefik API is
+// read-only), but it can still delay the removal until the service's
+// open connections settle, which covers the common short-lived-request
+// case.
+type traefikDrainer struct {
+	url     string
+	service string
+	client  *http.Client
+}
+
+func (d *traefikDrainer) Name() string { return "traefik" }
+
+func (d *traefikDrainer) Drain(ctx context.Context, server string) error { return nil }
+
+func (d *traefikDrainer) Release(ctx context.Context, server string) error { return nil }
+
+func (d *traefikDrainer) Connections(ctx context.Context, server string) (int, error) {
+	text, err := fetchText(ctx, d.client, d.url)
+	if err != nil {
+		return 0, fmt.Errorf("traefik metrics: %w", err)
+	}
+	open, found := sumPromCounter(text, "traefik_service_open_connections", `service="`+d.service)
+	if !found {
+		return 0, fmt.Errorf("traefik metrics: no open connections gauge for service %q", d.service)
+	}
+	return int(open), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHAProxyCurrentSessions(t *testing.T) {
+	csv := "# pxname,svname,qcur,qmax,scur,smax\n" +
+		"web,FRONTEND,,,12,40\n" +
+		"app,web-1,0,0,3,9\n" +
+		"app,web-2,0,0,0,4\n" +
+		"app,BACKEND,0,0,3,9\n"
+
+	if n, err := haproxyCurrentSessions(csv, "app", "web-1"); err != nil || n != 3 {
+		t.Errorf("web-1 = %d, %v; want 3", n, err)
+	}
+	if n, err := haproxyCurrentSessions(csv, "app", "web-2"); err != nil || n != 0 {
+		t.Errorf("web-2 = %d, %v; want 0", n, err)
+	}
+	if _, err := haproxyCurrentSessions(csv, "app", "web-9"); err == nil {
+		t.Error("missing server not reported")
+	}
+}
+
+func TestNginxDrainer(t *testing.T) {
+	var drained bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/9/http/upstreams/app/servers":
+			json.NewEncoder(w).Encode([]nginxPeer{
+				{ID: 0, Name: "web-1:80", Active: 2},
+				{ID: 1, Name: "web-2:80", Active: 0},
+			})
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/9/http/upstreams/app/servers/0":
+			drained = true
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	d := &nginxDrainer{api: srv.URL + "/api/9", upstream: "app", client: srv.Client()}
+	ctx := context.Background()
+
+	if err := d.Drain(ctx, "web-1"); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if !drained {
+		t.Error("PATCH for the right peer never arrived")
+	}
+	if n, err := d.Connections(ctx, "web-1"); err != nil || n != 2 {
+		t.Errorf("Connections(web-1) = %d, %v; want 2", n, err)
+	}
+	if err := d.Drain(ctx, "web-9"); err == nil {
+		t.Error("draining an unknown server succeeded")
+	}
+}
+
+func TestNewDrainer(t *testing.T) {
+	if d, err := newDrainer(&config{}, nil); err != nil || d != nil {
+		t.Errorf("empty mode: %v, %v", d, err)
+	}
+	if _, err := newDrainer(&config{drainMode: "haproxy"}, nil); err == nil {
+		t.Error("haproxy without addr/backend accepted")
+	}
+	if _, err := newDrainer(&config{drainMode: "f5"}, nil); err == nil {
+		t.Error("unknown mode accepted")
+	}
+	d, err := newDrainer(&config{drainMode: "haproxy", haproxyAddr: "lb:9999", haproxyBackend: "app", drainTimeout: time.Second}, nil)
+	if err != nil || d.Name() != "haproxy" {
+		t.Errorf("haproxy drainer: %v, %v", d, err)
+	}
+	if _, err := newDrainer(&config{drainMode: "http"}, nil); err == nil {
+		t.Error("http mode without -drain-http-url accepted")
+	}
+	d, err = newDrainer(&config{drainMode: "signal", drainSignal: "SIGTERM"}, nil)
+	if err != nil || d.Name() != "signal" {
+		t.Errorf("signal drainer: %v, %v", d, err)
+	}
+}
+
+func TestAutoscalerDrainServerName(t *testing.T) {
+	a := &autoscaler{cfg: &config{project: "shop", service: "web"}}
+	if got := a.drainServerName(3); got != "shop-web-3" {
+		t.Errorf("default name = %q", got)
+	}
+	a.cfg.drainServer = "backend-%d"
+	if got := a.drainServerName(3); got != "backend-3" {
+		t.Errorf("templated name = %q", got)
+	}
+}
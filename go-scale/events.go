@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+)
+
+// scaleEvent is one attempted scale action. Its ID ties together the
+// log lines, the webhook payload and the status API record, so "what
+// happened during event 7f3a" can be answered from any sink.
+type scaleEvent struct {
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+	From    int       `json:"from"`
+	To      int       `json:"to"`
+	CPU     float64   `json:"cpu"`
+	Mem     float64   `json:"mem"`
+	Outcome string    `json:"outcome"` // applied, vetoed, rejected, failed, hook-failed or unverified
+}
+
+// eventHistorySize bounds the in-memory event ring kept for the API.
+const eventHistorySize = 50
+
+// newEventID returns a short random hex ID, unique enough for log
+// correlation.
+func newEventID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+func (a *autoscaler) recordEvent(e scaleEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, e)
+	if len(a.events) > eventHistorySize {
+		a.events = a.events[len(a.events)-eventHistorySize:]
+	}
+	self.countDecision(e.Outcome)
+	// The ring answers "what happened recently"; the export file answers
+	// "how often did we scale last week" after the process restarted.
+	if a.exportW != nil {
+		if err := json.NewEncoder(a.exportW).Encode(e); err != nil {
+			log.Printf("✗ [%s] history export: %v", e.ID, err)
+		}
+	}
+	a.stream.publish("scale", e)
+	if a.notify != nil {
+		e := e
+		a.notify.send(notification{
+			Time: e.Time, Project: a.cfg.project, Service: a.cfg.service,
+			Kind:    "scale",
+			Message: fmt.Sprintf("scale %d -> %d (%s)", e.From, e.To, e.Outcome),
+			Event:   &e,
+		})
+	}
+}
+
+// recentEvents returns the event history, newest last.
+func (a *autoscaler) recentEvents() []scaleEvent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]scaleEvent, len(a.events))
+	copy(out, a.events)
+	return out
+}
+
+// rateLimitReason reports why the proposed change would breach the
+// scaling rate limits, or "" when it may proceed. The limits brake
+// oscillation storms from misconfigured thresholds: a cap on applied
+// events per hour, and a cap on replicas added per 10 minutes.
+func (a *autoscaler) rateLimitReason(now time.Time, from, to int) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var lastHour, addedRecently int
+	for _, e := range a.events {
+		if e.Outcome != "applied" {
+			continue
+		}
+		if now.Sub(e.Time) <= time.Hour {
+			lastHour++
+		}
+		if now.Sub(e.Time) <= 10*time.Minute && e.To > e.From {
+			addedRecently += e.To - e.From
+		}
+	}
+	if a.cfg.maxEventsPerHour > 0 && lastHour >= a.cfg.maxEventsPerHour {
+		return fmt.Sprintf("%d scale events in the last hour (limit %d)", lastHour, a.cfg.maxEventsPerHour)
+	}
+	if a.cfg.maxAddedPer10m > 0 && to > from && addedRecently+to-from > a.cfg.maxAddedPer10m {
+		return fmt.Sprintf("%d replicas already added in the last 10m (limit %d)", addedRecently, a.cfg.maxAddedPer10m)
+	}
+	return ""
+}
+
+// writeEventsCSV renders events in CSV, one row per event, for the
+// control API's format=csv. Spreadsheet-friendly: timestamps are
+// RFC 3339, percentages plain numbers.
+func writeEventsCSV(w io.Writer, events []scaleEvent) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "time", "from", "to", "cpu", "mem", "outcome"}); err != nil {
+		return err
+	}
+	for _, e := range events {
+		rec := []string{
+			e.ID,
+			e.Time.Format(time.RFC3339),
+			strconv.Itoa(e.From),
+			strconv.Itoa(e.To),
+			strconv.FormatFloat(e.CPU, 'f', 1, 64),
+			strconv.FormatFloat(e.Mem, 'f', 1, 64),
+			e.Outcome,
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (a *autoscaler) findEvent(id string) (scaleEvent, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, e := range a.events {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return scaleEvent{}, false
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventIDs(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := newEventID()
+		if len(id) != 8 {
+			t.Fatalf("id %q has wrong length", id)
+		}
+		if seen[id] {
+			t.Fatalf("id %q repeated", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestEventHistory(t *testing.T) {
+	a := &autoscaler{cfg: &config{}}
+
+	if _, ok := a.findEvent("none"); ok {
+		t.Error("empty history found an event")
+	}
+
+	for i := 0; i < eventHistorySize+10; i++ {
+		a.recordEvent(scaleEvent{ID: newEventID(), From: i, To: i + 1, Outcome: "applied"})
+	}
+	events := a.recentEvents()
+	if len(events) != eventHistorySize {
+		t.Fatalf("history size = %d, want %d", len(events), eventHistorySize)
+	}
+	// Oldest entries were evicted; the newest survives and is findable.
+	newest := events[len(events)-1]
+	if newest.From != eventHistorySize+9 {
+		t.Errorf("newest event From = %d", newest.From)
+	}
+	if got, ok := a.findEvent(newest.ID); !ok || got.To != newest.To {
+		t.Errorf("findEvent(%s) = %+v, %v", newest.ID, got, ok)
+	}
+}
+
+func TestHistoryExport(t *testing.T) {
+	var buf bytes.Buffer
+	a := &autoscaler{cfg: &config{}, exportW: &buf}
+	a.recordEvent(scaleEvent{ID: "aaaa", From: 1, To: 2, Outcome: "applied"})
+	a.recordEvent(scaleEvent{ID: "bbbb", From: 2, To: 1, Outcome: "vetoed"})
+
+	sc := bufio.NewScanner(&buf)
+	var exported []scaleEvent
+	for sc.Scan() {
+		var e scaleEvent
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("bad export line %q: %v", sc.Text(), err)
+		}
+		exported = append(exported, e)
+	}
+	if len(exported) != 2 || exported[0].ID != "aaaa" || exported[1].Outcome != "vetoed" {
+		t.Errorf("exported = %+v", exported)
+	}
+}
+
+func TestWriteEventsCSV(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := writeEventsCSV(&buf, []scaleEvent{
+		{ID: "aaaa", Time: at, From: 2, To: 3, CPU: 91.3, Mem: 40, Outcome: "applied"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines:\n%s", len(lines), buf.String())
+	}
+	if lines[0] != "id,time,from,to,cpu,mem,outcome" {
+		t.Errorf("header = %q", lines[0])
+	}
+	if lines[1] != "aaaa,2024-03-01T12:00:00Z,2,3,91.3,40.0,applied" {
+		t.Errorf("row = %q", lines[1])
+	}
+}
+
+func TestRateLimitReason(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{maxEventsPerHour: 2, maxAddedPer10m: 3}}
+
+	if got := a.rateLimitReason(now, 2, 3); got != "" {
+		t.Errorf("empty history limited: %q", got)
+	}
+
+	// Two applied events within the hour hits the hourly cap; a vetoed
+	// one and an old one don't count.
+	a.recordEvent(scaleEvent{Time: now.Add(-2 * time.Hour), From: 1, To: 2, Outcome: "applied"})
+	a.recordEvent(scaleEvent{Time: now.Add(-30 * time.Minute), From: 2, To: 3, Outcome: "vetoed"})
+	a.recordEvent(scaleEvent{Time: now.Add(-30 * time.Minute), From: 2, To: 3, Outcome: "applied"})
+	if got := a.rateLimitReason(now, 3, 4); got != "" {
+		t.Errorf("under the caps limited: %q", got)
+	}
+	a.recordEvent(scaleEvent{Time: now.Add(-20 * time.Minute), From: 3, To: 4, Outcome: "applied"})
+	if got := a.rateLimitReason(now, 4, 5); got == "" {
+		t.Error("hourly cap not enforced")
+	}
+
+	// Replicas-added cap: 2 added in the window, adding 2 more breaches
+	// the limit of 3, but a scale-down is still allowed.
+	a.cfg.maxEventsPerHour = 0
+	a.recordEvent(scaleEvent{Time: now.Add(-5 * time.Minute), From: 4, To: 6, Outcome: "applied"})
+	if got := a.rateLimitReason(now, 6, 8); got == "" {
+		t.Error("added-replicas cap not enforced")
+	}
+	if got := a.rateLimitReason(now, 6, 7); got != "" {
+		t.Errorf("within added-replicas cap limited: %q", got)
+	}
+	if got := a.rateLimitReason(now, 6, 5); got != "" {
+		t.Errorf("scale-down limited by added-replicas cap: %q", got)
+	}
+}
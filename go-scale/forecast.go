@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// loadHistory is a seasonal-average forecaster: one bucket per weekday
+// and hour holds a moving average of observed utilization. Recurring
+// peaks (the 9:00 Monday spike) show up in the matching bucket, so the
+// autoscaler can ramp up shortly before they return.
+type loadHistory struct {
+	path string
+
+	mu      sync.Mutex
+	Buckets [7][24]seasonalBucket `json:"buckets"`
+	dirty   bool
+}
+
+type seasonalBucket struct {
+	Avg     float64 `json:"avg"`
+	Samples int     `json:"samples"`
+}
+
+// ewmaWindow caps the effective sample weight so the average keeps
+// adapting instead of fossilizing after months of data.
+const ewmaWindow = 20
+
+// forecastMinSamples is how many observations a bucket needs before its
+// average is trusted for pre-scaling.
+const forecastMinSamples = 3
+
+// newLoadHistory loads the history file, or starts empty when it does
+// not exist yet.
+func newLoadHistory(path string) (*loadHistory, error) {
+	h := &loadHistory{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// record folds one utilization observation into its seasonal bucket.
+func (h *loadHistory) record(t time.Time, load float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b := &h.Buckets[t.Weekday()][t.Hour()]
+	n := b.Samples + 1
+	if n > ewmaWindow {
+		n = ewmaWindow
+	}
+	b.Avg += (load - b.Avg) / float64(n)
+	b.Samples++
+	h.dirty = true
+}
+
+// forecast returns the expected utilization at t, once the bucket has
+// seen enough history to mean anything.
+func (h *loadHistory) forecast(t time.Time) (float64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	b := h.Buckets[t.Weekday()][t.Hour()]
+	if b.Samples < forecastMinSamples {
+		return 0, false
+	}
+	return b.Avg, true
+}
+
+// save persists the history when it changed since the last save. Errors
+// are logged, not fatal: losing the forecast degrades to plain reactive
+// scaling.
+func (h *loadHistory) save() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.dirty {
+		return
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		log.Printf("✗ history: %v", err)
+		return
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		log.Printf("✗ history: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		log.Printf("✗ history: %v", err)
+		return
+	}
+	h.dirty = false
+}
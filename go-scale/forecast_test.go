@@ -0,0 +1,70 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadHistoryForecast(t *testing.T) {
+	h := &loadHistory{}
+	monday9 := time.Date(2024, 1, 8, 9, 15, 0, 0, time.UTC) // a Monday
+
+	if _, ok := h.forecast(monday9); ok {
+		t.Error("empty history produced a forecast")
+	}
+
+	for i := 0; i < forecastMinSamples; i++ {
+		h.record(monday9.AddDate(0, 0, -7*i), 90)
+	}
+	fc, ok := h.forecast(monday9)
+	if !ok || fc != 90 {
+		t.Errorf("forecast = %.1f, %v; want 90", fc, ok)
+	}
+
+	// Other buckets stay independent.
+	if _, ok := h.forecast(monday9.Add(2 * time.Hour)); ok {
+		t.Error("neighboring hour bucket leaked samples")
+	}
+	if _, ok := h.forecast(monday9.AddDate(0, 0, 1)); ok {
+		t.Error("tuesday bucket leaked samples")
+	}
+
+	// The moving average keeps adapting after the window fills.
+	for i := 0; i < ewmaWindow*3; i++ {
+		h.record(monday9, 20)
+	}
+	if fc, _ := h.forecast(monday9); fc > 30 {
+		t.Errorf("average %.1f did not track the new level", fc)
+	}
+}
+
+func TestLoadHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h, err := newLoadHistory(path)
+	if err != nil {
+		t.Fatalf("newLoadHistory: %v", err)
+	}
+	at := time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC)
+	for i := 0; i < forecastMinSamples; i++ {
+		h.record(at, 75)
+	}
+	h.save()
+
+	reloaded, err := newLoadHistory(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	fc, ok := reloaded.forecast(at)
+	if !ok || fc != 75 {
+		t.Errorf("reloaded forecast = %.1f, %v; want 75", fc, ok)
+	}
+
+	// A clean (non-dirty) history does not rewrite the file.
+	before, _ := filepath.Glob(path)
+	reloaded.save()
+	after, _ := filepath.Glob(path)
+	if len(before) != len(after) {
+		t.Error("save of clean history changed the directory")
+	}
+}
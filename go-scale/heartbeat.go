@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Machine-readable heartbeats. With -heartbeat-format json every poll
+// writes one JSON object to stdout — logs stay on stderr, so the
+// stream pipes straight into jq or a log-based alerting rule without
+// regex over the human lines.
+
+// heartbeat is one poll's numbers as emitted on stdout.
+type heartbeat struct {
+	Time       time.Time `json:"time"`
+	Project    string    `json:"project"`
+	Service    string    `json:"service"`
+	Replicas   int       `json:"replicas"`
+	CPU        float64   `json:"cpu"`
+	Mem        float64   `json:"mem"`
+	MetricName string    `json:"metric_name,omitempty"`
+	Metric     *float64  `json:"metric,omitempty"` // absent when no source or the sample failed
+}
+
+// emitHeartbeat writes the poll's heartbeat when JSON format is on.
+// The text format stays with the verbose "i ..." log lines.
+func (a *autoscaler) emitHeartbeat(replicas int, avgCPU, avgMem float64, metric *float64) {
+	if a.cfg.heartbeatFormat != "json" {
+		return
+	}
+	hb := heartbeat{
+		Time:     a.now(),
+		Project:  a.cfg.project,
+		Service:  a.cfg.service,
+		Replicas: replicas,
+		CPU:      avgCPU,
+		Mem:      avgMem,
+		Metric:   metric,
+	}
+	if a.metric != nil {
+		hb.MetricName = a.metric.Name()
+	}
+	raw, err := json.Marshal(hb)
+	if err != nil {
+		return
+	}
+	w := a.heartbeatW
+	if w == nil {
+		w = io.Writer(os.Stdout)
+	}
+	fmt.Fprintln(w, string(raw))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEmitHeartbeatJSON(t *testing.T) {
+	var buf bytes.Buffer
+	a := &autoscaler{
+		cfg:        &config{project: "demo", service: "web", heartbeatFormat: "json"},
+		now:        func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) },
+		heartbeatW: &buf,
+	}
+	v := 17.0
+	a.emitHeartbeat(3, 42.5, 61.2, &v)
+
+	var hb heartbeat
+	if err := json.Unmarshal(buf.Bytes(), &hb); err != nil {
+		t.Fatalf("heartbeat is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if hb.Project != "demo" || hb.Service != "web" || hb.Replicas != 3 {
+		t.Errorf("heartbeat = %+v", hb)
+	}
+	if hb.CPU != 42.5 || hb.Mem != 61.2 {
+		t.Errorf("heartbeat load = %g/%g, want 42.5/61.2", hb.CPU, hb.Mem)
+	}
+	if hb.Metric == nil || *hb.Metric != 17 {
+		t.Errorf("heartbeat metric = %v, want 17", hb.Metric)
+	}
+}
+
+func TestEmitHeartbeatTextSilent(t *testing.T) {
+	var buf bytes.Buffer
+	a := &autoscaler{
+		cfg:        &config{project: "demo", service: "web", heartbeatFormat: "text"},
+		now:        time.Now,
+		heartbeatW: &buf,
+	}
+	a.emitHeartbeat(3, 42.5, 61.2, nil)
+	if buf.Len() != 0 {
+		t.Errorf("text format wrote to the heartbeat stream: %q", buf.String())
+	}
+}
+
+func TestEmitHeartbeatOmitsFailedMetric(t *testing.T) {
+	var buf bytes.Buffer
+	a := &autoscaler{
+		cfg:        &config{project: "demo", service: "web", heartbeatFormat: "json"},
+		now:        time.Now,
+		heartbeatW: &buf,
+	}
+	a.emitHeartbeat(3, 42.5, 61.2, nil)
+	var raw map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw["metric"]; ok {
+		t.Error("metric key present without a sample")
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// hookTimeout bounds each hook script run so a hung hook cannot stall
+// the poll loop for good.
+const hookTimeout = 30 * time.Second
+
+// hookEnv describes a scale event to hook scripts through GOSCALE_*
+// environment variables.
+func hookEnv(cfg *config, e scaleEvent) []string {
+	direction := "down"
+	if e.To > e.From {
+		direction = "up"
+	}
+	return append(os.Environ(),
+		"GOSCALE_EVENT_ID="+e.ID,
+		"GOSCALE_PROJECT="+cfg.project,
+		"GOSCALE_SERVICE="+cfg.service,
+		"GOSCALE_DIRECTION="+direction,
+		fmt.Sprintf("GOSCALE_FROM=%d", e.From),
+		fmt.Sprintf("GOSCALE_TO=%d", e.To),
+		fmt.Sprintf("GOSCALE_CPU=%.1f", e.CPU),
+		fmt.Sprintf("GOSCALE_MEM=%.1f", e.Mem),
+	)
+}
+
+// runHook executes one hook script through the shell — so hooks can be
+// one-liners as well as script paths — with the event in the
+// environment.
+func runHook(cfg *config, script string, e scaleEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+	cmd := shellCommand(ctx, script)
+	cmd.Env = hookEnv(cfg, e)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
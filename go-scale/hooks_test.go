@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunHookEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env")
+	cfg := &config{project: "shop", service: "web"}
+	e := scaleEvent{ID: "abcd1234", From: 2, To: 4, CPU: 91.5, Mem: 40}
+
+	err := runHook(cfg, "env | grep ^GOSCALE_ | sort > "+out, e)
+	if err != nil {
+		t.Fatalf("runHook: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"GOSCALE_EVENT_ID=abcd1234",
+		"GOSCALE_PROJECT=shop",
+		"GOSCALE_SERVICE=web",
+		"GOSCALE_DIRECTION=up",
+		"GOSCALE_FROM=2",
+		"GOSCALE_TO=4",
+		"GOSCALE_CPU=91.5",
+		"GOSCALE_MEM=40.0",
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("hook environment missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunHookFailure(t *testing.T) {
+	cfg := &config{}
+	err := runHook(cfg, "echo warming failed >&2; exit 3", scaleEvent{From: 3, To: 2})
+	if err == nil || !strings.Contains(err.Error(), "warming failed") {
+		t.Fatalf("runHook error = %v, want stderr included", err)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/iamdanielv/utils/internal/format"
+)
+
+// hostGuard blocks scale-ups when the Docker host itself is saturated,
+// so we don't pile more replicas onto an overloaded box. Scale-downs
+// are never blocked: shedding load is exactly what a loaded host wants.
+type hostGuard struct {
+	// loadMax is the 1-minute load average above which scale-ups are
+	// held. Zero disables the check.
+	loadMax float64
+	// memFreeMin is the minimum available memory in bytes required for
+	// a scale-up. Zero disables the check.
+	memFreeMin uint64
+}
+
+func (g *hostGuard) enabled() bool {
+	return g.loadMax > 0 || g.memFreeMin > 0
+}
+
+// check returns a non-empty reason when the host is too loaded for a
+// scale-up. Read errors fail open with a log line: a broken /proc is
+// no reason to stop scaling entirely.
+func (g *hostGuard) check() string {
+	if g.loadMax > 0 {
+		load, err := readLoadAvg("/proc/loadavg")
+		if err == nil && load > g.loadMax {
+			return fmt.Sprintf("host load %.2f above limit %.2f", load, g.loadMax)
+		}
+	}
+	if g.memFreeMin > 0 {
+		avail, err := readMemAvailable("/proc/meminfo")
+		if err == nil && avail < g.memFreeMin {
+			return fmt.Sprintf("host has %s available, below %s minimum",
+				format.Bytes(avail), format.Bytes(g.memFreeMin))
+		}
+	}
+	return ""
+}
+
+// readLoadAvg parses the 1-minute load average from a loadavg file.
+func readLoadAvg(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("malformed loadavg %q", string(data))
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// readMemAvailable parses MemAvailable (in bytes) from a meminfo file.
+func readMemAvailable(path string) (uint64, error) {
+	return readMeminfoField(path, "MemAvailable:")
+}
+
+// readMemTotal parses MemTotal (in bytes) from a meminfo file.
+func readMemTotal(path string) (uint64, error) {
+	return readMeminfoField(path, "MemTotal:")
+}
+
+// readMeminfoField finds one meminfo line by its prefix and returns
+// the kB value converted to bytes.
+func readMeminfoField(path, prefix string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			break
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return kb * 1024, nil
+	}
+	return 0, fmt.Errorf("no %s in %s", strings.TrimSuffix(prefix, ":"), path)
+}
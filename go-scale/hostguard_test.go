@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadLoadAvg(t *testing.T) {
+	path := writeTemp(t, "loadavg", "2.41 1.82 1.50 3/512 12345\n")
+	load, err := readLoadAvg(path)
+	if err != nil {
+		t.Fatalf("readLoadAvg: %v", err)
+	}
+	if load != 2.41 {
+		t.Errorf("load = %v, want 2.41", load)
+	}
+}
+
+func TestReadMemAvailable(t *testing.T) {
+	meminfo := "MemTotal:       16303456 kB\nMemFree:          842040 kB\nMemAvailable:    4194304 kB\n"
+	path := writeTemp(t, "meminfo", meminfo)
+	avail, err := readMemAvailable(path)
+	if err != nil {
+		t.Fatalf("readMemAvailable: %v", err)
+	}
+	if want := uint64(4194304) * 1024; avail != want {
+		t.Errorf("avail = %d, want %d", avail, want)
+	}
+}
+
+func TestReadMemAvailableMissing(t *testing.T) {
+	path := writeTemp(t, "meminfo", "MemTotal: 1 kB\n")
+	if _, err := readMemAvailable(path); err == nil {
+		t.Error("expected error for meminfo without MemAvailable")
+	}
+}
+
+func TestHostGuardDisabled(t *testing.T) {
+	var g hostGuard
+	if g.enabled() {
+		t.Error("zero-value guard should be disabled")
+	}
+	if reason := g.check(); reason != "" {
+		t.Errorf("disabled guard returned %q", reason)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// influxExporter writes per-poll samples to an InfluxDB write endpoint
+// using line protocol, building a historical record of load and replica
+// counts for capacity planning. It works against both 1.x (/write?db=)
+// and 2.x (/api/v2/write) URLs; the token is optional for 1.x.
+type influxExporter struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newInfluxExporter(url, token string) *influxExporter {
+	return &influxExporter{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// containerPoint is one container's contribution to a poll.
+type containerPoint struct {
+	id     string
+	sample *statsSample
+}
+
+// export ships one poll's samples; it runs in the caller's goroutine
+// and logs rather than returns errors, since a failed export must not
+// affect scaling.
+func (e *influxExporter) export(project, service string, points []containerPoint, avgCPU, avgMem float64, replicas int) {
+	if e == nil {
+		return
+	}
+	body := buildInfluxLines(project, service, points, avgCPU, avgMem, replicas, time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, strings.NewReader(body))
+	if err != nil {
+		log.Printf("✗ influx: %v", err)
+		return
+	}
+	if e.token != "" {
+		req.Header.Set("Authorization", "Token "+e.token)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		log.Printf("✗ influx: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("✗ influx: write returned %s", resp.Status)
+	}
+}
+
+// buildInfluxLines renders line protocol: one point per container plus
+// an aggregate point per poll.
+func buildInfluxLines(project, service string, points []containerPoint, avgCPU, avgMem float64, replicas int, now time.Time) string {
+	var b strings.Builder
+	ts := now.UnixNano()
+	tags := fmt.Sprintf("project=%s,service=%s", escapeInfluxTag(project), escapeInfluxTag(service))
+	for _, p := range points {
+		fmt.Fprintf(&b, "goscale_container,%s,container=%s cpu=%g,mem=%g %d\n",
+			tags, escapeInfluxTag(shortID(p.id)), p.sample.CPUPercent, p.sample.MemPercent, ts)
+	}
+	fmt.Fprintf(&b, "goscale,%s cpu=%g,mem=%g,replicas=%di %d\n", tags, avgCPU, avgMem, replicas, ts)
+	return b.String()
+}
+
+// escapeInfluxTag escapes the characters line protocol reserves in tag
+// values.
+func escapeInfluxTag(s string) string {
+	return strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `).Replace(s)
+}
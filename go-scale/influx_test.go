@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildInfluxLines(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	points := []containerPoint{
+		{id: "abcdef1234567890", sample: &statsSample{CPUPercent: 42.5, MemPercent: 10}},
+	}
+	out := buildInfluxLines("shop", "web", points, 42.5, 10, 3, now)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out)
+	}
+	wantContainer := "goscale_container,project=shop,service=web,container=abcdef123456 cpu=42.5,mem=10 1700000000000000000"
+	if lines[0] != wantContainer {
+		t.Errorf("container line = %q\nwant %q", lines[0], wantContainer)
+	}
+	wantAgg := "goscale,project=shop,service=web cpu=42.5,mem=10,replicas=3i 1700000000000000000"
+	if lines[1] != wantAgg {
+		t.Errorf("aggregate line = %q\nwant %q", lines[1], wantAgg)
+	}
+}
+
+func TestEscapeInfluxTag(t *testing.T) {
+	if got, want := escapeInfluxTag("my service,v=1"), `my\ service\,v\=1`; got != want {
+		t.Errorf("escapeInfluxTag = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// injection overrides collected metrics for a limited number of polls,
+// so scaling behavior and notifications can be rehearsed in staging
+// without generating real load.
+type injection struct {
+	cpu, mem, metric          float64
+	hasCPU, hasMem, hasMetric bool
+	polls                     int
+}
+
+// parseInjection parses "cpu=90,mem=50,metric=1200" style overrides.
+func parseInjection(spec string, polls int) (*injection, error) {
+	inj := &injection{polls: polls}
+	for _, part := range strings.Split(spec, ",") {
+		key, raw, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected name=value, got %q", part)
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad value in %q", part)
+		}
+		switch key {
+		case "cpu":
+			inj.cpu, inj.hasCPU = v, true
+		case "mem":
+			inj.mem, inj.hasMem = v, true
+		case "metric":
+			inj.metric, inj.hasMetric = v, true
+		default:
+			return nil, fmt.Errorf("unknown metric %q (want cpu, mem or metric)", key)
+		}
+	}
+	if inj.polls < 1 {
+		inj.polls = 1
+	}
+	return inj, nil
+}
+
+// setInjection arms (or, with nil, clears) the override window.
+func (a *autoscaler) setInjection(inj *injection) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.inject = inj
+}
+
+// takeInjection consumes one poll of the active override window and
+// returns this poll's overrides, or nil when none are armed.
+func (a *autoscaler) takeInjection() *injection {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.inject == nil || a.inject.polls <= 0 {
+		return nil
+	}
+	current := *a.inject
+	a.inject.polls--
+	if a.inject.polls == 0 {
+		a.inject = nil
+	}
+	return &current
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestParseInjection(t *testing.T) {
+	inj, err := parseInjection("cpu=90,mem=50,metric=1200", 5)
+	if err != nil {
+		t.Fatalf("parseInjection: %v", err)
+	}
+	if !inj.hasCPU || inj.cpu != 90 || !inj.hasMem || inj.mem != 50 || !inj.hasMetric || inj.metric != 1200 {
+		t.Errorf("parsed = %+v", inj)
+	}
+	if inj.polls != 5 {
+		t.Errorf("polls = %d", inj.polls)
+	}
+
+	inj, err = parseInjection("cpu=90", 0)
+	if err != nil || inj.polls != 1 {
+		t.Errorf("polls floor: %+v, %v", inj, err)
+	}
+
+	for _, bad := range []string{"cpu", "cpu=high", "disk=90", "=90"} {
+		if _, err := parseInjection(bad, 5); err == nil {
+			t.Errorf("parseInjection(%q) accepted", bad)
+		}
+	}
+}
+
+func TestTakeInjection(t *testing.T) {
+	a := &autoscaler{cfg: &config{}}
+
+	if got := a.takeInjection(); got != nil {
+		t.Errorf("unarmed takeInjection = %+v", got)
+	}
+
+	inj, _ := parseInjection("cpu=95", 2)
+	a.setInjection(inj)
+	if got := a.takeInjection(); got == nil || got.cpu != 95 {
+		t.Fatalf("first poll = %+v", got)
+	}
+	if got := a.takeInjection(); got == nil {
+		t.Fatal("second poll missing")
+	}
+	if got := a.takeInjection(); got != nil {
+		t.Errorf("window did not expire: %+v", got)
+	}
+
+	a.setInjection(inj)
+	a.setInjection(nil)
+	if got := a.takeInjection(); got != nil {
+		t.Errorf("cleared window still active: %+v", got)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/sdinstall"
+)
+
+// runInstall implements `go-scale install <flags...>`: it writes a
+// hardened per-project systemd unit whose ExecStart re-runs this binary
+// with the given flags, enables it, and verifies it comes up.
+func runInstall(args []string) {
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	unitDir := fs.String("unit-dir", "", "unit directory (default /etc/systemd/system)")
+	noEnable := fs.Bool("no-enable", false, "write the unit file but do not enable/start it")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: go-scale install [-unit-dir DIR] [-no-enable] -- <go-scale flags>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	runFlags := fs.Args()
+
+	// The run flags double as the unit's identity: find -project.
+	project := ""
+	for i, a := range runFlags {
+		if (a == "-project" || a == "--project") && i+1 < len(runFlags) {
+			project = runFlags[i+1]
+		}
+	}
+	if project == "" {
+		log.Fatalf("install: pass the go-scale flags after install, including -project")
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	unit := goscaleUnit(bin, project, runFlags)
+	path, err := sdinstall.Install(unit, *unitDir)
+	if err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	log.Printf("✓ wrote %s", path)
+	if *noEnable {
+		return
+	}
+	if err := sdinstall.Enable(unit.Name); err != nil {
+		log.Fatalf("install: %v", err)
+	}
+	if err := sdinstall.VerifyActive(unit.Name, 30*time.Second); err != nil {
+		log.Fatalf("install: unit enabled but %v", err)
+	}
+	log.Printf("✓ %s is active", unit.Name)
+}
+
+// goscaleUnit builds the supervised unit for one project: Type=notify
+// so systemd waits for our READY=1, and a watchdog the poll loops feed.
+func goscaleUnit(bin, project string, runFlags []string) sdinstall.Unit {
+	return sdinstall.Unit{
+		Name:        "goscale-" + project + ".service",
+		Description: "go-scale autoscaler for compose project " + project,
+		ExecStart:   bin + " " + strings.Join(runFlags, " "),
+		After:       []string{"docker.service"},
+		Type:        "notify",
+		WatchdogSec: 90 * time.Second,
+	}
+}
+
+// runSystemdInstall implements `go-scale systemd-install -- <flags>`:
+// it prints the unit `install` would write to stdout, for operators who
+// manage unit files through their own tooling.
+func runSystemdInstall(args []string) {
+	fs := flag.NewFlagSet("systemd-install", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: go-scale systemd-install -- <go-scale flags>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	runFlags := fs.Args()
+	if len(runFlags) == 0 {
+		// No flags given: show a complete example to adapt.
+		runFlags = []string{"-project", "myproject", "-service", "web", "-min", "1", "-max", "4"}
+	}
+
+	project := "myproject"
+	for i, a := range runFlags {
+		if (a == "-project" || a == "--project") && i+1 < len(runFlags) {
+			project = runFlags[i+1]
+		}
+	}
+	bin, err := os.Executable()
+	if err != nil {
+		bin = "/usr/local/bin/go-scale"
+	}
+	fmt.Print(sdinstall.Render(goscaleUnit(bin, project, runFlags)))
+}
@@ -0,0 +1,94 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// These tests exercise the real Docker daemon and compose CLI. Run them
+// with:
+//
+//	go test -tags integration ./go-scale
+//
+// They are skipped automatically when no daemon is reachable.
+
+const itestCompose = `services:
+  web:
+    image: busybox
+    command: sleep 3600
+`
+
+func requireDocker(t *testing.T) *dockerClient {
+	t.Helper()
+	c, err := newDockerClient()
+	if err != nil {
+		t.Skipf("docker client: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.negotiateVersion(ctx); err != nil {
+		t.Skipf("docker daemon not reachable: %v", err)
+	}
+	return c
+}
+
+func TestRealComposeScaling(t *testing.T) {
+	docker := requireDocker(t)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte(itestCompose), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project := fmt.Sprintf("goscale-itest-%d", os.Getpid())
+
+	compose := func(args ...string) error {
+		cmd := exec.Command("docker", append([]string{"compose", "-p", project}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%v: %s", err, out)
+		}
+		return nil
+	}
+	if err := compose("up", "-d"); err != nil {
+		t.Skipf("compose not available: %v", err)
+	}
+	t.Cleanup(func() { compose("down", "-v", "--remove-orphans") })
+
+	ctx := context.Background()
+
+	// scaleTo drives the same code path the autoscaler uses.
+	cwd, _ := os.Getwd()
+	os.Chdir(dir)
+	defer os.Chdir(cwd)
+	if err := scaleTo(&config{project: project}, "web", 3); err != nil {
+		t.Fatalf("scaleTo: %v", err)
+	}
+
+	want := 3
+	var got int
+	deadline := time.Now().Add(60 * time.Second)
+	for time.Now().Before(deadline) {
+		containers, err := docker.getServiceContainers(ctx, &config{project: project}, "web")
+		if err != nil {
+			t.Fatalf("getServiceContainers: %v", err)
+		}
+		got = len(containers)
+		if got >= want {
+			// Real stats must decode through the negotiated schema.
+			if _, err := docker.containerStats(ctx, containers[0].ID); err != nil && err != errIncompleteSample {
+				t.Errorf("containerStats: %v", err)
+			}
+			return
+		}
+		time.Sleep(2 * time.Second)
+	}
+	t.Fatalf("replica count = %d after scale, want %d", got, want)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// labelPrefix marks threshold overrides set as compose labels, so the
+// scaling policy can live in docker-compose.yml next to the service:
+//
+//	labels:
+//	  goscale.cpu-up: "75"
+//	  goscale.max: "8"
+//
+// Label names are the same reloadable settings -config accepts.
+const labelPrefix = "goscale."
+
+// applyLabelOverrides reads goscale.* labels off the service's
+// containers and applies them to the live config. Overrides apply
+// all-or-nothing, like a config reload, and are re-applied only when
+// the label set changes (a redeploy), with the diff logged.
+func (a *autoscaler) applyLabelOverrides(containers []containerInfo) {
+	labels := map[string]string{}
+	for _, c := range containers {
+		for k, v := range c.Labels {
+			if strings.HasPrefix(k, labelPrefix) {
+				labels[strings.TrimPrefix(k, labelPrefix)] = v
+			}
+		}
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sig := ""
+	for _, name := range names {
+		sig += name + "=" + labels[name] + "\n"
+	}
+	if sig == a.labelSig {
+		return
+	}
+	a.labelSig = sig
+
+	if err := applyLabelSettings(a.cfg, names, labels); err != nil {
+		log.Printf("✗ %s label override: %v (keeping current settings)", a.cfg.service, err)
+	}
+}
+
+// applyLabelSettings validates the whole override set against a
+// scratch copy before touching cfg, then applies it and logs the diff.
+func applyLabelSettings(cfg *config, names []string, labels map[string]string) error {
+	scratch := *cfg
+	for _, name := range names {
+		if _, _, err := applyConfigSetting(&scratch, name, labels[name]); err != nil {
+			return fmt.Errorf("label %s%s: %w", labelPrefix, name, err)
+		}
+	}
+	if err := validateReload(&scratch); err != nil {
+		return err
+	}
+	for _, name := range names {
+		oldV, newV, _ := applyConfigSetting(cfg, name, labels[name])
+		if oldV != newV {
+			log.Printf("i label override: %s %s -> %s", name, oldV, newV)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func labelTestScaler() *autoscaler {
+	return newAutoscaler(&config{
+		project: "demo", service: "web",
+		minReplicas: 1, maxReplicas: 5,
+		cpuUp: 80, cpuDown: 20, memUp: 80, memDown: 20,
+		scaleUpChecks: 1, scaleDownChecks: 3,
+		cooldown: 2 * time.Minute,
+	}, nil)
+}
+
+func TestApplyLabelOverrides(t *testing.T) {
+	a := labelTestScaler()
+	containers := []containerInfo{
+		{ID: "1", Labels: map[string]string{
+			"com.docker.compose.service": "web",
+			"goscale.cpu-up":             "70",
+			"goscale.max":                "8",
+		}},
+		{ID: "2", Labels: map[string]string{"goscale.cpu-up": "70"}},
+	}
+	a.applyLabelOverrides(containers)
+	if a.cfg.cpuUp != 70 || a.cfg.maxReplicas != 8 {
+		t.Errorf("cfg after labels: cpu-up %g, max %d", a.cfg.cpuUp, a.cfg.maxReplicas)
+	}
+
+	// Same label set again: no re-apply (would re-log the diff).
+	a.cfg.cpuUp = 85 // e.g. changed by a config reload
+	a.applyLabelOverrides(containers)
+	if a.cfg.cpuUp != 85 {
+		t.Error("unchanged labels were re-applied")
+	}
+
+	// A changed label set applies again.
+	containers[0].Labels["goscale.cpu-up"] = "75"
+	containers[1].Labels["goscale.cpu-up"] = "75"
+	a.applyLabelOverrides(containers)
+	if a.cfg.cpuUp != 75 {
+		t.Errorf("cpu-up = %g after label change, want 75", a.cfg.cpuUp)
+	}
+}
+
+func TestLabelOverridesAtomic(t *testing.T) {
+	a := labelTestScaler()
+	a.applyLabelOverrides([]containerInfo{
+		{ID: "1", Labels: map[string]string{
+			"goscale.cpu-up": "70",
+			"goscale.min":    "0", // fails validation
+		}},
+	})
+	if a.cfg.cpuUp != 80 || a.cfg.minReplicas != 1 {
+		t.Errorf("invalid label set partially applied: cpu-up %g, min %d", a.cfg.cpuUp, a.cfg.minReplicas)
+	}
+
+	a.applyLabelOverrides([]containerInfo{
+		{ID: "1", Labels: map[string]string{"goscale.interval": "10s"}}, // not reloadable
+	})
+	if a.cfg.interval != 0 {
+		t.Errorf("non-reloadable label applied: interval %s", a.cfg.interval)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// serviceLink scales a dependent service in ratio with the primary,
+// e.g. one worker per two web replicas.
+type serviceLink struct {
+	service string
+	ratio   float64 // dependent replicas per primary replica
+}
+
+// target returns the dependent replica count for a primary size:
+// ceil(primary*ratio), never below one.
+func (l serviceLink) target(primary int) int {
+	want := int(math.Ceil(float64(primary) * l.ratio))
+	if want < 1 {
+		want = 1
+	}
+	return want
+}
+
+// linkFlag collects repeatable -link service=ratio pairs. The ratio is
+// a decimal or a fraction: worker=0.5 and worker=1/2 are the same.
+type linkFlag []serviceLink
+
+func (f *linkFlag) String() string {
+	var parts []string
+	for _, l := range *f {
+		parts = append(parts, fmt.Sprintf("%s=%g", l.service, l.ratio))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *linkFlag) Set(v string) error {
+	service, spec, ok := strings.Cut(v, "=")
+	if !ok || service == "" {
+		return fmt.Errorf("expected service=ratio, got %q", v)
+	}
+	ratio, err := parseRatio(spec)
+	if err != nil {
+		return fmt.Errorf("bad ratio %q for %s", spec, service)
+	}
+	*f = append(*f, serviceLink{service: service, ratio: ratio})
+	return nil
+}
+
+func parseRatio(spec string) (float64, error) {
+	if num, den, ok := strings.Cut(spec, "/"); ok {
+		n, err1 := strconv.ParseFloat(num, 64)
+		d, err2 := strconv.ParseFloat(den, 64)
+		if err1 != nil || err2 != nil || d <= 0 || n <= 0 {
+			return 0, fmt.Errorf("bad fraction %q", spec)
+		}
+		return n / d, nil
+	}
+	r, err := strconv.ParseFloat(spec, 64)
+	if err != nil || r <= 0 {
+		return 0, fmt.Errorf("bad ratio %q", spec)
+	}
+	return r, nil
+}
+
+// scaleLinked brings ratio-linked services in line with the primary's
+// new size. Linked services follow the primary's applied events only;
+// they have no thresholds, cooldowns or events of their own.
+func (a *autoscaler) scaleLinked(ctx context.Context, eventID string, primary int) {
+	if len(a.cfg.links) == 0 || a.docker == nil {
+		return
+	}
+	for _, l := range a.cfg.links {
+		want := l.target(primary)
+		containers, err := a.docker.getServiceContainers(ctx, a.cfg, l.service)
+		if err != nil {
+			log.Printf("✗ [%s] linked %s: %v", eventID, l.service, err)
+			continue
+		}
+		have := countReplicas(a.cfg, containers)
+		if have == want {
+			continue
+		}
+		log.Printf("[%s] scaling linked %s: %d -> %d (ratio %g)", eventID, l.service, have, want, l.ratio)
+		if err := scaleTo(a.cfg, l.service, want); err != nil {
+			log.Printf("✗ [%s] linked %s: %v", eventID, l.service, err)
+		}
+	}
+}
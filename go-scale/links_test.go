@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestLinkFlag(t *testing.T) {
+	var f linkFlag
+	for _, v := range []string{"worker=0.5", "indexer=1/2", "fetcher=2"} {
+		if err := f.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	if len(f) != 3 || f[0].ratio != 0.5 || f[1].ratio != 0.5 || f[2].ratio != 2 {
+		t.Errorf("parsed links = %+v", f)
+	}
+
+	for _, bad := range []string{"worker", "=0.5", "worker=", "worker=0", "worker=-1", "worker=1/0", "worker=x/2"} {
+		if err := f.Set(bad); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestLinkTarget(t *testing.T) {
+	cases := []struct {
+		ratio   float64
+		primary int
+		want    int
+	}{
+		{0.5, 4, 2},
+		{0.5, 3, 2}, // rounds up
+		{0.25, 2, 1},
+		{0.1, 1, 1}, // never below one
+		{2, 3, 6},
+	}
+	for _, c := range cases {
+		l := serviceLink{service: "worker", ratio: c.ratio}
+		if got := l.target(c.primary); got != c.want {
+			t.Errorf("ratio %g, primary %d: target = %d, want %d", c.ratio, c.primary, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package main
+
+import (
+	"io"
+	"log/syslog"
+	"strings"
+)
+
+// newSyslogWriter connects to the local syslog daemon for -log-output
+// syslog.
+func newSyslogWriter() (io.Writer, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, "go-scale")
+	if err != nil {
+		return nil, err
+	}
+	return syslogWriter{w: w}, nil
+}
+
+// syslogWriter fans log lines to the severity-specific syslog calls.
+type syslogWriter struct {
+	w *syslog.Writer
+}
+
+func (s syslogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+	var err error
+	switch linePriority(p) {
+	case prioErr:
+		err = s.w.Err(msg)
+	case prioNotice:
+		err = s.w.Notice(msg)
+	default:
+		err = s.w.Info(msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
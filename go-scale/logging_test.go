@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLinePriority(t *testing.T) {
+	cases := []struct {
+		line string
+		want int
+	}{
+		{"✗ stats for abc: timeout\n", prioErr},
+		{"✓ docker recovered, circuit closed\n", prioNotice},
+		{"i config reloaded, no changes\n", prioInfo},
+		{"watching demo/web every 10s\n", prioInfo},
+	}
+	for _, c := range cases {
+		if got := linePriority([]byte(c.line)); got != c.want {
+			t.Errorf("linePriority(%q) = %d, want %d", c.line, got, c.want)
+		}
+	}
+}
+
+func TestJournalWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := journalWriter{out: &buf}
+	line := "✗ something broke\n"
+	n, err := w.Write([]byte(line))
+	if err != nil || n != len(line) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if !strings.HasPrefix(buf.String(), "<3>✗") {
+		t.Errorf("journal line = %q, want <3> prefix", buf.String())
+	}
+}
+
+func TestQuietWriter(t *testing.T) {
+	kept := []string{
+		"go-scale: 2024/03/01 12:00:00 ✗ stats for abc: timeout\n",
+		"go-scale: 2024/03/01 12:00:00 ✓ docker recovered, circuit closed\n",
+		"go-scale: 2024/03/01 12:00:00 [a1b2] scaling web 2 -> 3\n",
+		"go-scale: 2024/03/01 12:00:00 watching demo/web every 10s\n",
+		"✗ bare daemon-mode error\n", // no prefix or flags (journald/syslog)
+	}
+	dropped := []string{
+		"go-scale: 2024/03/01 12:00:00 i cooldown active, 42s left\n",
+		"i config reloaded, no changes\n",
+	}
+	var buf bytes.Buffer
+	w := quietWriter{out: &buf}
+	for _, line := range append(kept, dropped...) {
+		n, err := w.Write([]byte(line))
+		if err != nil || n != len(line) {
+			t.Fatalf("Write(%q) = %d, %v", line, n, err)
+		}
+	}
+	got := buf.String()
+	if got != strings.Join(kept, "") {
+		t.Errorf("quiet output:\n%s\nwant only the kept lines", got)
+	}
+}
+
+func TestSetupLoggingUnknownMode(t *testing.T) {
+	if err := setupLogging("pigeon", nil); err == nil {
+		t.Fatal("unknown mode accepted")
+	}
+	if err := setupLogging("stderr", nil); err != nil {
+		t.Fatalf("stderr mode: %v", err)
+	}
+}
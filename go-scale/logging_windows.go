@@ -0,0 +1,14 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// newSyslogWriter has no syslog daemon to talk to on Windows; use
+// -log-file or stderr redirection there.
+func newSyslogWriter() (io.Writer, error) {
+	return nil, fmt.Errorf("-log-output syslog is not supported on windows")
+}
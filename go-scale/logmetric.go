@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"time"
+)
+
+// logPatternMetric scales on the service's own log output: each poll
+// it reads the log lines written since the previous poll through the
+// engine's logs API, counts the ones matching -log-pattern, and
+// reports the rate in matches per minute. A spike of "job queued"
+// lines then drives the usual -metric-up/-metric-down thresholds.
+type logPatternMetric struct {
+	docker *dockerClient
+	cfg    *config
+	re     *regexp.Regexp
+	since  time.Time
+	now    func() time.Time
+}
+
+func newLogPatternMetric(docker *dockerClient, cfg *config, re *regexp.Regexp) *logPatternMetric {
+	return &logPatternMetric{docker: docker, cfg: cfg, re: re, now: time.Now}
+}
+
+func (m *logPatternMetric) Name() string { return "log-pattern" }
+
+func (m *logPatternMetric) Collect(ctx context.Context) (float64, error) {
+	now := m.now()
+	since := m.since
+	m.since = now
+	if since.IsZero() {
+		// First poll: look back one minute so the very first sample is
+		// already a meaningful rate.
+		since = now.Add(-time.Minute)
+	}
+	elapsed := now.Sub(since)
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+
+	containers, err := m.docker.getServiceContainers(ctx, m.cfg, m.cfg.service)
+	if err != nil {
+		return 0, fmt.Errorf("log pattern: %w", err)
+	}
+	matches := 0
+	for _, c := range containers {
+		if c.State != "running" {
+			continue
+		}
+		n, err := m.countMatches(ctx, c.ID, since, now)
+		if err != nil {
+			// One replica's logs failing shouldn't zero the whole signal;
+			// count what we can and note the gap.
+			log.Printf("✗ logs for %s: %v", shortID(c.ID), err)
+			continue
+		}
+		matches += n
+	}
+	return float64(matches) / elapsed.Minutes(), nil
+}
+
+// countMatches reads one container's log window and counts matching
+// lines.
+func (m *logPatternMetric) countMatches(ctx context.Context, id string, since, until time.Time) (int, error) {
+	path := fmt.Sprintf("/containers/%s/logs?stdout=1&stderr=1&since=%d&until=%d", id, since.Unix(), until.Unix())
+	body, err := m.docker.Get(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n := 0
+	sc := bufio.NewScanner(newLogDemux(body))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if m.re.Match(sc.Bytes()) {
+			n++
+		}
+	}
+	if err := sc.Err(); err != nil && err != io.ErrUnexpectedEOF {
+		return n, err
+	}
+	return n, nil
+}
+
+// logDemux strips the engine's log stream multiplexing: without a TTY
+// the payload arrives in frames with an 8-byte header (stream byte,
+// three zeros, big-endian length); with one it is the raw byte stream.
+// The first bytes tell the two apart.
+type logDemux struct {
+	r      *bufio.Reader
+	framed bool
+	remain int // payload bytes left in the current frame
+}
+
+func newLogDemux(r io.Reader) *logDemux {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(8)
+	framed := len(head) == 8 && head[0] <= 2 && head[1] == 0 && head[2] == 0 && head[3] == 0
+	return &logDemux{r: br, framed: framed}
+}
+
+func (d *logDemux) Read(p []byte) (int, error) {
+	if !d.framed {
+		return d.r.Read(p)
+	}
+	for d.remain == 0 {
+		var head [8]byte
+		if _, err := io.ReadFull(d.r, head[:]); err != nil {
+			return 0, err
+		}
+		d.remain = int(binary.BigEndian.Uint32(head[4:]))
+	}
+	if len(p) > d.remain {
+		p = p[:d.remain]
+	}
+	n, err := d.r.Read(p)
+	d.remain -= n
+	return n, err
+}
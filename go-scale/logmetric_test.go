@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// frame wraps one payload chunk in the engine's log multiplexing
+// header: stream byte, three zeros, big-endian length.
+func frame(stream byte, payload string) []byte {
+	head := make([]byte, 8)
+	head[0] = stream
+	binary.BigEndian.PutUint32(head[4:], uint32(len(payload)))
+	return append(head, payload...)
+}
+
+func TestLogDemuxFramed(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(frame(1, "job queued id=1\njob "))
+	buf.Write(frame(2, "queued id=2\n"))
+	buf.Write(frame(1, "worker idle\n"))
+
+	var lines []string
+	sc := bufio.NewScanner(newLogDemux(&buf))
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	want := []string{"job queued id=1", "job queued id=2", "worker idle"}
+	if len(lines) != len(want) {
+		t.Fatalf("lines = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestLogDemuxRawTTY(t *testing.T) {
+	// A TTY container's logs arrive unframed; the demux must pass the
+	// bytes straight through.
+	raw := "job queued id=1\nworker idle\n"
+	var lines []string
+	sc := bufio.NewScanner(newLogDemux(strings.NewReader(raw)))
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	if len(lines) != 2 || lines[0] != "job queued id=1" {
+		t.Errorf("lines = %q", lines)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// maintenanceWindow is a recurring daily window during which scale
+// actions are suppressed — metrics keep flowing, decisions keep being
+// logged, but nothing is applied, so deployments and backups don't
+// fight the autoscaler.
+type maintenanceWindow struct {
+	days       cronField // day-of-week restriction; any for every day
+	start, end int       // minutes since midnight; start > end wraps past it
+	raw        string
+}
+
+// maintenanceFlag collects repeatable -maintenance windows.
+type maintenanceFlag []maintenanceWindow
+
+func (m *maintenanceFlag) String() string {
+	var parts []string
+	for _, w := range *m {
+		parts = append(parts, w.raw)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (m *maintenanceFlag) Set(v string) error {
+	w, err := parseMaintenanceWindow(v)
+	if err != nil {
+		return err
+	}
+	*m = append(*m, w)
+	return nil
+}
+
+// parseMaintenanceWindow parses "HH:MM-HH:MM" with an optional leading
+// day-of-week field in cron syntax: "1-5 02:00-04:00".
+func parseMaintenanceWindow(v string) (maintenanceWindow, error) {
+	w := maintenanceWindow{days: cronField{any: true}, raw: v}
+	fields := strings.Fields(v)
+	span := ""
+	switch len(fields) {
+	case 1:
+		span = fields[0]
+	case 2:
+		days, err := parseCronField(fields[0], 0, 7)
+		if err != nil {
+			return maintenanceWindow{}, fmt.Errorf("maintenance %q: %w", v, err)
+		}
+		if days.has(7) {
+			days.set |= 1
+		}
+		w.days = days
+		span = fields[1]
+	default:
+		return maintenanceWindow{}, fmt.Errorf("maintenance %q: want [days] HH:MM-HH:MM", v)
+	}
+
+	from, to, ok := strings.Cut(span, "-")
+	if !ok {
+		return maintenanceWindow{}, fmt.Errorf("maintenance %q: want HH:MM-HH:MM", v)
+	}
+	var err error
+	if w.start, err = parseClock(from); err != nil {
+		return maintenanceWindow{}, fmt.Errorf("maintenance %q: %w", v, err)
+	}
+	if w.end, err = parseClock(to); err != nil {
+		return maintenanceWindow{}, fmt.Errorf("maintenance %q: %w", v, err)
+	}
+	if w.start == w.end {
+		return maintenanceWindow{}, fmt.Errorf("maintenance %q: empty window", v)
+	}
+	return w, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether t falls inside the window. Windows with
+// start > end wrap past midnight; their day restriction applies to the
+// day the window opened.
+func (w maintenanceWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start <= w.end {
+		return minute >= w.start && minute < w.end && w.days.has(int(t.Weekday()))
+	}
+	if minute >= w.start {
+		return w.days.has(int(t.Weekday()))
+	}
+	if minute < w.end {
+		yesterday := t.AddDate(0, 0, -1)
+		return w.days.has(int(yesterday.Weekday()))
+	}
+	return false
+}
+
+// suppressReason reports why scale actions are currently suppressed, or
+// "" when they are not: an operator pause (API or SIGUSR1), the pause
+// file existing, or an active maintenance window. Monitoring continues
+// while suppressed; only the scale commands are held.
+func (a *autoscaler) suppressReason(now time.Time) string {
+	if a.isPaused() {
+		return "paused by operator"
+	}
+	if a.deployHold != "" {
+		return "deploy in progress: " + a.deployHold
+	}
+	if a.cfg.pauseFile != "" {
+		if _, err := os.Stat(a.cfg.pauseFile); err == nil {
+			return "pause file " + a.cfg.pauseFile + " present"
+		}
+	}
+	if reason := a.manualHoldReason(now); reason != "" {
+		return reason
+	}
+	for _, w := range a.cfg.maintenance {
+		if w.contains(now) {
+			return "maintenance window " + w.raw
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func mustWindow(t *testing.T, v string) maintenanceWindow {
+	t.Helper()
+	w, err := parseMaintenanceWindow(v)
+	if err != nil {
+		t.Fatalf("parseMaintenanceWindow(%q): %v", v, err)
+	}
+	return w
+}
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	monday3am := time.Date(2024, 1, 8, 3, 0, 0, 0, time.UTC)
+
+	w := mustWindow(t, "02:00-04:00")
+	if !w.contains(monday3am) {
+		t.Error("03:00 not inside 02:00-04:00")
+	}
+	if w.contains(monday3am.Add(2 * time.Hour)) {
+		t.Error("05:00 inside 02:00-04:00")
+	}
+
+	// Day restriction.
+	w = mustWindow(t, "1-5 02:00-04:00")
+	if !w.contains(monday3am) {
+		t.Error("weekday window missed Monday")
+	}
+	saturday := time.Date(2024, 1, 6, 3, 0, 0, 0, time.UTC)
+	if w.contains(saturday) {
+		t.Error("weekday window matched Saturday")
+	}
+
+	// Overnight wrap: the day applies to when the window opened.
+	w = mustWindow(t, "5 22:00-02:00")
+	friday23 := time.Date(2024, 1, 5, 23, 0, 0, 0, time.UTC)
+	saturday1am := time.Date(2024, 1, 6, 1, 0, 0, 0, time.UTC)
+	if !w.contains(friday23) || !w.contains(saturday1am) {
+		t.Error("overnight Friday window missed its own span")
+	}
+	saturday23 := time.Date(2024, 1, 6, 23, 0, 0, 0, time.UTC)
+	if w.contains(saturday23) {
+		t.Error("overnight Friday window matched Saturday night")
+	}
+
+	for _, bad := range []string{"", "02:00", "02:00-02:00", "25:00-04:00", "1-5", "1-5 2-4"} {
+		if _, err := parseMaintenanceWindow(bad); err == nil {
+			t.Errorf("parseMaintenanceWindow(%q) accepted", bad)
+		}
+	}
+}
+
+func TestSuppressReason(t *testing.T) {
+	a := &autoscaler{cfg: &config{}}
+	now := time.Date(2024, 1, 8, 3, 0, 0, 0, time.UTC)
+
+	if got := a.suppressReason(now); got != "" {
+		t.Errorf("unconfigured: %q", got)
+	}
+
+	a.cfg.maintenance = maintenanceFlag{mustWindow(t, "02:00-04:00")}
+	if got := a.suppressReason(now); got == "" {
+		t.Error("maintenance window not suppressing")
+	}
+	if got := a.suppressReason(now.Add(3 * time.Hour)); got != "" {
+		t.Errorf("outside window: %q", got)
+	}
+
+	pause := filepath.Join(t.TempDir(), "pause")
+	a.cfg = &config{pauseFile: pause}
+	if got := a.suppressReason(now); got != "" {
+		t.Errorf("absent pause file: %q", got)
+	}
+	if err := os.WriteFile(pause, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.suppressReason(now); got == "" {
+		t.Error("pause file not suppressing")
+	}
+
+	a.cfg = &config{}
+	if a.togglePaused() != true {
+		t.Fatal("togglePaused did not pause")
+	}
+	if got := a.suppressReason(now); got != "paused by operator" {
+		t.Errorf("paused: %q", got)
+	}
+	if a.togglePaused() != false {
+		t.Fatal("togglePaused did not resume")
+	}
+	if got := a.suppressReason(now); got != "" {
+		t.Errorf("resumed: %q", got)
+	}
+}
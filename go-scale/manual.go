@@ -0,0 +1,44 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// Manual scaling detection. An operator who runs `docker compose up
+// --scale web=8` by hand usually knows something the autoscaler
+// doesn't, and quietly scaling their count away a poll later fights
+// them. Every poll compares the observed replica count with the count
+// the autoscaler itself last saw or set; a mismatch is logged and, the
+// thresholds being relative to the current count, becomes the new
+// baseline on its own. -manual-hold additionally suppresses scale
+// actions for a while so the operator's count sticks.
+
+// trackManualScale notes an externally changed replica count. It runs
+// on the poll goroutine with the freshly observed count; the
+// autoscaler's own actions keep expectReplicas in step via execute.
+func (a *autoscaler) trackManualScale(replicas int) {
+	prev := a.expectReplicas
+	a.expectReplicas = replicas
+	if prev == 0 || replicas == prev {
+		return
+	}
+	if a.deployHold != "" {
+		// A deploy recreating containers moves the count around on its
+		// own; that's not an operator decision to honor.
+		return
+	}
+	log.Printf("i replica count changed outside go-scale: %d -> %d, adopting as baseline", prev, replicas)
+	if a.cfg.manualHold > 0 {
+		a.manualUntil = a.now().Add(a.cfg.manualHold)
+		log.Printf("i holding scale actions for %s after the manual change", a.cfg.manualHold)
+	}
+}
+
+// manualHoldReason reports the active -manual-hold window, if any.
+func (a *autoscaler) manualHoldReason(now time.Time) string {
+	if now.Before(a.manualUntil) {
+		return "manual scale adopted, holding until " + a.manualUntil.Format("15:04:05")
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackManualScale(t *testing.T) {
+	now := time.Now()
+	a := &autoscaler{cfg: &config{manualHold: 10 * time.Minute}}
+	a.now = func() time.Time { return now }
+
+	// First observation establishes the baseline without a hold.
+	a.trackManualScale(3)
+	if got := a.manualHoldReason(now); got != "" {
+		t.Fatalf("first observation held: %q", got)
+	}
+
+	// Same count: nothing external happened.
+	a.trackManualScale(3)
+	if got := a.manualHoldReason(now); got != "" {
+		t.Fatalf("unchanged count held: %q", got)
+	}
+
+	// The count moved without a scale action: hold starts.
+	a.trackManualScale(5)
+	if got := a.manualHoldReason(now); got == "" {
+		t.Error("manual change not held")
+	}
+	if got := a.manualHoldReason(now.Add(11 * time.Minute)); got != "" {
+		t.Errorf("hold outlived -manual-hold: %q", got)
+	}
+}
+
+func TestTrackManualScaleDeployHold(t *testing.T) {
+	a := &autoscaler{cfg: &config{manualHold: 10 * time.Minute}}
+	a.now = time.Now
+	a.trackManualScale(3)
+	a.deployHold = "web recreating"
+	a.trackManualScale(1) // deploys move the count; not an operator
+	if got := a.manualHoldReason(time.Now()); got != "" {
+		t.Errorf("deploy-time change held: %q", got)
+	}
+	if a.expectReplicas != 1 {
+		t.Errorf("expectReplicas = %d, want 1", a.expectReplicas)
+	}
+}
+
+func TestTrackManualScaleLogOnly(t *testing.T) {
+	a := &autoscaler{cfg: &config{}} // no -manual-hold
+	a.now = time.Now
+	a.trackManualScale(3)
+	a.trackManualScale(5)
+	if got := a.manualHoldReason(time.Now()); got != "" {
+		t.Errorf("hold without -manual-hold: %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Independent metric cadence. Docker stats are cheap enough to sample
+// every -interval, but a custom source can be an expensive PromQL
+// query or a slow HTTP probe. -metric-interval moves that source onto
+// its own background ticker; the decision loop then uses the freshest
+// cached sample instead of collecting inline. A sample older than two
+// metric intervals counts as a failed one, which holds scale-downs
+// like any failed sample.
+
+// metricPoll holds the background sampler's latest good value.
+type metricPoll struct {
+	mu sync.Mutex
+	v  float64
+	at time.Time
+	ok bool
+}
+
+func (p *metricPoll) store(v float64, at time.Time) {
+	p.mu.Lock()
+	p.v, p.at, p.ok = v, at, true
+	p.mu.Unlock()
+}
+
+// fresh returns the last sample when it is younger than the limit.
+func (p *metricPoll) fresh(now time.Time, limit time.Duration) (float64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.ok || now.Sub(p.at) > limit {
+		return 0, false
+	}
+	return p.v, true
+}
+
+// pollMetric samples the custom source on its own ticker until the
+// context ends. Failures keep the previous sample; staleness is the
+// reader's problem.
+func (a *autoscaler) pollMetric(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.metricInterval)
+	defer ticker.Stop()
+	for {
+		v, err := a.metric.Collect(ctx)
+		if err != nil {
+			log.Printf("✗ %v", err)
+			self.countError("metric")
+		} else {
+			if a.cfg.verbose {
+				log.Printf("i metric %s = %.2f", a.metric.Name(), v)
+			}
+			a.metricPoll.store(v, time.Now())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectMetric returns this poll's custom metric sample and whether
+// it failed: the freshest background sample with -metric-interval,
+// collected inline otherwise. An injected override wins either way.
+func (a *autoscaler) collectMetric(ctx context.Context, inject *injection) (*float64, bool) {
+	if a.metric == nil {
+		return nil, false
+	}
+	if inject != nil && inject.hasMetric {
+		v := inject.metric
+		return &v, false
+	}
+	if a.metricPoll != nil {
+		if v, ok := a.metricPoll.fresh(time.Now(), 2*a.cfg.metricInterval); ok {
+			return &v, false
+		}
+		return nil, true
+	}
+	v, err := a.metric.Collect(ctx)
+	if err != nil {
+		log.Printf("✗ %v", err)
+		self.countError("metric")
+		return nil, true
+	}
+	if a.cfg.verbose {
+		log.Printf("i metric %s = %.2f", a.metric.Name(), v)
+	}
+	return &v, false
+}
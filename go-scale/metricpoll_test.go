@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubMetric struct {
+	v   float64
+	err error
+}
+
+func (s stubMetric) Name() string { return "stub" }
+
+func (s stubMetric) Collect(ctx context.Context) (float64, error) { return s.v, s.err }
+
+func TestMetricPollFresh(t *testing.T) {
+	now := time.Now()
+	p := &metricPoll{}
+
+	if _, ok := p.fresh(now, time.Minute); ok {
+		t.Fatal("empty poller returned a sample")
+	}
+	p.store(7, now.Add(-30*time.Second))
+	if v, ok := p.fresh(now, time.Minute); !ok || v != 7 {
+		t.Errorf("fresh = %v, %v, want 7, true", v, ok)
+	}
+	if _, ok := p.fresh(now.Add(2*time.Minute), time.Minute); ok {
+		t.Error("stale sample returned as fresh")
+	}
+}
+
+func TestCollectMetricInline(t *testing.T) {
+	a := &autoscaler{cfg: &config{}, metric: stubMetric{v: 12}}
+	v, failed := a.collectMetric(context.Background(), nil)
+	if failed || v == nil || *v != 12 {
+		t.Errorf("collectMetric = %v, %v, want 12, false", v, failed)
+	}
+
+	a.metric = stubMetric{err: errors.New("boom")}
+	if v, failed := a.collectMetric(context.Background(), nil); !failed || v != nil {
+		t.Errorf("failed collect = %v, %v, want nil, true", v, failed)
+	}
+
+	a.metric = nil
+	if v, failed := a.collectMetric(context.Background(), nil); failed || v != nil {
+		t.Errorf("no source = %v, %v, want nil, false", v, failed)
+	}
+}
+
+func TestCollectMetricBackground(t *testing.T) {
+	a := &autoscaler{
+		cfg:        &config{metricInterval: time.Minute},
+		metric:     stubMetric{v: 99}, // must not be collected inline
+		metricPoll: &metricPoll{},
+	}
+
+	// No sample yet counts as a failed one, holding scale-downs.
+	if v, failed := a.collectMetric(context.Background(), nil); !failed || v != nil {
+		t.Errorf("no sample = %v, %v, want nil, true", v, failed)
+	}
+
+	a.metricPoll.store(5, time.Now())
+	if v, failed := a.collectMetric(context.Background(), nil); failed || v == nil || *v != 5 {
+		t.Errorf("cached sample = %v, %v, want 5, false", v, failed)
+	}
+
+	a.metricPoll.store(5, time.Now().Add(-3*time.Minute))
+	if v, failed := a.collectMetric(context.Background(), nil); !failed || v != nil {
+		t.Errorf("stale sample = %v, %v, want nil, true", v, failed)
+	}
+}
+
+func TestCollectMetricInjectOverride(t *testing.T) {
+	a := &autoscaler{
+		cfg:        &config{metricInterval: time.Minute},
+		metric:     stubMetric{v: 99},
+		metricPoll: &metricPoll{},
+	}
+	inject := &injection{hasMetric: true, metric: 3}
+	if v, failed := a.collectMetric(context.Background(), inject); failed || v == nil || *v != 3 {
+		t.Errorf("injected = %v, %v, want 3, false", v, failed)
+	}
+}
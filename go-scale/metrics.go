@@ -0,0 +1,9 @@
+package main
+
+import "github.com/iamdanielv/utils/pkg/metrics"
+
+// metricSource is the custom-signal interface; it lives in pkg/metrics
+// now so embedders can supply their own sources. The built-in sources
+// in this package (redis, rabbit, nginx, traefik, log-pattern) keep
+// implementing it.
+type metricSource = metrics.Source
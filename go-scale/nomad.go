@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/iamdanielv/utils/pkg/scaler"
+)
+
+// HashiCorp Nomad backend. With -backend nomad the scaler targets a
+// Nomad cluster instead of a compose project: -project names the job,
+// -service the task group, and scale actions go through the job scale
+// endpoint. The metric sources, decision engine, behavior gates and
+// circuit breaker are shared with the compose backend; only counting,
+// sampling and the scale call differ. Compose-only machinery (label
+// overrides, deploy holds, linked services, image rolls) does not
+// apply here. The API client itself lives in pkg/scaler.
+
+// nomadClient is the library client; this package adds the poll loop
+// around it.
+type nomadClient = scaler.Nomad
+
+// newNomadClient resolves the usual Nomad environment defaults:
+// NOMAD_ADDR and NOMAD_TOKEN, then the local agent.
+func newNomadClient(addr, token, namespace string) *nomadClient {
+	return scaler.NewNomad(addr, token, namespace)
+}
+
+// applyScale routes a decided count through the configured backend.
+func (a *autoscaler) applyScale(ctx context.Context, event scaleEvent) error {
+	if a.nomad == nil {
+		return scaleTo(a.cfg, a.cfg.service, event.To)
+	}
+	if a.cfg.dryRun {
+		log.Printf("[%s] dry-run: would scale nomad group %s/%s to %d", event.ID, a.cfg.project, a.cfg.service, event.To)
+		return nil
+	}
+	return a.nomad.ScaleGroup(ctx, a.cfg.project, a.cfg.service, event.To, "go-scale event "+event.ID)
+}
+
+// evaluateNomad is the Nomad counterpart of evaluate: one poll of the
+// task group's count and allocation usage, fed into the shared
+// decision path.
+func (a *autoscaler) evaluateNomad(ctx context.Context) {
+	if !a.breaker.Allow(a.now()) {
+		return
+	}
+	defer self.timeEval(time.Now())
+	if e, ok := a.approvedDecision(); ok {
+		a.execute(ctx, e)
+		return
+	}
+
+	replicas, memMiB, err := a.nomad.GroupCount(ctx, a.cfg.project, a.cfg.service)
+	if err != nil {
+		log.Printf("✗ %v", err)
+		self.countError("nomad")
+		a.dockerFailure()
+		return
+	}
+	if replicas == 0 {
+		log.Printf("✗ task group %q has count 0, not scaling", a.cfg.service)
+		return
+	}
+
+	allocs, err := a.nomad.RunningAllocs(ctx, a.cfg.project, a.cfg.service)
+	if err != nil {
+		log.Printf("✗ %v", err)
+		self.countError("nomad")
+		a.dockerFailure()
+		return
+	}
+	var cpuSum, memSum float64
+	var sampled int
+	for _, id := range allocs {
+		cpu, memBytes, err := a.nomad.AllocUsage(ctx, id)
+		if err != nil {
+			log.Printf("✗ stats for alloc %s: %v", shortID(id), err)
+			continue
+		}
+		cpuSum += cpu
+		if memMiB > 0 {
+			memSum += float64(memBytes) / float64(uint64(memMiB)<<20) * 100.0
+		}
+		sampled++
+	}
+	if sampled == 0 && len(allocs) > 0 {
+		log.Printf("✗ no usable allocation stats this poll")
+		a.dockerFailure()
+		return
+	}
+	a.dockerSuccess()
+
+	var avgCPU, avgMem float64
+	if sampled > 0 {
+		avgCPU = cpuSum / float64(sampled)
+		avgMem = memSum / float64(sampled)
+	}
+	avgCPU = a.spikeCPU.filter(avgCPU)
+	avgMem = a.spikeMem.filter(avgMem)
+	inject := a.takeInjection()
+	if inject != nil {
+		if inject.hasCPU {
+			avgCPU = inject.cpu
+		}
+		if inject.hasMem {
+			avgMem = inject.mem
+		}
+		log.Printf("i injected metrics active, %d polls left", inject.polls-1)
+	}
+	a.trackManualScale(replicas)
+	a.setReplicas(replicas)
+	a.trackReplicaHours(replicas)
+
+	if a.cfg.verbose {
+		log.Printf("i %s: %d replicas, cpu %.1f%%, mem %.1f%%", a.cfg.service, replicas, avgCPU, avgMem)
+	}
+	a.statsd.gauge(a.cfg.service+".cpu", avgCPU)
+	a.statsd.gauge(a.cfg.service+".mem", avgMem)
+	a.statsd.gauge(a.cfg.service+".replicas", float64(replicas))
+
+	metricV, metricErr := a.collectMetric(ctx, inject)
+	if metricV != nil && (inject == nil || !inject.hasMetric) {
+		v := a.spikeMetric.filter(*metricV)
+		metricV = &v
+	}
+
+	a.emitHeartbeat(replicas, avgCPU, avgMem, metricV)
+
+	a.decide(ctx, replicas, avgCPU, avgMem, metricV, metricErr)
+
+	remaining := a.effectiveCooldown() - a.now().Sub(a.lastScale)
+	if remaining < 0 {
+		remaining = 0
+	}
+	a.stream.publish("sample", tuiSample{
+		Time: a.now(), CPU: avgCPU, Mem: avgMem,
+		Replicas: a.currentReplicas(), Cooldown: remaining, Paused: a.isPaused(),
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// The notifier layer fans human-facing messages (scale events, alert
+// transitions) out to chat channels. Targets are declared together as
+// repeatable -notify kind=url flags, and the message body is one Go
+// template shared by all of them, so adding a channel is configuration
+// rather than a new code path per product.
+
+// notifyTarget is one -notify destination.
+type notifyTarget struct {
+	kind string // slack, discord, teams or webhook
+	url  string
+}
+
+// notifyFlag collects repeatable -notify kind=url pairs.
+type notifyFlag []notifyTarget
+
+func (f *notifyFlag) String() string {
+	var parts []string
+	for _, t := range *f {
+		parts = append(parts, t.kind+"="+t.url)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *notifyFlag) Set(v string) error {
+	kind, url, ok := strings.Cut(v, "=")
+	if !ok || url == "" {
+		return fmt.Errorf("expected kind=url, got %q", v)
+	}
+	switch kind {
+	case "slack", "discord", "teams", "webhook":
+	default:
+		return fmt.Errorf("unknown notifier %q (want slack, discord, teams or webhook)", kind)
+	}
+	*f = append(*f, notifyTarget{kind: kind, url: url})
+	return nil
+}
+
+// notification is the data a message template renders from.
+type notification struct {
+	Time     time.Time   `json:"time"`
+	Project  string      `json:"project"`
+	Service  string      `json:"service"`
+	Kind     string      `json:"kind"` // "scale" or "alert"
+	Message  string      `json:"message"`
+	Resolved bool        `json:"resolved,omitempty"`
+	Event    *scaleEvent `json:"event,omitempty"`
+}
+
+// defaultNotifyTemplate is used when no -notify-template file is given.
+const defaultNotifyTemplate = "go-scale {{.Project}}/{{.Service}}: {{.Message}}"
+
+// notifierSet renders one template and posts the result to every
+// configured target.
+type notifierSet struct {
+	targets []notifyTarget
+	tmpl    *template.Template
+	client  *http.Client
+}
+
+// newNotifierSet builds the set; tmplPath is an optional file holding a
+// Go template over the notification struct.
+func newNotifierSet(targets []notifyTarget, tmplPath string) (*notifierSet, error) {
+	text := defaultNotifyTemplate
+	if tmplPath != "" {
+		b, err := os.ReadFile(tmplPath)
+		if err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(string(b))
+	}
+	tmpl, err := template.New("notify").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("notify template: %w", err)
+	}
+	return &notifierSet{
+		targets: targets,
+		tmpl:    tmpl,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// send renders the notification and posts it to every target in the
+// background, so a slow chat service never blocks the poll loop.
+func (n *notifierSet) send(note notification) {
+	var buf bytes.Buffer
+	if err := n.tmpl.Execute(&buf, note); err != nil {
+		log.Printf("✗ notify template: %v", err)
+		return
+	}
+	body := buf.String()
+	for _, t := range n.targets {
+		t := t
+		go func() {
+			if err := n.post(t, body, note); err != nil {
+				log.Printf("✗ notify %s: %v", t.kind, err)
+			}
+		}()
+	}
+}
+
+// post wraps the rendered body in the target's expected payload shape.
+// Slack and Teams take {"text": ...}, Discord {"content": ...}; the
+// generic webhook gets the full notification with the rendered message.
+func (n *notifierSet) post(t notifyTarget, body string, note notification) error {
+	var payload any
+	switch t.kind {
+	case "slack", "teams":
+		payload = map[string]string{"text": body}
+	case "discord":
+		payload = map[string]string{"content": body}
+	default:
+		note.Message = body
+		payload = note
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(t.url, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return nil
+}
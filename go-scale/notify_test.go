@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifyFlag(t *testing.T) {
+	var f notifyFlag
+	if err := f.Set("slack=https://hooks.example/abc"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := f.Set("pager=https://x"); err == nil {
+		t.Error("unknown kind accepted")
+	}
+	if err := f.Set("slack"); err == nil {
+		t.Error("missing url accepted")
+	}
+	if len(f) != 1 || f[0].kind != "slack" {
+		t.Fatalf("targets = %+v", f)
+	}
+}
+
+func TestNotifierSetSend(t *testing.T) {
+	var mu sync.Mutex
+	got := map[string]string{} // kind -> raw body
+	var wg sync.WaitGroup
+	handler := func(kind string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			defer wg.Done()
+			b, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			got[kind] = string(b)
+			mu.Unlock()
+		}
+	}
+	slack := httptest.NewServer(handler("slack"))
+	defer slack.Close()
+	hook := httptest.NewServer(handler("webhook"))
+	defer hook.Close()
+
+	n, err := newNotifierSet(notifyFlag{
+		{kind: "slack", url: slack.URL},
+		{kind: "webhook", url: hook.URL},
+	}, "")
+	if err != nil {
+		t.Fatalf("newNotifierSet: %v", err)
+	}
+
+	wg.Add(2)
+	n.send(notification{
+		Time:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Project: "demo", Service: "web",
+		Kind: "scale", Message: "scale 2 -> 3 (applied)",
+	})
+	wg.Wait()
+
+	var slackBody struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(got["slack"]), &slackBody); err != nil {
+		t.Fatalf("slack payload: %v", err)
+	}
+	if want := "go-scale demo/web: scale 2 -> 3 (applied)"; slackBody.Text != want {
+		t.Errorf("slack text = %q, want %q", slackBody.Text, want)
+	}
+
+	var hookBody notification
+	if err := json.Unmarshal([]byte(got["webhook"]), &hookBody); err != nil {
+		t.Fatalf("webhook payload: %v", err)
+	}
+	if hookBody.Project != "demo" || hookBody.Kind != "scale" {
+		t.Errorf("webhook payload = %+v", hookBody)
+	}
+}
+
+func TestNotifierCustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tmpl")
+	if err := os.WriteFile(path, []byte("[{{.Kind}}] {{.Service}}: {{.Message}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer wg.Done()
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	n, err := newNotifierSet(notifyFlag{{kind: "discord", url: srv.URL}}, path)
+	if err != nil {
+		t.Fatalf("newNotifierSet: %v", err)
+	}
+	wg.Add(1)
+	n.send(notification{Service: "web", Kind: "alert", Message: "circuit open"})
+	wg.Wait()
+
+	var discord struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal([]byte(body), &discord); err != nil {
+		t.Fatalf("discord payload: %v", err)
+	}
+	if want := "[alert] web: circuit open"; discord.Content != want {
+		t.Errorf("content = %q, want %q", discord.Content, want)
+	}
+
+	if _, err := newNotifierSet(nil, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("missing template file accepted")
+	}
+}
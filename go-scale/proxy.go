@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iamdanielv/utils/pkg/metrics"
+)
+
+// The proxy sources double as registry providers, so -metric-provider
+// can pick them up by name with the URL as the spec.
+func init() {
+	metrics.Register("nginx", func(spec string) (metrics.Source, error) {
+		if spec == "" {
+			return nil, fmt.Errorf("needs the stub_status URL as its spec")
+		}
+		return newNginxStubMetric(spec), nil
+	})
+	metrics.Register("traefik", func(spec string) (metrics.Source, error) {
+		url, service, _ := strings.Cut(spec, "#")
+		if url == "" || service == "" {
+			return nil, fmt.Errorf("needs metricsURL#service as its spec")
+		}
+		return newRateMetric(newTraefikMetric(url, service)), nil
+	})
+}
+
+// nginxStubMetric reads active connection count from an nginx
+// stub_status endpoint, for throughput-based scaling of web services
+// behind nginx.
+type nginxStubMetric struct {
+	url    string
+	client *http.Client
+}
+
+func newNginxStubMetric(url string) *nginxStubMetric {
+	return &nginxStubMetric{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (m *nginxStubMetric) Name() string { return "nginx" }
+
+func (m *nginxStubMetric) Collect(ctx context.Context) (float64, error) {
+	text, err := fetchText(ctx, m.client, m.url)
+	if err != nil {
+		return 0, fmt.Errorf("nginx stub_status: %w", err)
+	}
+	active, _, err := parseStubStatus(text)
+	if err != nil {
+		return 0, fmt.Errorf("nginx stub_status: %w", err)
+	}
+	return active, nil
+}
+
+// parseStubStatus parses nginx stub_status output:
+//
+//	Active connections: 291
+//	server accepts handled requests
+//	 16630948 16630948 31070465
+//	Reading: 6 Writing: 179 Waiting: 106
+func parseStubStatus(text string) (active, requests float64, err error) {
+	lines := strings.Split(text, "\n")
+	if len(lines) < 3 {
+		return 0, 0, fmt.Errorf("short response %q", text)
+	}
+	_, v, ok := strings.Cut(lines[0], "Active connections:")
+	if !ok {
+		return 0, 0, fmt.Errorf("missing active connections in %q", lines[0])
+	}
+	active, err = strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(lines[2])
+	if len(fields) == 3 {
+		requests, _ = strconv.ParseFloat(fields[2], 64)
+	}
+	return active, requests, nil
+}
+
+// traefikMetric sums Traefik's cumulative request counter for one
+// service from its Prometheus metrics endpoint. Wrap it in rateMetric
+// to get requests/sec.
+type traefikMetric struct {
+	url     string // Traefik metrics endpoint, e.g. http://traefik:8082/metrics
+	service string // Traefik service name to match
+	client  *http.Client
+}
+
+func newTraefikMetric(url, service string) *traefikMetric {
+	return &traefikMetric{url: url, service: service, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (m *traefikMetric) Name() string { return "traefik" }
+
+func (m *traefikMetric) Collect(ctx context.Context) (float64, error) {
+	text, err := fetchText(ctx, m.client, m.url)
+	if err != nil {
+		return 0, fmt.Errorf("traefik metrics: %w", err)
+	}
+	total, found := sumPromCounter(text, "traefik_service_requests_total", `service="`+m.service)
+	if !found {
+		return 0, fmt.Errorf("traefik metrics: no traefik_service_requests_total for service %q", m.service)
+	}
+	return total, nil
+}
+
+// sumPromCounter sums all samples of a Prometheus metric whose label
+// block contains labelFragment.
+func sumPromCounter(text, metric, labelFragment string) (float64, bool) {
+	var sum float64
+	found := false
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, metric+"{") || !strings.Contains(line, labelFragment) {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+		if err != nil {
+			continue
+		}
+		sum += v
+		found = true
+	}
+	return sum, found
+}
+
+// rateMetric converts a cumulative counter source into a per-second
+// rate between polls. The first poll (and counter resets) yield
+// errIncompleteSample semantics: no usable value yet.
+type rateMetric struct {
+	inner metricSource
+
+	last     float64
+	lastTime time.Time
+	primed   bool
+}
+
+func newRateMetric(inner metricSource) *rateMetric {
+	return &rateMetric{inner: inner}
+}
+
+func (m *rateMetric) Name() string { return m.inner.Name() + "/s" }
+
+func (m *rateMetric) Collect(ctx context.Context) (float64, error) {
+	v, err := m.inner.Collect(ctx)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	defer func() { m.last, m.lastTime, m.primed = v, now, true }()
+	if !m.primed {
+		return 0, fmt.Errorf("%s: first sample, no rate yet", m.inner.Name())
+	}
+	elapsed := now.Sub(m.lastTime).Seconds()
+	if elapsed <= 0 {
+		return 0, fmt.Errorf("%s: no time elapsed between samples", m.inner.Name())
+	}
+	delta := v - m.last
+	if delta < 0 {
+		// Counter reset (proxy restarted); skip this interval.
+		return 0, fmt.Errorf("%s: counter reset detected", m.inner.Name())
+	}
+	return delta / elapsed, nil
+}
+
+func fetchText(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
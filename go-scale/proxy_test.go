@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const stubStatusSample = `Active connections: 291
+server accepts handled requests
+ 16630948 16630948 31070465
+Reading: 6 Writing: 179 Waiting: 106
+`
+
+func TestParseStubStatus(t *testing.T) {
+	active, requests, err := parseStubStatus(stubStatusSample)
+	if err != nil {
+		t.Fatalf("parseStubStatus: %v", err)
+	}
+	if active != 291 {
+		t.Errorf("active = %v, want 291", active)
+	}
+	if requests != 31070465 {
+		t.Errorf("requests = %v, want 31070465", requests)
+	}
+}
+
+func TestParseStubStatusGarbage(t *testing.T) {
+	if _, _, err := parseStubStatus("<html>not nginx</html>"); err == nil {
+		t.Error("expected error for non-stub_status body")
+	}
+}
+
+const traefikMetricsSample = `# TYPE traefik_service_requests_total counter
+traefik_service_requests_total{code="200",method="GET",service="web@docker"} 1000
+traefik_service_requests_total{code="500",method="GET",service="web@docker"} 50
+traefik_service_requests_total{code="200",method="GET",service="other@docker"} 9999
+`
+
+func TestSumPromCounter(t *testing.T) {
+	sum, found := sumPromCounter(traefikMetricsSample, "traefik_service_requests_total", `service="web@docker`)
+	if !found {
+		t.Fatal("counter not found")
+	}
+	if sum != 1050 {
+		t.Errorf("sum = %v, want 1050", sum)
+	}
+
+	if _, found := sumPromCounter(traefikMetricsSample, "traefik_service_requests_total", `service="missing`); found {
+		t.Error("found a counter for a missing service")
+	}
+}
+
+// staticCounter feeds controlled values through rateMetric.
+type staticCounter struct{ v float64 }
+
+func (s *staticCounter) Name() string                             { return "static" }
+func (s *staticCounter) Collect(context.Context) (float64, error) { return s.v, nil }
+
+func TestRateMetric(t *testing.T) {
+	inner := &staticCounter{v: 100}
+	m := newRateMetric(inner)
+
+	if _, err := m.Collect(context.Background()); err == nil {
+		t.Error("first sample should not produce a rate")
+	}
+
+	// Backdate the stored sample so the second poll sees elapsed time.
+	m.lastTime = time.Now().Add(-10 * time.Second)
+	inner.v = 200
+	rate, err := m.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if rate < 9.5 || rate > 10.5 {
+		t.Errorf("rate = %v, want ~10/s", rate)
+	}
+
+	// A counter reset must not produce a negative rate.
+	m.lastTime = time.Now().Add(-10 * time.Second)
+	inner.v = 5
+	if _, err := m.Collect(context.Background()); err == nil {
+		t.Error("counter reset should be reported, not returned as a rate")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeRedis answers LLEN with the given reply line.
+func fakeRedis(t *testing.T, reply string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			// Read one RESP array command: header plus two lines per
+			// argument, then answer it.
+			header, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+			if err != nil {
+				return
+			}
+			for i := 0; i < 2*n; i++ {
+				if _, err := r.ReadString('\n'); err != nil {
+					return
+				}
+			}
+			conn.Write([]byte(reply + "\r\n"))
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedisMetric(t *testing.T) {
+	addr := fakeRedis(t, ":42")
+	m := newRedisMetric(addr, "", "jobs")
+	v, err := m.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if v != 42 {
+		t.Errorf("Collect = %v, want 42", v)
+	}
+}
+
+func TestRedisMetricError(t *testing.T) {
+	addr := fakeRedis(t, "-ERR wrong type")
+	m := newRedisMetric(addr, "", "jobs")
+	if _, err := m.Collect(context.Background()); err == nil || !strings.Contains(err.Error(), "wrong type") {
+		t.Errorf("Collect error = %v, want server error", err)
+	}
+}
+
+func TestRabbitMetric(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/queues/%2F/work" {
+			t.Errorf("path = %q, want /api/queues/%%2F/work", r.URL.EscapedPath())
+		}
+		w.Write([]byte(`{"messages": 117, "name": "work"}`))
+	}))
+	defer srv.Close()
+
+	m := newRabbitMetric(srv.URL, "/", "work")
+	v, err := m.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if v != 117 {
+		t.Errorf("Collect = %v, want 117", v)
+	}
+}
+
+func TestRabbitMetricNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	m := newRabbitMetric(srv.URL, "/", "missing")
+	if _, err := m.Collect(context.Background()); err == nil {
+		t.Error("expected error for missing queue")
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+)
+
+// maybeRefreshImages rolls replicas whose image is older than what the
+// service's tag resolves to now (i.e. something pulled a newer digest)
+// onto the new image. At most one replica is recreated per poll, and
+// only while every replica is running and passing its healthcheck, so
+// a bad image can never take more than one replica down. The caller
+// only invokes this during calm periods, which keeps the roll out of
+// the way of scaling decisions.
+func (a *autoscaler) maybeRefreshImages(ctx context.Context, containers []containerInfo) {
+	want, err := a.docker.ImageID(ctx, containers[0].Image)
+	if err != nil {
+		log.Printf("✗ image refresh: %v", err)
+		return
+	}
+	victim, ok := pickStaleReplica(containers, want)
+	if !ok {
+		return
+	}
+	log.Printf("i %s has a newer digest, recreating %s", containers[0].Image, victim.Name())
+	if err := recreateReplica(a.cfg, victim.Name(), len(containers)); err != nil {
+		log.Printf("✗ image refresh: %v", err)
+	}
+}
+
+// pickStaleReplica returns one container not running the wanted image.
+// Any replica that is stopped, mid-start or failing its healthcheck
+// vetoes the roll entirely: the previous recreate (or whatever else is
+// wrong) has to settle before we touch the next one.
+func pickStaleReplica(containers []containerInfo, want string) (containerInfo, bool) {
+	var victim containerInfo
+	var found bool
+	for _, c := range containers {
+		if c.State != "running" || strings.Contains(c.Status, "unhealthy") ||
+			strings.Contains(c.Status, "health: starting") {
+			return containerInfo{}, false
+		}
+		if c.ImageID != want && !found {
+			victim, found = c, true
+		}
+	}
+	return victim, found
+}
+
+// recreateReplica force-removes one container and has compose restore
+// the count, which creates the replacement from the current image. The
+// replica count is passed through so the service never drifts outside
+// the bounds the scaler is holding it to.
+func recreateReplica(cfg *config, name string, replicas int) error {
+	if cfg.dryRun {
+		log.Printf("[dry-run] would recreate %s on the new image", name)
+		return nil
+	}
+	out, err := exec.Command("docker", "rm", "-f", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker rm %s: %v: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return scaleTo(cfg, cfg.service, replicas)
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestPickStaleReplica(t *testing.T) {
+	const fresh = "sha256:new"
+	running := func(name, image string) containerInfo {
+		return containerInfo{Names: []string{"/" + name}, ImageID: image, State: "running", Status: "Up 2 hours (healthy)"}
+	}
+
+	// Everything current: nothing to roll.
+	if _, ok := pickStaleReplica([]containerInfo{running("web-1", fresh), running("web-2", fresh)}, fresh); ok {
+		t.Error("picked a replica with nothing stale")
+	}
+
+	// One stale replica gets picked.
+	v, ok := pickStaleReplica([]containerInfo{running("web-1", "sha256:old"), running("web-2", fresh)}, fresh)
+	if !ok || v.Name() != "web-1" {
+		t.Errorf("picked %q, %v; want web-1", v.Name(), ok)
+	}
+
+	// An unhealthy replica vetoes the whole roll, stale or not.
+	sick := running("web-2", fresh)
+	sick.Status = "Up 10 seconds (unhealthy)"
+	if _, ok := pickStaleReplica([]containerInfo{running("web-1", "sha256:old"), sick}, fresh); ok {
+		t.Error("rolled while a replica is unhealthy")
+	}
+
+	// Same for one still starting up or not running at all.
+	starting := running("web-2", fresh)
+	starting.Status = "Up 3 seconds (health: starting)"
+	if _, ok := pickStaleReplica([]containerInfo{running("web-1", "sha256:old"), starting}, fresh); ok {
+		t.Error("rolled while a replica's healthcheck is settling")
+	}
+	stopped := running("web-2", "sha256:old")
+	stopped.State = "exited"
+	if _, ok := pickStaleReplica([]containerInfo{running("web-1", "sha256:old"), stopped}, fresh); ok {
+		t.Error("rolled while a replica is stopped")
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The -config file holds the tunable scaling knobs as "name value"
+// lines (flag names, #-comments and blank lines allowed) and is re-read
+// on SIGHUP or a control API reload. Only threshold-style settings are
+// reloadable: anything wired into a constructed object at startup
+// (metric sources, drainers, the API listener) still needs a restart.
+
+// applyConfigSetting applies one reloadable setting to cfg, returning
+// the old and new values as strings for diff logging.
+func applyConfigSetting(cfg *config, name, value string) (oldV, newV string, err error) {
+	setF := func(p *float64) (string, string, error) {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", "", fmt.Errorf("bad value %q for %s", value, name)
+		}
+		old := strconv.FormatFloat(*p, 'g', -1, 64)
+		*p = v
+		return old, strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+	setI := func(p *int) (string, string, error) {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return "", "", fmt.Errorf("bad value %q for %s", value, name)
+		}
+		old := strconv.Itoa(*p)
+		*p = v
+		return old, value, nil
+	}
+	setD := func(p *time.Duration) (string, string, error) {
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return "", "", fmt.Errorf("bad value %q for %s", value, name)
+		}
+		old := p.String()
+		*p = v
+		return old, v.String(), nil
+	}
+	setB := func(p *bool) (string, string, error) {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", "", fmt.Errorf("bad value %q for %s", value, name)
+		}
+		old := strconv.FormatBool(*p)
+		*p = v
+		return old, strconv.FormatBool(v), nil
+	}
+
+	switch name {
+	case "cpu-up":
+		return setF(&cfg.cpuUp)
+	case "cpu-down":
+		return setF(&cfg.cpuDown)
+	case "mem-up":
+		return setF(&cfg.memUp)
+	case "mem-down":
+		return setF(&cfg.memDown)
+	case "metric-up":
+		return setF(&cfg.metricUp)
+	case "metric-down":
+		return setF(&cfg.metricDown)
+	case "min":
+		return setI(&cfg.minReplicas)
+	case "max":
+		return setI(&cfg.maxReplicas)
+	case "cooldown":
+		return setD(&cfg.cooldown)
+	case "adaptive-cooldown":
+		return setB(&cfg.adaptiveCooldown)
+	case "scale-up-checks":
+		return setI(&cfg.scaleUpChecks)
+	case "scale-down-checks":
+		return setI(&cfg.scaleDownChecks)
+	case "min-samples":
+		return setI(&cfg.minSamples)
+	case "burst-pct":
+		return setF(&cfg.burstPct)
+	case "replica-cost":
+		return setF(&cfg.replicaCost)
+	case "cost-budget":
+		return setF(&cfg.costBudget)
+	case "scale-down-step":
+		return setI(&cfg.scaleDownStep)
+	case "scale-up-pct":
+		return setF(&cfg.scaleUpPct)
+	case "scale-down-pct":
+		return setF(&cfg.scaleDownPct)
+	case "hysteresis-pct":
+		return setF(&cfg.hysteresisPct)
+	case "max-events-per-hour":
+		return setI(&cfg.maxEventsPerHour)
+	case "max-added-10m":
+		return setI(&cfg.maxAddedPer10m)
+	case "forecast-lead":
+		return setD(&cfg.forecastLead)
+	case "deploy-blackout":
+		return setD(&cfg.deployBlackout)
+	default:
+		return "", "", fmt.Errorf("%s is not reloadable", name)
+	}
+}
+
+// validateReload sanity-checks the settings a reload would produce.
+func validateReload(cfg *config) error {
+	if cfg.minReplicas < 1 {
+		return fmt.Errorf("min must be at least 1, got %d", cfg.minReplicas)
+	}
+	if cfg.maxReplicas < cfg.minReplicas {
+		return fmt.Errorf("max %d is below min %d", cfg.maxReplicas, cfg.minReplicas)
+	}
+	if cfg.cpuDown >= cfg.cpuUp {
+		return fmt.Errorf("cpu-down %g is not below cpu-up %g", cfg.cpuDown, cfg.cpuUp)
+	}
+	if cfg.memDown >= cfg.memUp {
+		return fmt.Errorf("mem-down %g is not below mem-up %g", cfg.memDown, cfg.memUp)
+	}
+	if cfg.burstPct > 0 && (cfg.burstPct < cfg.cpuUp || cfg.burstPct < cfg.memUp) {
+		return fmt.Errorf("burst-pct %g is below the normal scale-up thresholds", cfg.burstPct)
+	}
+	if cfg.scaleUpChecks < 1 || cfg.scaleDownChecks < 1 {
+		return fmt.Errorf("scale-up-checks and scale-down-checks must be at least 1")
+	}
+	if cfg.cooldown < 0 {
+		return fmt.Errorf("cooldown cannot be negative")
+	}
+	return nil
+}
+
+// reloadConfig applies a settings file to cfg atomically: every line is
+// first applied to a scratch copy and the result validated, so a typo
+// halfway down cannot leave the live thresholds half-updated. It
+// returns a "name old -> new" diff line per changed setting.
+func reloadConfig(cfg *config, r io.Reader) ([]string, error) {
+	type setting struct{ name, value string }
+	var settings []setting
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want \"name value\", got %q", lineNo, line)
+		}
+		settings = append(settings, setting{fields[0], fields[1]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	scratch := *cfg
+	for _, s := range settings {
+		if _, _, err := applyConfigSetting(&scratch, s.name, s.value); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateReload(&scratch); err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, s := range settings {
+		oldV, newV, _ := applyConfigSetting(cfg, s.name, s.value)
+		if oldV != newV {
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", s.name, oldV, newV))
+		}
+	}
+	return changes, nil
+}
+
+// loadConfigFile reads cfg.configFile into cfg.
+func loadConfigFile(cfg *config) ([]string, error) {
+	f, err := os.Open(cfg.configFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return reloadConfig(cfg, f)
+}
+
+// requestReload asks the poll loop to re-read the config file. Like
+// approved decisions, the apply happens on the poll goroutine so the
+// thresholds never change mid-evaluation.
+func (a *autoscaler) requestReload() {
+	select {
+	case a.reload <- struct{}{}:
+	default: // a reload is already queued
+	}
+}
+
+// applyConfigReload re-reads the config file and logs what changed.
+func (a *autoscaler) applyConfigReload() {
+	if a.cfg.configFile == "" {
+		log.Printf("i reload requested but no -config file is set")
+		return
+	}
+	changes, err := loadConfigFile(a.cfg)
+	if err != nil {
+		log.Printf("✗ config reload: %v (keeping current settings)", err)
+		return
+	}
+	if len(changes) == 0 {
+		log.Printf("i config reloaded, no changes")
+		return
+	}
+	for _, c := range changes {
+		log.Printf("i config reloaded: %s", c)
+	}
+	// New thresholds get the same -min-samples warm-up as startup.
+	a.goodPolls = 0
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReloadConfig(t *testing.T) {
+	cfg := &config{
+		minReplicas: 1, maxReplicas: 5,
+		cpuUp: 80, cpuDown: 20, memUp: 80, memDown: 20,
+		scaleUpChecks: 1, scaleDownChecks: 3,
+		cooldown: 2 * time.Minute,
+	}
+	changes, err := reloadConfig(cfg, strings.NewReader(`
+# tuned after the launch spike
+cpu-up   75
+cooldown 5m
+max      8
+mem-up   80
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.cpuUp != 75 || cfg.cooldown != 5*time.Minute || cfg.maxReplicas != 8 {
+		t.Errorf("cfg after reload: cpu-up %g, cooldown %s, max %d", cfg.cpuUp, cfg.cooldown, cfg.maxReplicas)
+	}
+	// mem-up was set to its current value: no diff line for it.
+	want := []string{"cpu-up 80 -> 75", "cooldown 2m0s -> 5m0s", "max 5 -> 8"}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d = %q, want %q", i, changes[i], want[i])
+		}
+	}
+}
+
+func TestMinSamplesWarmup(t *testing.T) {
+	a := &autoscaler{cfg: &config{
+		minSamples:  2,
+		minReplicas: 1, maxReplicas: 5,
+		cpuUp: 80, cpuDown: 20, memUp: 80, memDown: 20,
+		scaleUpChecks: 1, scaleDownChecks: 3,
+	}}
+	a.now = time.Now
+	ctx := context.Background()
+
+	// The first polls are held even though replicas is below the
+	// configured minimum; nothing has been cross-checked yet.
+	for i := 1; i <= 2; i++ {
+		if calm := a.decide(ctx, 2, 50, 50, nil, false); calm {
+			t.Errorf("poll %d during warm-up reported calm", i)
+		}
+		if a.goodPolls != i {
+			t.Fatalf("goodPolls after poll %d = %d", i, a.goodPolls)
+		}
+	}
+	if calm := a.decide(ctx, 2, 50, 50, nil, false); !calm {
+		t.Error("poll after warm-up not calm")
+	}
+
+	// A config reload starts the warm-up over.
+	path := filepath.Join(t.TempDir(), "goscale.conf")
+	if err := os.WriteFile(path, []byte("cpu-up 75\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	a.cfg.configFile = path
+	a.applyConfigReload()
+	if a.goodPolls != 0 {
+		t.Errorf("goodPolls after reload = %d, want 0", a.goodPolls)
+	}
+}
+
+func TestReloadConfigAtomic(t *testing.T) {
+	cfg := &config{
+		minReplicas: 1, maxReplicas: 5,
+		cpuUp: 80, cpuDown: 20, memUp: 80, memDown: 20,
+		scaleUpChecks: 1, scaleDownChecks: 3,
+	}
+	for _, bad := range []string{
+		"cpu-up 75\ninterval 10s", // not reloadable
+		"cpu-up 75\nmem-up high",  // bad value
+		"cpu-up 75\nmax 0",        // fails validation (max below min)
+		"cpu-up 75\ncpu-down 90",  // fails validation (down above up)
+		"cpu-up 75 extra",         // malformed line
+		"min 3\nmax 2",            // bounds cross
+		"scale-down-checks 0",     // checks below 1
+		"cpu-up 75\ncooldown -1m", // negative cooldown
+	} {
+		if _, err := reloadConfig(cfg, strings.NewReader(bad)); err == nil {
+			t.Errorf("reloadConfig(%q) succeeded, want error", bad)
+		}
+		if cfg.cpuUp != 80 {
+			t.Fatalf("reloadConfig(%q) changed cpu-up to %g despite the error", bad, cfg.cpuUp)
+		}
+	}
+}
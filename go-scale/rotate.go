@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// rotatingWriter appends to -log-file and rotates it in-process, so a
+// daemon can run for months on a host with no logrotate configuration
+// and still not grow without bound. Rotation shifts file -> file.1 ->
+// file.2 up to `keep` generations, by size and — measured from when
+// this process opened the file — by age.
+type rotatingWriter struct {
+	path    string
+	maxSize int64         // bytes; 0 disables size rotation
+	maxAge  time.Duration // 0 disables age rotation
+	keep    int           // rotated generations retained
+
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newRotatingWriter(path string, maxSize int64, maxAge time.Duration, keep int) (*rotatingWriter, error) {
+	if keep < 1 {
+		keep = 1
+	}
+	w := &rotatingWriter{path: path, maxSize: maxSize, maxAge: maxAge, keep: keep}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f, w.size, w.openedAt = f, st.Size(), time.Now()
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			// Rotation failing (permissions, disk state) must not lose
+			// the log line too; rotate reopens the live file and we
+			// carry on writing to it.
+			fmt.Fprintf(os.Stderr, "go-scale: log rotation: %v\n", err)
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotatingWriter) shouldRotate(add int64) bool {
+	if w.maxSize > 0 && w.size > 0 && w.size+add > w.maxSize {
+		return true
+	}
+	if w.maxAge > 0 && time.Since(w.openedAt) >= w.maxAge {
+		return true
+	}
+	return false
+}
+
+// rotate shifts the generation chain and reopens the live file.
+func (w *rotatingWriter) rotate() error {
+	w.f.Close()
+	os.Remove(fmt.Sprintf("%s.%d", w.path, w.keep))
+	for i := w.keep - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		// Couldn't shift the live file aside; reopen it so logging
+		// keeps working and try again next write.
+		w.open()
+		return err
+	}
+	return w.open()
+}
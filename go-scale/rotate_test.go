@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingWriterBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scale.log")
+	w, err := newRotatingWriter(path, 32, 0, 2)
+	if err != nil {
+		t.Fatalf("newRotatingWriter: %v", err)
+	}
+
+	line := strings.Repeat("x", 19) + "\n" // two lines cross the 32-byte cap
+	for i := 0; i < 4; i++ {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		st, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("%s missing after rotation: %v", name, err)
+		}
+		if st.Size() == 0 {
+			t.Errorf("%s is empty", name)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("kept more generations than -log-keep")
+	}
+}
+
+func TestRotatingWriterNoRotationUnderCap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scale.log")
+	w, err := newRotatingWriter(path, 1<<20, 0, 2)
+	if err != nil {
+		t.Fatalf("newRotatingWriter: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		w.Write([]byte("hello\n"))
+	}
+	if _, err := os.Stat(path + ".1"); err == nil {
+		t.Error("rotated below the size cap")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iamdanielv/utils/pkg/scaler"
+)
+
+// Compose derives container names from a per-project counter, so two
+// concurrent `up --scale` runs against one project race on naming.
+// Overlapping triggers — a forced evaluation landing during a poll, a
+// verify rollback, or several services sharing one project in
+// multi-target mode — queue on the project's lock instead.
+var (
+	projectMu    sync.Mutex
+	projectLocks = map[string]*sync.Mutex{}
+)
+
+func projectLock(project string) *sync.Mutex {
+	projectMu.Lock()
+	defer projectMu.Unlock()
+	mu, ok := projectLocks[project]
+	if !ok {
+		mu = &sync.Mutex{}
+		projectLocks[project] = mu
+	}
+	return mu
+}
+
+// composeBackend adapts the compose-relevant config fields to the
+// library scaler in pkg/scaler, which owns the CLI invocation, retry
+// and timeout logic.
+func composeBackend(cfg *config) scaler.Compose {
+	return scaler.Compose{
+		Project:    cfg.project,
+		Files:      cfg.composeFiles,
+		ProjectDir: cfg.projectDir,
+		Profiles:   cfg.profiles,
+		Timeout:    cfg.scaleTimeout,
+		Retries:    cfg.scaleRetries,
+		DryRun:     cfg.dryRun,
+	}
+}
+
+// composeArgs builds the common compose invocation prefix for the
+// subcommands that shell out themselves.
+func composeArgs(cfg *config) []string {
+	return composeBackend(cfg).Args()
+}
+
+// scaleTo sets the replica count for a compose service. Invocations
+// for the same project are serialized; the compose timer starts after
+// the lock so queueing doesn't inflate it.
+func scaleTo(cfg *config, service string, replicas int) error {
+	mu := projectLock(cfg.project)
+	mu.Lock()
+	defer mu.Unlock()
+	defer self.timeCompose(time.Now())
+	err := composeBackend(cfg).ScaleTo(service, replicas)
+	if err != nil {
+		self.countError("compose")
+	}
+	return err
+}
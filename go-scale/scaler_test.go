@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposeArgs(t *testing.T) {
+	cfg := &config{project: "demo"}
+	if got := strings.Join(composeArgs(cfg), " "); got != "compose -p demo" {
+		t.Errorf("bare project: %q", got)
+	}
+
+	cfg.composeFiles = listFlag{"a.yml", "b.yml"}
+	cfg.projectDir = "/srv/demo"
+	want := "compose -p demo -f a.yml -f b.yml --project-directory /srv/demo"
+	if got := strings.Join(composeArgs(cfg), " "); got != want {
+		t.Errorf("composeArgs = %q, want %q", got, want)
+	}
+
+	cfg.profiles = listFlag{"workers", "batch"}
+	want += " --profile workers --profile batch"
+	if got := strings.Join(composeArgs(cfg), " "); got != want {
+		t.Errorf("composeArgs with profiles = %q, want %q", got, want)
+	}
+}
+
+func TestProjectLockIdentity(t *testing.T) {
+	if projectLock("shop") != projectLock("shop") {
+		t.Error("same project should share one lock")
+	}
+	if projectLock("shop") == projectLock("blog") {
+		t.Error("different projects should not share a lock")
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleRule adjusts the replica bounds at cron-given times, e.g.
+// "0 8 * * 1-5 min=4" guarantees four replicas from 08:00 on weekdays.
+// Metric-based scaling keeps working inside the adjusted bounds; the
+// most recently fired rule wins until the next one fires.
+type scheduleRule struct {
+	spec     cronSpec
+	min, max int // 0 leaves the configured bound unchanged
+	raw      string
+}
+
+// scheduleFlag collects repeatable -schedule rules.
+type scheduleFlag []scheduleRule
+
+func (s *scheduleFlag) String() string {
+	var parts []string
+	for _, r := range *s {
+		parts = append(parts, r.raw)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (s *scheduleFlag) Set(v string) error {
+	rule, err := parseScheduleRule(v)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, rule)
+	return nil
+}
+
+// parseScheduleRule parses "MIN HOUR DOM MON DOW min=N [max=N]".
+func parseScheduleRule(v string) (scheduleRule, error) {
+	fields := strings.Fields(v)
+	if len(fields) < 6 {
+		return scheduleRule{}, fmt.Errorf("schedule %q: want 5 cron fields plus min=/max= settings", v)
+	}
+	spec, err := parseCron(fields[:5])
+	if err != nil {
+		return scheduleRule{}, fmt.Errorf("schedule %q: %w", v, err)
+	}
+	rule := scheduleRule{spec: spec, raw: v}
+	for _, setting := range fields[5:] {
+		key, raw, ok := strings.Cut(setting, "=")
+		if !ok {
+			return scheduleRule{}, fmt.Errorf("schedule %q: bad setting %q", v, setting)
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return scheduleRule{}, fmt.Errorf("schedule %q: bad count in %q", v, setting)
+		}
+		switch key {
+		case "min":
+			rule.min = n
+		case "max":
+			rule.max = n
+		default:
+			return scheduleRule{}, fmt.Errorf("schedule %q: unknown setting %q (want min or max)", v, key)
+		}
+	}
+	if rule.min != 0 && rule.max != 0 && rule.min > rule.max {
+		return scheduleRule{}, fmt.Errorf("schedule %q: min > max", v)
+	}
+	return rule, nil
+}
+
+// cronSpec is a parsed five-field cron expression.
+type cronSpec struct {
+	minute, hour, dom, month, dow cronField
+}
+
+// cronField is one cron field as a bit set; any is "*".
+type cronField struct {
+	any bool
+	set uint64
+}
+
+func (f cronField) has(n int) bool { return f.any || f.set&(1<<uint(n)) != 0 }
+
+// parseCron parses the five standard cron fields, supporting *, lists,
+// ranges and */step.
+func parseCron(fields []string) (cronSpec, error) {
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("want 5 cron fields, got %d", len(fields))
+	}
+	bounds := []struct{ lo, hi int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	parsed := make([]cronField, 5)
+	for i, field := range fields {
+		f, err := parseCronField(field, bounds[i].lo, bounds[i].hi)
+		if err != nil {
+			return cronSpec{}, fmt.Errorf("field %d: %w", i+1, err)
+		}
+		parsed[i] = f
+	}
+	// Both 0 and 7 mean Sunday.
+	if parsed[4].has(7) {
+		parsed[4].set |= 1
+	}
+	return cronSpec{minute: parsed[0], hour: parsed[1], dom: parsed[2], month: parsed[3], dow: parsed[4]}, nil
+}
+
+func parseCronField(field string, lo, hi int) (cronField, error) {
+	if field == "*" {
+		return cronField{any: true}, nil
+	}
+	var f cronField
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, rawStep, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(rawStep)
+			if err != nil || n < 1 {
+				return cronField{}, fmt.Errorf("bad step in %q", part)
+			}
+			part, step = base, n
+		}
+		start, end := lo, hi
+		switch {
+		case part == "*":
+			// keep full range
+		case strings.Contains(part, "-"):
+			rawLo, rawHi, _ := strings.Cut(part, "-")
+			var err1, err2 error
+			start, err1 = strconv.Atoi(rawLo)
+			end, err2 = strconv.Atoi(rawHi)
+			if err1 != nil || err2 != nil || start > end {
+				return cronField{}, fmt.Errorf("bad range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return cronField{}, fmt.Errorf("bad value %q", part)
+			}
+			start, end = n, n
+		}
+		if start < lo || end > hi {
+			return cronField{}, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for n := start; n <= end; n += step {
+			f.set |= 1 << uint(n)
+		}
+	}
+	return f, nil
+}
+
+// matches reports whether the spec fires at t, using standard cron
+// day-matching: when both day-of-month and day-of-week are restricted,
+// either one matching fires the rule.
+func (c cronSpec) matches(t time.Time) bool {
+	if !c.minute.has(t.Minute()) || !c.hour.has(t.Hour()) || !c.month.has(int(t.Month())) {
+		return false
+	}
+	domOK := c.dom.has(t.Day())
+	dowOK := c.dow.has(int(t.Weekday()))
+	if !c.dom.any && !c.dow.any {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+// lastFire finds the most recent minute at or before now when the spec
+// fired, looking back at most lookback.
+func (c cronSpec) lastFire(now time.Time, lookback time.Duration) (time.Time, bool) {
+	t := now.Truncate(time.Minute)
+	for since := time.Duration(0); since <= lookback; since += time.Minute {
+		if c.matches(t) {
+			return t, true
+		}
+		t = t.Add(-time.Minute)
+	}
+	return time.Time{}, false
+}
+
+// scheduleLookback bounds the search for a rule's last firing; rules
+// fire at least weekly in practice, and anything older should not pin
+// the bounds forever anyway.
+const scheduleLookback = 8 * 24 * time.Hour
+
+// effectiveBounds resolves the replica bounds at now: the configured
+// min/max, overridden by whichever schedule rule fired most recently.
+func effectiveBounds(cfg *config, now time.Time) (min, max int) {
+	min, max = cfg.minReplicas, cfg.maxReplicas
+	var winner *scheduleRule
+	var winnerAt time.Time
+	for i := range cfg.schedules {
+		rule := &cfg.schedules[i]
+		at, ok := rule.spec.lastFire(now, scheduleLookback)
+		if !ok {
+			continue
+		}
+		if winner == nil || at.After(winnerAt) {
+			winner, winnerAt = rule, at
+		}
+	}
+	if winner != nil {
+		if winner.min != 0 {
+			min = winner.min
+		}
+		if winner.max != 0 {
+			max = winner.max
+		}
+		if max < min {
+			max = min
+		}
+	}
+	return min, max
+}
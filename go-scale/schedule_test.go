@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mustRule(t *testing.T, v string) scheduleRule {
+	t.Helper()
+	rule, err := parseScheduleRule(v)
+	if err != nil {
+		t.Fatalf("parseScheduleRule(%q): %v", v, err)
+	}
+	return rule
+}
+
+func TestParseScheduleRule(t *testing.T) {
+	rule := mustRule(t, "0 8 * * 1-5 min=4")
+	if rule.min != 4 || rule.max != 0 {
+		t.Errorf("rule = %+v", rule)
+	}
+	rule = mustRule(t, "0 20 * * * min=1 max=3")
+	if rule.min != 1 || rule.max != 3 {
+		t.Errorf("rule = %+v", rule)
+	}
+
+	for _, bad := range []string{
+		"0 8 * * 1-5",             // no settings
+		"0 8 * * min=4",           // short cron
+		"0 8 * * 1-5 min=zero",    // bad count
+		"0 8 * * 1-5 foo=4",       // unknown setting
+		"0 8 * * 1-5 min=5 max=2", // inverted
+		"0 25 * * * min=2",        // hour out of range
+	} {
+		if _, err := parseScheduleRule(bad); err == nil {
+			t.Errorf("parseScheduleRule(%q) accepted", bad)
+		}
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	spec := mustRule(t, "0 8 * * 1-5 min=4").spec
+	monday := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	if !spec.matches(monday) {
+		t.Error("monday 08:00 did not match")
+	}
+	if spec.matches(monday.Add(time.Minute)) {
+		t.Error("08:01 matched")
+	}
+	saturday := time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC)
+	if spec.matches(saturday) {
+		t.Error("saturday matched 1-5")
+	}
+
+	// Steps and lists.
+	spec = mustRule(t, "*/15 9,17 * * 0 min=2").spec
+	sunday := time.Date(2024, 1, 7, 17, 45, 0, 0, time.UTC)
+	if !spec.matches(sunday) {
+		t.Error("sunday 17:45 did not match */15 9,17 dow=0")
+	}
+
+	// 7 also means Sunday.
+	spec = mustRule(t, "0 0 * * 7 min=2").spec
+	if !spec.matches(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Error("dow=7 did not match Sunday")
+	}
+}
+
+func TestEffectiveBounds(t *testing.T) {
+	cfg := &config{minReplicas: 1, maxReplicas: 5}
+	now := time.Date(2024, 1, 8, 10, 30, 0, 0, time.UTC) // Monday mid-morning
+
+	if min, max := effectiveBounds(cfg, now); min != 1 || max != 5 {
+		t.Errorf("no rules: %d-%d", min, max)
+	}
+
+	cfg.schedules = scheduleFlag{
+		mustRule(t, "0 8 * * 1-5 min=4"),
+		mustRule(t, "0 20 * * * min=1"),
+	}
+	// 08:00 fired more recently than yesterday's 20:00.
+	if min, max := effectiveBounds(cfg, now); min != 4 || max != 5 {
+		t.Errorf("business hours: %d-%d, want 4-5", min, max)
+	}
+	// After 20:00 the evening rule wins again.
+	evening := time.Date(2024, 1, 8, 21, 0, 0, 0, time.UTC)
+	if min, _ := effectiveBounds(cfg, evening); min != 1 {
+		t.Errorf("evening min = %d, want 1", min)
+	}
+
+	// A scheduled min above the configured max lifts the max with it.
+	cfg = &config{minReplicas: 1, maxReplicas: 3,
+		schedules: scheduleFlag{mustRule(t, "0 8 * * 1-5 min=4")}}
+	if min, max := effectiveBounds(cfg, now); min != 4 || max != 4 {
+		t.Errorf("min above max: %d-%d, want 4-4", min, max)
+	}
+}
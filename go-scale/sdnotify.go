@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// sd_notify integration: when systemd launches us with Type=notify it
+// sets NOTIFY_SOCKET; READY=1 goes out once startup validation has
+// passed, and WATCHDOG=1 pings flow only while the poll loops are
+// actually turning, so a wedged loop gets the unit restarted. All of
+// it degrades to no-ops outside systemd.
+
+// sdNotify sends one state message to the systemd notify socket.
+func sdNotify(state string) {
+	sock := os.Getenv("NOTIFY_SOCKET")
+	if sock == "" {
+		return
+	}
+	conn, err := net.Dial("unixgram", sock)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte(state))
+}
+
+// watchdogInterval returns how often to ping the systemd watchdog:
+// half the WATCHDOG_USEC budget, or 0 when no watchdog is armed for
+// this process.
+func watchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// pollsAlive reports whether every poll loop has turned recently. The
+// slack over two intervals covers a slow compose call without letting
+// a truly wedged loop keep pinging.
+func pollsAlive(scalers []*autoscaler, interval time.Duration) bool {
+	cutoff := time.Now().Add(-(2*interval + 30*time.Second))
+	for _, as := range scalers {
+		if as.lastBeatTime().Before(cutoff) {
+			return false
+		}
+	}
+	return true
+}
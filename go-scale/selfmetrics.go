@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Self-metrics: counters and latency histograms about the autoscaler
+// itself, served at /metrics on the control API in the Prometheus text
+// format. When the thing that scales your services misbehaves, its own
+// numbers — how long polls take, how often compose fails — are the
+// first place to look.
+
+// histBounds are the histogram bucket upper bounds in seconds, spanning
+// a fast local docker call to a compose invocation grinding through
+// retries.
+var histBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// numHistBuckets is len(histBounds) plus one for the +Inf overflow.
+const numHistBuckets = 13
+
+// histogram is a fixed-bucket latency histogram. Callers hold the
+// selfMetrics mutex.
+type histogram struct {
+	counts [numHistBuckets]uint64
+	sum    float64
+	n      uint64
+}
+
+func (h *histogram) observe(seconds float64) {
+	i := sort.SearchFloat64s(histBounds, seconds)
+	h.counts[i]++
+	h.sum += seconds
+	h.n++
+}
+
+// selfMetrics is the process-wide collector. One instance covers every
+// target: the labels tell series apart, not separate registries.
+type selfMetrics struct {
+	mu        sync.Mutex
+	decisions map[string]uint64 // scale event count by outcome
+	errors    map[string]uint64 // error count by subsystem
+
+	replicaHours map[string]float64 // accrued replica-hours by service
+
+	evalDur    histogram // one full evaluation, listing to decision
+	statsDur   histogram // one container stats fetch
+	composeDur histogram // one scaleTo call, retries included
+}
+
+// self is the collector for this process.
+var self = &selfMetrics{
+	decisions:    map[string]uint64{},
+	errors:       map[string]uint64{},
+	replicaHours: map[string]float64{},
+}
+
+func (m *selfMetrics) countDecision(outcome string) {
+	m.mu.Lock()
+	m.decisions[outcome]++
+	m.mu.Unlock()
+}
+
+func (m *selfMetrics) countError(kind string) {
+	m.mu.Lock()
+	m.errors[kind]++
+	m.mu.Unlock()
+}
+
+func (m *selfMetrics) addReplicaHours(service string, hours float64) {
+	m.mu.Lock()
+	m.replicaHours[service] += hours
+	m.mu.Unlock()
+}
+
+// timeEval records one evaluation's duration; use with defer and the
+// wall clock (not a.now — the simulator's fake clock would lie here).
+func (m *selfMetrics) timeEval(start time.Time) {
+	m.mu.Lock()
+	m.evalDur.observe(time.Since(start).Seconds())
+	m.mu.Unlock()
+}
+
+func (m *selfMetrics) timeStats(start time.Time) {
+	m.mu.Lock()
+	m.statsDur.observe(time.Since(start).Seconds())
+	m.mu.Unlock()
+}
+
+func (m *selfMetrics) timeCompose(start time.Time) {
+	m.mu.Lock()
+	m.composeDur.observe(time.Since(start).Seconds())
+	m.mu.Unlock()
+}
+
+// writeProm renders the collector in the Prometheus text exposition
+// format, hand-rolled since the tree carries no client library.
+func (m *selfMetrics) writeProm(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	writeCounterVec(w, "goscale_decisions_total", "Scale decisions by outcome.", "outcome", m.decisions)
+	writeCounterVec(w, "goscale_errors_total", "Errors by subsystem.", "type", m.errors)
+	writeFloatCounterVec(w, "goscale_replica_hours_total", "Accrued replica-hours by service.", "service", m.replicaHours)
+	writeHistogram(w, "goscale_evaluation_duration_seconds", "Duration of one poll evaluation.", &m.evalDur)
+	writeHistogram(w, "goscale_stats_fetch_duration_seconds", "Duration of one container stats fetch.", &m.statsDur)
+	writeHistogram(w, "goscale_compose_scale_duration_seconds", "Duration of one compose scale command, retries included.", &m.composeDur)
+}
+
+func writeCounterVec(w io.Writer, name, help, label string, vals map[string]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", name, label, k, vals[k])
+	}
+}
+
+func writeFloatCounterVec(w io.Writer, name, help, label string, vals map[string]float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	keys := make([]string, 0, len(vals))
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=%q} %g\n", name, label, k, vals[k])
+	}
+}
+
+func writeHistogram(w io.Writer, name, help string, h *histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	var cum uint64
+	for i, le := range histBounds {
+		cum += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", name, fmt.Sprintf("%g", le), cum)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.n)
+	fmt.Fprintf(w, "%s_sum %g\n", name, h.sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, h.n)
+}
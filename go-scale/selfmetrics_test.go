@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	var h histogram
+	h.observe(0.003) // below the first bound
+	h.observe(0.005) // exactly on a bound: le is inclusive
+	h.observe(0.3)
+	h.observe(60) // beyond the last bound: +Inf only
+
+	if h.n != 4 {
+		t.Fatalf("count = %d, want 4", h.n)
+	}
+	if h.counts[0] != 2 {
+		t.Errorf("le=0.005 bucket = %d, want 2", h.counts[0])
+	}
+	if h.counts[len(histBounds)] != 1 {
+		t.Errorf("+Inf overflow bucket = %d, want 1", h.counts[len(histBounds)])
+	}
+}
+
+func TestWriteProm(t *testing.T) {
+	m := &selfMetrics{
+		decisions: map[string]uint64{},
+		errors:    map[string]uint64{},
+	}
+	m.countDecision("applied")
+	m.countDecision("applied")
+	m.countDecision("vetoed")
+	m.countError("compose")
+	m.evalDur.observe(0.02)
+	m.evalDur.observe(0.04)
+
+	var sb strings.Builder
+	m.writeProm(&sb)
+	out := sb.String()
+
+	for _, want := range []string{
+		`goscale_decisions_total{outcome="applied"} 2`,
+		`goscale_decisions_total{outcome="vetoed"} 1`,
+		`goscale_errors_total{type="compose"} 1`,
+		"# TYPE goscale_evaluation_duration_seconds histogram",
+		`goscale_evaluation_duration_seconds_bucket{le="0.025"} 1`,
+		`goscale_evaluation_duration_seconds_bucket{le="0.05"} 2`,
+		`goscale_evaluation_duration_seconds_bucket{le="+Inf"} 2`,
+		"goscale_evaluation_duration_seconds_count 2",
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("exposition missing %q:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(out, "goscale_evaluation_duration_seconds_sum 0.06") {
+		t.Errorf("exposition sum wrong:\n%s", out)
+	}
+}
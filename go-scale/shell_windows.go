@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand wraps a script in the platform shell, so hooks can be
+// one-liners as well as script paths. cmd.exe handles the splitting on
+// Windows.
+func shellCommand(ctx context.Context, script string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/c", script)
+}
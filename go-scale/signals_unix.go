@@ -0,0 +1,45 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/iamdanielv/utils/internal/lifecycle"
+)
+
+// watchOperatorSignals wires the unix operator signals: SIGHUP re-reads
+// -config, SIGUSR1 toggles the paused state, SIGUSR2 forces an
+// evaluation outside the interval.
+func watchOperatorSignals(grp *lifecycle.Group, ts *targetSet) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2)
+	grp.Go(func(ctx context.Context) error {
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case sig := <-sigs:
+				for _, as := range ts.all() {
+					switch sig {
+					case syscall.SIGHUP:
+						as.requestReload()
+					case syscall.SIGUSR1:
+						if as.togglePaused() {
+							log.Printf("%s/%s paused via SIGUSR1 (monitoring continues, scale actions held)", as.cfg.project, as.cfg.service)
+						} else {
+							log.Printf("%s/%s resumed via SIGUSR1", as.cfg.project, as.cfg.service)
+						}
+					case syscall.SIGUSR2:
+						as.requestEvaluation()
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package main
+
+import "github.com/iamdanielv/utils/internal/lifecycle"
+
+// watchOperatorSignals is a no-op on Windows, which has no SIGHUP or
+// SIGUSR signals. Reload, pause and forced evaluation remain available
+// through the control API.
+func watchOperatorSignals(*lifecycle.Group, *targetSet) {}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Simulation mode replays a recorded (or hand-written) load trace
+// through the real decision logic — thresholds, steps, streaks,
+// cooldowns, schedules — with a fake clock, so thresholds can be tuned
+// offline before touching production. Trace lines are:
+//
+//	<offset> <cpu%> <mem%> [metric]
+//
+// where offset is a Go duration from the start of the trace (e.g. 30s,
+// 5m). Blank lines and #-comments are skipped.
+
+// simPoint is one sampled poll in a load trace.
+type simPoint struct {
+	at       time.Duration
+	cpu, mem float64
+	metric   *float64
+}
+
+// parseSimTrace reads a load trace. Offsets must not decrease: the
+// simulator only moves the clock forward.
+func parseSimTrace(r io.Reader) ([]simPoint, error) {
+	var points []simPoint
+	sc := bufio.NewScanner(r)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, fmt.Errorf("trace line %d: want \"offset cpu mem [metric]\", got %q", lineNo, line)
+		}
+		at, err := time.ParseDuration(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("trace line %d: bad offset %q: %v", lineNo, fields[0], err)
+		}
+		if len(points) > 0 && at < points[len(points)-1].at {
+			return nil, fmt.Errorf("trace line %d: offset %s goes backwards", lineNo, fields[0])
+		}
+		p := simPoint{at: at}
+		if p.cpu, err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return nil, fmt.Errorf("trace line %d: bad cpu %q", lineNo, fields[1])
+		}
+		if p.mem, err = strconv.ParseFloat(fields[2], 64); err != nil {
+			return nil, fmt.Errorf("trace line %d: bad mem %q", lineNo, fields[2])
+		}
+		if len(fields) == 4 {
+			v, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				return nil, fmt.Errorf("trace line %d: bad metric %q", lineNo, fields[3])
+			}
+			p.metric = &v
+		}
+		points = append(points, p)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// simResult is one trace point's outcome in the printed timeline.
+type simResult struct {
+	point    simPoint
+	replicas int         // replica count after this point
+	event    *scaleEvent // non-nil when this point scaled
+}
+
+// simulate feeds a trace through the decision logic with a fake clock.
+// Scaling is forced into dry-run, so nothing touches docker; the
+// simulated replica count follows the applied events instead.
+func simulate(cfg *config, points []simPoint) []simResult {
+	cfg.dryRun = true
+	cfg.approvalMode = false
+	a := newAutoscaler(cfg, nil)
+	a.metric = nil // samples come from the trace, not a live source
+
+	ctx := context.Background()
+	base := time.Now()
+	replicas := cfg.minReplicas
+	seen := 0
+	results := make([]simResult, 0, len(points))
+	for _, p := range points {
+		now := base.Add(p.at)
+		a.now = func() time.Time { return now }
+		a.decide(ctx, replicas, p.cpu, p.mem, p.metric, false)
+
+		res := simResult{point: p, replicas: replicas}
+		events := a.recentEvents()
+		for i := seen; i < len(events); i++ {
+			if e := events[i]; e.Outcome == "applied" {
+				replicas = e.To
+				res.replicas = replicas
+				res.event = &e
+			}
+		}
+		seen = len(events)
+		results = append(results, res)
+	}
+	return results
+}
+
+// runSimulation implements -simulate: parse the trace, replay it and
+// print the scale timeline on stdout.
+func runSimulation(cfg *config) error {
+	f, err := os.Open(cfg.simulateFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	points, err := parseSimTrace(f)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return fmt.Errorf("%s: empty trace", cfg.simulateFile)
+	}
+
+	fmt.Printf("simulating %s/%s: %d points over %s, starting at %d replicas\n",
+		cfg.project, cfg.service, len(points), points[len(points)-1].at, cfg.minReplicas)
+	results := simulate(cfg, points)
+	scaled := 0
+	for _, r := range results {
+		line := fmt.Sprintf("%8s  cpu %5.1f  mem %5.1f", r.point.at, r.point.cpu, r.point.mem)
+		if r.point.metric != nil {
+			line += fmt.Sprintf("  metric %.2f", *r.point.metric)
+		}
+		if r.event != nil {
+			scaled++
+			fmt.Printf("%s  %d -> %d replicas  [%s]\n", line, r.event.From, r.event.To, r.event.ID)
+		} else {
+			fmt.Printf("%s  %d replicas\n", line, r.replicas)
+		}
+	}
+	fmt.Printf("%d scale events, final replicas %d\n", scaled, results[len(results)-1].replicas)
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSimTrace(t *testing.T) {
+	trace := `
+# warm-up, then a spike
+0s   20 30
+30s  85 40 12.5
+
+1m   25 30
+`
+	points, err := parseSimTrace(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("got %d points, want 3", len(points))
+	}
+	if points[1].at != 30*time.Second || points[1].cpu != 85 || points[1].mem != 40 {
+		t.Errorf("point 1 = %+v", points[1])
+	}
+	if points[1].metric == nil || *points[1].metric != 12.5 {
+		t.Errorf("point 1 metric = %v, want 12.5", points[1].metric)
+	}
+	if points[0].metric != nil {
+		t.Errorf("point 0 has a metric: %v", *points[0].metric)
+	}
+
+	for _, bad := range []string{
+		"0s 20",               // too few fields
+		"0s 20 30 1 extra",    // too many
+		"soon 20 30",          // bad offset
+		"0s high 30",          // bad cpu
+		"0s 20 30 full",       // bad metric
+		"1m 20 30\n30s 85 40", // offset goes backwards
+	} {
+		if _, err := parseSimTrace(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseSimTrace(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	cfg := &config{
+		project: "demo", service: "web",
+		minReplicas: 1, maxReplicas: 5,
+		cpuUp: 80, cpuDown: 20, memUp: 80, memDown: 20,
+		scaleUpChecks: 1, scaleDownChecks: 1,
+		scaleDownStep: 1,
+		cooldown:      time.Minute,
+	}
+	points, err := parseSimTrace(strings.NewReader(`
+0s   50 30
+10s  90 40
+20s  90 40
+3m   10 10
+6m   10 10
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := simulate(cfg, points)
+	want := []int{1, 2, 2, 1, 1} // up at 10s, cooldown holds 20s, down at 3m, floor at 6m
+	for i, r := range results {
+		if r.replicas != want[i] {
+			t.Errorf("point %d (%s): replicas = %d, want %d", i, r.point.at, r.replicas, want[i])
+		}
+	}
+	if results[1].event == nil || results[1].event.From != 1 || results[1].event.To != 2 {
+		t.Errorf("scale-up event = %+v", results[1].event)
+	}
+	if results[2].event != nil {
+		t.Errorf("scaled during cooldown: %+v", results[2].event)
+	}
+	if !cfg.dryRun {
+		t.Error("simulate did not force dry-run")
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statsSample is a normalized view of one container stats read,
+// independent of which Engine API schema produced it.
+type statsSample struct {
+	Read       time.Time
+	CPUPercent float64
+	MemUsage   uint64
+	MemLimit   uint64
+	// MemWorkingSet is usage minus the reclaimable page cache, the way
+	// `docker stats` accounts memory; MemPercent is derived from it.
+	// MemUsagePercent keeps the raw usage/limit ratio for operators who
+	// prefer it (-mem-accounting usage).
+	MemWorkingSet   uint64
+	MemPercent      float64
+	MemUsagePercent float64
+	// CPUEstimated is set when the schema did not tell us how many CPUs
+	// the container can use and we had to fall back to the host count.
+	CPUEstimated bool
+}
+
+// memPct returns the percentage for the configured accounting mode:
+// the working set by default, raw usage when mode is "usage".
+func (s *statsSample) memPct(mode string) float64 {
+	if mode == "usage" {
+		return s.MemUsagePercent
+	}
+	return s.MemPercent
+}
+
+// errIncompleteSample is returned for a stats read that has no usable
+// CPU delta, e.g. the very first sample after a container starts.
+var errIncompleteSample = errors.New("incomplete stats sample")
+
+// statsDecoder turns a raw Engine API stats payload into a statsSample.
+// Different daemon versions ship different schemas (cgroup v1 vs v2,
+// presence of online_cpus), so the decoder is chosen at negotiation time
+// rather than guessed per sample.
+type statsDecoder interface {
+	// Name identifies the schema family, for logging.
+	Name() string
+	Decode(r io.Reader) (*statsSample, error)
+}
+
+// decoderForVersion picks a decoder for a negotiated API version.
+// API 1.41+ daemons may run cgroup v2, where per-CPU usage is gone and
+// online_cpus is authoritative. Older daemons predate online_cpus and
+// report per-CPU usage instead.
+func decoderForVersion(apiVersion string) statsDecoder {
+	if versionLess(apiVersion, "1.25") {
+		return legacyStatsDecoder{}
+	}
+	return modernStatsDecoder{}
+}
+
+// engineStats covers the fields we use from both cgroup v1 and v2
+// payloads; absent fields simply decode to zero.
+type engineStats struct {
+	Read     time.Time `json:"read"`
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage  uint64   `json:"total_usage"`
+			PercpuUsage []uint64 `json:"percpu_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+		OnlineCPUs  uint32 `json:"online_cpus"`
+	} `json:"cpu_stats"`
+	PreCPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+		SystemUsage uint64 `json:"system_cpu_usage"`
+	} `json:"precpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+}
+
+// statsPool recycles decode targets between polls. A full StatsJSON
+// payload is a few KB of mostly-unused detail; decoding it into a fresh
+// struct for every container adds up fast when a poll covers 100+
+// containers. Reusing the structs (and the percpu_usage slice backing
+// them) keeps the steady-state allocation rate flat.
+var statsPool = sync.Pool{New: func() any { return new(engineStats) }}
+
+// decodeEngineStats stream-decodes the fields engineStats declares
+// straight off the response body — unknown keys are skipped without
+// ever buffering the whole payload — into a pooled struct.
+func decodeEngineStats(r io.Reader, raw *engineStats) error {
+	percpu := raw.CPUStats.CPUUsage.PercpuUsage[:0]
+	stats := raw.MemoryStats.Stats
+	clear(stats)
+	*raw = engineStats{}
+	raw.CPUStats.CPUUsage.PercpuUsage = percpu
+	raw.MemoryStats.Stats = stats
+	if err := json.NewDecoder(r).Decode(raw); err != nil {
+		return fmt.Errorf("decode stats: %w", err)
+	}
+	return nil
+}
+
+// modernStatsDecoder handles API 1.25+ payloads: online_cpus when the
+// daemon provides it, per-CPU usage as the cgroup v1 fallback, and the
+// host CPU count as a last resort so we never silently report 0%.
+type modernStatsDecoder struct{}
+
+func (modernStatsDecoder) Name() string { return "modern" }
+
+func (modernStatsDecoder) Decode(r io.Reader) (*statsSample, error) {
+	raw := statsPool.Get().(*engineStats)
+	defer statsPool.Put(raw)
+	if err := decodeEngineStats(r, raw); err != nil {
+		return nil, err
+	}
+	return buildSample(raw)
+}
+
+// legacyStatsDecoder handles pre-1.25 payloads, which never include
+// online_cpus; the per-CPU usage list is the only source of CPU count.
+type legacyStatsDecoder struct{}
+
+func (legacyStatsDecoder) Name() string { return "legacy" }
+
+func (legacyStatsDecoder) Decode(r io.Reader) (*statsSample, error) {
+	raw := statsPool.Get().(*engineStats)
+	defer statsPool.Put(raw)
+	if err := decodeEngineStats(r, raw); err != nil {
+		return nil, err
+	}
+	if raw.CPUStats.OnlineCPUs != 0 {
+		// A daemon this old should not send online_cpus; zero it so the
+		// per-CPU path is exercised consistently.
+		raw.CPUStats.OnlineCPUs = 0
+	}
+	return buildSample(raw)
+}
+
+func buildSample(raw *engineStats) (*statsSample, error) {
+	cpuDelta := int64(raw.CPUStats.CPUUsage.TotalUsage) - int64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	sysDelta := int64(raw.CPUStats.SystemUsage) - int64(raw.PreCPUStats.SystemUsage)
+	if raw.CPUStats.CPUUsage.TotalUsage == 0 && raw.PreCPUStats.CPUUsage.TotalUsage == 0 {
+		return nil, errIncompleteSample
+	}
+
+	s := &statsSample{Read: raw.Read}
+
+	cpus := float64(raw.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		if n := len(raw.CPUStats.CPUUsage.PercpuUsage); n > 0 {
+			cpus = float64(n)
+		} else {
+			// Neither online_cpus nor per-CPU usage (seen on some cgroup
+			// v2 backports): estimate with the host count rather than
+			// degrading to a silent 0%.
+			cpus = float64(runtime.NumCPU())
+			s.CPUEstimated = true
+		}
+	}
+	if cpuDelta > 0 && sysDelta > 0 {
+		s.CPUPercent = float64(cpuDelta) / float64(sysDelta) * cpus * 100.0
+	}
+
+	s.MemUsage = raw.MemoryStats.Usage
+	s.MemLimit = raw.MemoryStats.Limit
+	// Raw usage counts page cache the kernel would reclaim under
+	// pressure, which overstates how full a replica really is. Subtract
+	// it the way `docker stats` does: total_inactive_file under cgroup
+	// v1, inactive_file under v2.
+	s.MemWorkingSet = s.MemUsage
+	if v, ok := raw.MemoryStats.Stats["total_inactive_file"]; ok && v < s.MemUsage {
+		s.MemWorkingSet = s.MemUsage - v
+	} else if v, ok := raw.MemoryStats.Stats["inactive_file"]; ok && v < s.MemUsage {
+		s.MemWorkingSet = s.MemUsage - v
+	}
+	if s.MemLimit > 0 {
+		s.MemPercent = float64(s.MemWorkingSet) / float64(s.MemLimit) * 100.0
+		s.MemUsagePercent = float64(s.MemUsage) / float64(s.MemLimit) * 100.0
+	}
+	return s, nil
+}
+
+// versionLess compares two dotted API versions like "1.24" and "1.41".
+func versionLess(a, b string) bool {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		ai, _ := strconv.Atoi(as[i])
+		bi, _ := strconv.Atoi(bs[i])
+		if ai != bi {
+			return ai < bi
+		}
+	}
+	return len(as) < len(bs)
+}
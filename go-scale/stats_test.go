@@ -0,0 +1,227 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// cgroup v2 style payload (API 1.41+): online_cpus present, no
+// percpu_usage list.
+const cgroupV2Stats = `{
+  "read": "2024-01-01T10:00:01Z",
+  "cpu_stats": {
+    "cpu_usage": {"total_usage": 400000000},
+    "system_cpu_usage": 8000000000,
+    "online_cpus": 4
+  },
+  "precpu_stats": {
+    "cpu_usage": {"total_usage": 200000000},
+    "system_cpu_usage": 4000000000
+  },
+  "memory_stats": {"usage": 536870912, "limit": 1073741824}
+}`
+
+// cgroup v1 style payload from an older daemon: no online_cpus, CPU
+// count comes from the percpu_usage list.
+const cgroupV1Stats = `{
+  "read": "2024-01-01T10:00:01Z",
+  "cpu_stats": {
+    "cpu_usage": {
+      "total_usage": 400000000,
+      "percpu_usage": [100000000, 100000000, 100000000, 100000000]
+    },
+    "system_cpu_usage": 8000000000
+  },
+  "precpu_stats": {
+    "cpu_usage": {"total_usage": 200000000},
+    "system_cpu_usage": 4000000000
+  },
+  "memory_stats": {"usage": 268435456, "limit": 1073741824}
+}`
+
+// Degenerate payload with neither online_cpus nor percpu_usage; the
+// decoder should estimate with the host CPU count instead of returning
+// a silent 0%.
+const noCPUCountStats = `{
+  "read": "2024-01-01T10:00:01Z",
+  "cpu_stats": {
+    "cpu_usage": {"total_usage": 400000000},
+    "system_cpu_usage": 8000000000
+  },
+  "precpu_stats": {
+    "cpu_usage": {"total_usage": 200000000},
+    "system_cpu_usage": 4000000000
+  },
+  "memory_stats": {"usage": 536870912, "limit": 1073741824}
+}`
+
+// First read after container start: no previous sample to diff against.
+const firstReadStats = `{
+  "read": "2024-01-01T10:00:00Z",
+  "cpu_stats": {"cpu_usage": {"total_usage": 0}, "system_cpu_usage": 0},
+  "precpu_stats": {"cpu_usage": {"total_usage": 0}, "system_cpu_usage": 0},
+  "memory_stats": {"usage": 0, "limit": 0}
+}`
+
+func TestModernDecoderCgroupV2(t *testing.T) {
+	s, err := modernStatsDecoder{}.Decode(strings.NewReader(cgroupV2Stats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	// delta 200ms over 4s of system time across 4 CPUs = 20%.
+	if math.Abs(s.CPUPercent-20.0) > 0.01 {
+		t.Errorf("CPUPercent = %.2f, want 20.0", s.CPUPercent)
+	}
+	if s.CPUEstimated {
+		t.Error("CPUEstimated = true, want false (online_cpus present)")
+	}
+	if math.Abs(s.MemPercent-50.0) > 0.01 {
+		t.Errorf("MemPercent = %.2f, want 50.0", s.MemPercent)
+	}
+}
+
+func TestModernDecoderCgroupV1Fallback(t *testing.T) {
+	s, err := modernStatsDecoder{}.Decode(strings.NewReader(cgroupV1Stats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if math.Abs(s.CPUPercent-20.0) > 0.01 {
+		t.Errorf("CPUPercent = %.2f, want 20.0 (from percpu_usage)", s.CPUPercent)
+	}
+	if s.CPUEstimated {
+		t.Error("CPUEstimated = true, want false (percpu_usage present)")
+	}
+}
+
+func TestDecoderEstimatesWhenNoCPUCount(t *testing.T) {
+	s, err := modernStatsDecoder{}.Decode(strings.NewReader(noCPUCountStats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !s.CPUEstimated {
+		t.Error("CPUEstimated = false, want true")
+	}
+	if s.CPUPercent <= 0 {
+		t.Errorf("CPUPercent = %.2f, want > 0", s.CPUPercent)
+	}
+}
+
+// cgroup v2 memory stats: inactive_file is the reclaimable cache.
+const memV2Stats = `{
+  "read": "2024-01-01T10:00:01Z",
+  "cpu_stats": {
+    "cpu_usage": {"total_usage": 400000000},
+    "system_cpu_usage": 8000000000,
+    "online_cpus": 4
+  },
+  "precpu_stats": {
+    "cpu_usage": {"total_usage": 200000000},
+    "system_cpu_usage": 4000000000
+  },
+  "memory_stats": {
+    "usage": 536870912,
+    "limit": 1073741824,
+    "stats": {"inactive_file": 268435456}
+  }
+}`
+
+func TestMemoryWorkingSet(t *testing.T) {
+	s, err := modernStatsDecoder{}.Decode(strings.NewReader(memV2Stats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	// 512 MiB usage minus 256 MiB inactive_file over a 1 GiB limit.
+	if math.Abs(s.MemPercent-25.0) > 0.01 {
+		t.Errorf("MemPercent = %.2f, want 25.0 (working set)", s.MemPercent)
+	}
+	if math.Abs(s.MemUsagePercent-50.0) > 0.01 {
+		t.Errorf("MemUsagePercent = %.2f, want 50.0", s.MemUsagePercent)
+	}
+	if got := s.memPct("usage"); math.Abs(got-50.0) > 0.01 {
+		t.Errorf("memPct(usage) = %.2f, want 50.0", got)
+	}
+	if got := s.memPct("working-set"); math.Abs(got-25.0) > 0.01 {
+		t.Errorf("memPct(working-set) = %.2f, want 25.0", got)
+	}
+
+	// cgroup v1 spells it total_inactive_file.
+	v1 := strings.Replace(memV2Stats, "inactive_file", "total_inactive_file", 1)
+	s, err = modernStatsDecoder{}.Decode(strings.NewReader(v1))
+	if err != nil {
+		t.Fatalf("Decode v1: %v", err)
+	}
+	if math.Abs(s.MemPercent-25.0) > 0.01 {
+		t.Errorf("v1 MemPercent = %.2f, want 25.0", s.MemPercent)
+	}
+}
+
+// Without a stats map (or with inactive_file >= usage) the working set
+// falls back to raw usage rather than underflowing.
+func TestMemoryWorkingSetFallback(t *testing.T) {
+	s, err := modernStatsDecoder{}.Decode(strings.NewReader(cgroupV2Stats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if s.MemWorkingSet != s.MemUsage {
+		t.Errorf("MemWorkingSet = %d, want usage %d", s.MemWorkingSet, s.MemUsage)
+	}
+	big := strings.Replace(memV2Stats, "268435456}", "9999999999}", 1)
+	s, err = modernStatsDecoder{}.Decode(strings.NewReader(big))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if math.Abs(s.MemPercent-50.0) > 0.01 {
+		t.Errorf("MemPercent = %.2f, want 50.0 (oversized inactive_file ignored)", s.MemPercent)
+	}
+}
+
+func TestLegacyDecoderIgnoresOnlineCPUs(t *testing.T) {
+	s, err := legacyStatsDecoder{}.Decode(strings.NewReader(cgroupV1Stats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if math.Abs(s.CPUPercent-20.0) > 0.01 {
+		t.Errorf("CPUPercent = %.2f, want 20.0", s.CPUPercent)
+	}
+}
+
+func TestDecoderIncompleteSample(t *testing.T) {
+	_, err := modernStatsDecoder{}.Decode(strings.NewReader(firstReadStats))
+	if err != errIncompleteSample {
+		t.Fatalf("Decode error = %v, want errIncompleteSample", err)
+	}
+}
+
+// Decode targets are pooled; a payload missing a field must not pick
+// up a stale value from the previous container's sample.
+func TestDecoderPooledReuse(t *testing.T) {
+	d := modernStatsDecoder{}
+	if _, err := d.Decode(strings.NewReader(cgroupV1Stats)); err != nil {
+		t.Fatalf("Decode v1: %v", err)
+	}
+	s, err := d.Decode(strings.NewReader(noCPUCountStats))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if !s.CPUEstimated {
+		t.Error("CPUEstimated = false; stale percpu_usage leaked from pooled struct")
+	}
+}
+
+func TestDecoderForVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.24", "legacy"},
+		{"1.25", "modern"},
+		{"1.41", "modern"},
+		{"1.12", "legacy"},
+	}
+	for _, tt := range tests {
+		if got := decoderForVersion(tt.version).Name(); got != tt.want {
+			t.Errorf("decoderForVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+)
+
+// statsdClient sends gauges and counters to a StatsD endpoint over UDP
+// so existing Graphite dashboards can chart autoscaler behavior next to
+// app metrics. Sends are fire-and-forget: losing a sample is better
+// than stalling the poll loop.
+type statsdClient struct {
+	conn   net.Conn
+	prefix string
+}
+
+func newStatsdClient(addr, prefix string) (*statsdClient, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("statsd: %w", err)
+	}
+	if prefix == "" {
+		prefix = "goscale"
+	}
+	return &statsdClient{conn: conn, prefix: prefix}, nil
+}
+
+func (c *statsdClient) gauge(name string, value float64) {
+	if c == nil {
+		return
+	}
+	c.send(formatStatsd(c.prefix, name, fmt.Sprintf("%g", value), "g"))
+}
+
+func (c *statsdClient) count(name string, n int) {
+	if c == nil {
+		return
+	}
+	c.send(formatStatsd(c.prefix, name, fmt.Sprintf("%d", n), "c"))
+}
+
+func (c *statsdClient) send(msg string) {
+	if _, err := c.conn.Write([]byte(msg)); err != nil {
+		log.Printf("✗ statsd: %v", err)
+	}
+}
+
+// formatStatsd builds one StatsD datagram; metric name segments are
+// sanitized so service names with dots or colons don't split the key.
+func formatStatsd(prefix, name, value, kind string) string {
+	clean := strings.NewReplacer(":", "_", "|", "_", "@", "_").Replace(name)
+	return fmt.Sprintf("%s.%s:%s|%s", prefix, clean, value, kind)
+}
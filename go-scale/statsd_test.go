@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFormatStatsd(t *testing.T) {
+	tests := []struct {
+		name, value, kind, want string
+	}{
+		{"web.cpu", "42.5", "g", "goscale.web.cpu:42.5|g"},
+		{"web.scale.up", "1", "c", "goscale.web.scale.up:1|c"},
+		{"we:b.cpu", "1", "g", "goscale.we_b.cpu:1|g"},
+	}
+	for _, tt := range tests {
+		if got := formatStatsd("goscale", tt.name, tt.value, tt.kind); got != tt.want {
+			t.Errorf("formatStatsd(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatsdClientSends(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+
+	c, err := newStatsdClient(pc.LocalAddr().String(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.gauge("web.cpu", 55)
+
+	pc.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 256)
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(buf[:n]), "test.web.cpu:55|g"; got != want {
+		t.Errorf("datagram = %q, want %q", got, want)
+	}
+}
+
+func TestNilStatsdClientIsNoop(t *testing.T) {
+	var c *statsdClient
+	c.gauge("x", 1) // must not panic
+	c.count("y", 1)
+}
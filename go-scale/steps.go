@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stepTier is one rung of a step scaling policy: once utilization
+// crosses threshold, scale by add replicas (or straight to max).
+type stepTier struct {
+	threshold float64
+	add       int
+	toMax     bool
+}
+
+// stepPolicy is an ordered set of tiers. With no tiers configured the
+// autoscaler keeps its fixed +1 behavior; with tiers, a sharper breach
+// takes a bigger step, e.g. -step 70=1 -step 85=3 -step 95=max.
+type stepPolicy []stepTier
+
+func (p *stepPolicy) String() string {
+	var parts []string
+	for _, t := range *p {
+		step := strconv.Itoa(t.add)
+		if t.toMax {
+			step = "max"
+		}
+		parts = append(parts, fmt.Sprintf("%g=%s", t.threshold, step))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses one threshold=step pair; step is a replica count or "max".
+func (p *stepPolicy) Set(v string) error {
+	pct, step, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("expected threshold=step, got %q", v)
+	}
+	threshold, err := strconv.ParseFloat(pct, 64)
+	if err != nil || threshold <= 0 {
+		return fmt.Errorf("bad step threshold %q", pct)
+	}
+	tier := stepTier{threshold: threshold}
+	if step == "max" {
+		tier.toMax = true
+	} else {
+		tier.add, err = strconv.Atoi(step)
+		if err != nil || tier.add < 1 {
+			return fmt.Errorf("bad step size %q (want a positive count or \"max\")", step)
+		}
+	}
+	*p = append(*p, tier)
+	sort.Slice(*p, func(i, j int) bool { return (*p)[i].threshold < (*p)[j].threshold })
+	return nil
+}
+
+// stepSize is the replica delta for one scale action in the given
+// direction: the percentage of the current count when configured
+// (rounded up, at least one), otherwise the fixed step.
+func (cfg *config) stepSize(replicas int, up bool) int {
+	pct, fixed := cfg.scaleDownPct, cfg.scaleDownStep
+	if up {
+		pct, fixed = cfg.scaleUpPct, 1
+	}
+	if pct > 0 {
+		step := int(math.Ceil(float64(replicas) * pct / 100))
+		if step < 1 {
+			step = 1
+		}
+		return step
+	}
+	if fixed < 1 {
+		fixed = 1
+	}
+	return fixed
+}
+
+// effectiveDown returns the scale-down threshold to use against an up
+// threshold, enforcing the hysteresis band: the lower threshold is
+// pulled down to at least hysteresisPct percent below the upper one,
+// so thresholds set too close can't flip-flop a scale-up straight into
+// a scale-down.
+func (cfg *config) effectiveDown(up, down float64) float64 {
+	if cfg.hysteresisPct <= 0 {
+		return down
+	}
+	if ceiling := up * (1 - cfg.hysteresisPct/100); down > ceiling {
+		return ceiling
+	}
+	return down
+}
+
+// target picks the highest tier the utilization crosses and returns the
+// resulting replica target. ok is false when no tier matches (e.g. the
+// breach came from a custom metric, not CPU/mem).
+func (p stepPolicy) target(pct float64, current, max int) (int, bool) {
+	for i := len(p) - 1; i >= 0; i-- {
+		t := p[i]
+		if pct < t.threshold {
+			continue
+		}
+		if t.toMax {
+			return max, true
+		}
+		return current + t.add, true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package main
+
+import "testing"
+
+func TestStepPolicySet(t *testing.T) {
+	var p stepPolicy
+	for _, v := range []string{"95=max", "70=1", "85=3"} {
+		if err := p.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	if got := p.String(); got != "70=1,85=3,95=max" {
+		t.Errorf("String() = %q, want sorted tiers", got)
+	}
+
+	for _, bad := range []string{"70", "=1", "abc=1", "70=0", "70=-2", "70=lots", "0=1"} {
+		var q stepPolicy
+		if err := q.Set(bad); err == nil {
+			t.Errorf("Set(%q) accepted", bad)
+		}
+	}
+}
+
+func TestStepPolicyTarget(t *testing.T) {
+	var p stepPolicy
+	for _, v := range []string{"70=1", "85=3", "95=max"} {
+		if err := p.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+
+	tests := []struct {
+		pct     float64
+		current int
+		want    int
+		ok      bool
+	}{
+		{pct: 60, current: 2, want: 0, ok: false},
+		{pct: 70, current: 2, want: 3, ok: true},
+		{pct: 84.9, current: 2, want: 3, ok: true},
+		{pct: 85, current: 2, want: 5, ok: true},
+		{pct: 96, current: 2, want: 10, ok: true},
+	}
+	for _, tt := range tests {
+		got, ok := p.target(tt.pct, tt.current, 10)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("target(%.1f, %d) = %d, %v; want %d, %v", tt.pct, tt.current, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := stepPolicy(nil).target(99, 2, 10); ok {
+		t.Error("empty policy matched a tier")
+	}
+}
+
+func TestStepSize(t *testing.T) {
+	cfg := &config{scaleDownStep: 1}
+	if got := cfg.stepSize(10, true); got != 1 {
+		t.Errorf("default up step = %d", got)
+	}
+	if got := cfg.stepSize(10, false); got != 1 {
+		t.Errorf("default down step = %d", got)
+	}
+
+	cfg.scaleDownStep = 3
+	if got := cfg.stepSize(10, false); got != 3 {
+		t.Errorf("fixed down step = %d, want 3", got)
+	}
+
+	cfg.scaleUpPct, cfg.scaleDownPct = 50, 25
+	if got := cfg.stepSize(10, true); got != 5 {
+		t.Errorf("50%% of 10 up = %d, want 5", got)
+	}
+	if got := cfg.stepSize(10, false); got != 3 {
+		t.Errorf("25%% of 10 down = %d, want ceil 3", got)
+	}
+	// Percentages never round to zero.
+	if got := cfg.stepSize(1, false); got != 1 {
+		t.Errorf("25%% of 1 down = %d, want 1", got)
+	}
+}
+
+func TestEffectiveDown(t *testing.T) {
+	cfg := &config{}
+	if got := cfg.effectiveDown(80, 75); got != 75 {
+		t.Errorf("no hysteresis: down = %g, want 75", got)
+	}
+
+	cfg.hysteresisPct = 25
+	// 75 is inside the band below 80; pull it down to 80*0.75 = 60.
+	if got := cfg.effectiveDown(80, 75); got != 60 {
+		t.Errorf("down pulled to %g, want 60", got)
+	}
+	// 20 is already well clear of the band.
+	if got := cfg.effectiveDown(80, 20); got != 20 {
+		t.Errorf("down = %g, want 20 untouched", got)
+	}
+}
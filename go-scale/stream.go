@@ -0,0 +1,52 @@
+package main
+
+import (
+	"sync"
+)
+
+// streamMsg is one message on the live event stream: a scale event
+// outcome or a decision queued for approval.
+type streamMsg struct {
+	kind string // SSE event name: "scale" or "pending"
+	data any    // JSON-encoded into the data field
+}
+
+// streamBroker fans events out to SSE subscribers. It has its own lock
+// so publishers already holding the autoscaler mutex (recordEvent) can
+// publish without a deadlock.
+type streamBroker struct {
+	mu   sync.Mutex
+	subs map[chan streamMsg]struct{}
+}
+
+// subscribe registers a new subscriber channel. The buffer absorbs
+// bursts; a subscriber that falls further behind loses messages rather
+// than blocking the scaling loop.
+func (b *streamBroker) subscribe() chan streamMsg {
+	ch := make(chan streamMsg, 16)
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = map[chan streamMsg]struct{}{}
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *streamBroker) unsubscribe(ch chan streamMsg) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+}
+
+// publish delivers a message to every subscriber without blocking.
+func (b *streamBroker) publish(kind string, data any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- streamMsg{kind: kind, data: data}:
+		default: // subscriber too slow, drop
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+func TestStreamBroker(t *testing.T) {
+	var b streamBroker
+
+	b.publish("scale", scaleEvent{ID: "lost"}) // no subscribers: no-op
+
+	one := b.subscribe()
+	two := b.subscribe()
+	b.publish("scale", scaleEvent{ID: "aaaa"})
+	for _, ch := range []chan streamMsg{one, two} {
+		select {
+		case m := <-ch:
+			if m.kind != "scale" || m.data.(scaleEvent).ID != "aaaa" {
+				t.Errorf("got %+v", m)
+			}
+		default:
+			t.Fatal("subscriber missed the message")
+		}
+	}
+
+	b.unsubscribe(two)
+	b.publish("pending", scaleEvent{ID: "bbbb"})
+	select {
+	case m := <-two:
+		t.Errorf("unsubscribed channel got %+v", m)
+	default:
+	}
+	if m := <-one; m.kind != "pending" {
+		t.Errorf("kind = %q, want pending", m.kind)
+	}
+
+	// A subscriber that never drains drops messages instead of blocking.
+	for i := 0; i < cap(one)+5; i++ {
+		b.publish("scale", scaleEvent{})
+	}
+	if len(one) != cap(one) {
+		t.Errorf("backlog = %d, want the full buffer %d", len(one), cap(one))
+	}
+}
+
+func TestRecordEventPublishes(t *testing.T) {
+	a := &autoscaler{cfg: &config{}}
+	ch := a.stream.subscribe()
+	a.recordEvent(scaleEvent{ID: "aaaa", Outcome: "applied"})
+	select {
+	case m := <-ch:
+		if m.kind != "scale" || m.data.(scaleEvent).ID != "aaaa" {
+			t.Errorf("got %+v", m)
+		}
+	default:
+		t.Fatal("recordEvent did not publish to the stream")
+	}
+}
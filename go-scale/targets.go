@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/iamdanielv/utils/internal/lifecycle"
+	"github.com/iamdanielv/utils/pkg/scaler"
+)
+
+// targetSet is the live list of scale targets. The control API can add
+// and remove entries at runtime, so readers take a snapshot through
+// all() instead of holding the slice.
+type targetSet struct {
+	mu      sync.Mutex
+	scalers []*autoscaler
+}
+
+func (ts *targetSet) all() []*autoscaler {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]*autoscaler(nil), ts.scalers...)
+}
+
+// has reports whether a project/service pair is already scaled.
+func (ts *targetSet) has(project, service string) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for _, as := range ts.scalers {
+		if as.cfg.project == project && as.cfg.service == service {
+			return true
+		}
+	}
+	return false
+}
+
+func (ts *targetSet) add(as *autoscaler) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.scalers = append(ts.scalers, as)
+}
+
+// remove takes a target out of the set and returns it so the caller
+// can stop its goroutines; nil means no such target. The last target
+// cannot be removed — a daemon scaling nothing is a misconfiguration,
+// not a state to run in.
+func (ts *targetSet) remove(project, service string) (*autoscaler, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for i, as := range ts.scalers {
+		if as.cfg.project != project || as.cfg.service != service {
+			continue
+		}
+		if len(ts.scalers) == 1 {
+			return nil, fmt.Errorf("cannot remove the last target")
+		}
+		ts.scalers = append(ts.scalers[:i], ts.scalers[i+1:]...)
+		return as, nil
+	}
+	return nil, nil
+}
+
+// targetDeps carries the per-process dependencies every target shares:
+// one docker or nomad client, one statsd socket, one notifier set, one
+// stats cache.
+type targetDeps struct {
+	docker *dockerClient
+	nomad  *nomadClient
+	statsd *statsdClient
+	notify *notifierSet
+	cache  *statsCache
+}
+
+// buildTarget wires one project/service pair into an autoscaler. Each
+// target runs on its own config copy so thresholds are shared but
+// project, service and state files are not; with suffix set, the state
+// file names get a .project-service suffix so targets don't collide.
+func buildTarget(grp *lifecycle.Group, cfg *config, t target, deps targetDeps, suffix bool) (*autoscaler, error) {
+	tcfg := *cfg
+	tcfg.project, tcfg.service = t.project, t.service
+	if suffix {
+		sfx := "." + t.project + "-" + t.service
+		if tcfg.historyFile != "" {
+			tcfg.historyFile += sfx
+		}
+		if tcfg.historyExport != "" {
+			tcfg.historyExport += sfx
+		}
+	}
+
+	as := newAutoscaler(&tcfg, deps.docker)
+	as.nomad = deps.nomad
+	as.statsd = deps.statsd
+	as.notify = deps.notify
+	as.statsCache = deps.cache
+	if tcfg.injectSpec != "" {
+		inj, err := parseInjection(tcfg.injectSpec, tcfg.injectPolls)
+		if err != nil {
+			return nil, fmt.Errorf("-inject-metrics: %w", err)
+		}
+		as.setInjection(inj)
+	}
+	if tcfg.historyFile != "" {
+		h, err := newLoadHistory(tcfg.historyFile)
+		if err != nil {
+			return nil, fmt.Errorf("history file: %w", err)
+		}
+		as.history = h
+		grp.Defer("history save "+t.project+"/"+t.service, func(context.Context) error { h.save(); return nil })
+	}
+	d, err := newDrainer(&tcfg, deps.docker)
+	if err != nil {
+		return nil, err
+	}
+	as.drain = d
+	if tcfg.historyExport != "" {
+		f, err := os.OpenFile(tcfg.historyExport, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("-history-export: %w", err)
+		}
+		as.exportW = f
+		grp.Defer("history export close "+t.project+"/"+t.service, func(context.Context) error { return f.Close() })
+	}
+	return as, nil
+}
+
+// startTarget launches the target's poll loop (and docker event watch)
+// under its own cancel, so a runtime remove stops this target without
+// touching the rest of the group.
+func startTarget(grp *lifecycle.Group, as *autoscaler, watchEvents bool) {
+	ctx, cancel := context.WithCancel(grp.Context())
+	as.stop = cancel
+	grp.Go(func(context.Context) error { as.run(ctx); return nil })
+	if watchEvents {
+		grp.Go(func(context.Context) error { as.watchDockerEvents(ctx); return nil })
+	}
+}
+
+// validateTarget proves a runtime-added target exists before its poll
+// loop starts: the nomad group must resolve, or the service must be in
+// the project's compose config.
+func validateTarget(cfg *config, nomad *nomadClient) error {
+	if nomad != nil {
+		_, _, err := nomad.GroupCount(context.Background(), cfg.project, cfg.service)
+		return err
+	}
+	bin, args := scaler.ComposeCommand(append(composeArgs(cfg), "config", "--services"))
+	out, err := exec.Command(bin, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("compose config for %s: %v: %s", cfg.project, err, strings.TrimSpace(string(out)))
+	}
+	for _, s := range strings.Fields(string(out)) {
+		if s == cfg.service {
+			return nil
+		}
+	}
+	return fmt.Errorf("service %q is not in the compose config for %s (missing -profile?)", cfg.service, cfg.project)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iamdanielv/utils/internal/format"
+	"golang.org/x/term"
+)
+
+// ANSI palette and screen controls, same as centurion's TUI.
+const (
+	cReset  = "\x1b[0m"
+	cBold   = "\x1b[1m"
+	cRed    = "\x1b[31m"
+	cGreen  = "\x1b[32m"
+	cYellow = "\x1b[33m"
+	cBlue   = "\x1b[34m"
+	cGray   = "\x1b[30;1m"
+
+	altScreen  = "\x1b[?1049h"
+	mainScreen = "\x1b[?1049l"
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+	clearAll   = "\x1b[2J\x1b[H"
+)
+
+// tuiSample is one poll's averages, published on the event stream for
+// the dashboard (and any SSE subscriber that wants live utilization).
+type tuiSample struct {
+	Time     time.Time     `json:"time"`
+	CPU      float64       `json:"cpu"`
+	Mem      float64       `json:"mem"`
+	Replicas int           `json:"replicas"`
+	Cooldown time.Duration `json:"cooldown"` // time left before the next scale action may run
+	Paused   bool          `json:"paused"`
+}
+
+var sparkRunes = []rune("▁▂▃▄▅▆▇█")
+
+// spark renders percentage values (0-100) as a sparkline of at most
+// width runes, newest on the right.
+func spark(vals []float64, width int) string {
+	if width < 1 {
+		return ""
+	}
+	if len(vals) > width {
+		vals = vals[len(vals)-width:]
+	}
+	var b strings.Builder
+	for _, v := range vals {
+		idx := int(v / 100 * float64(len(sparkRunes)))
+		if idx < 0 {
+			idx = 0
+		}
+		if idx >= len(sparkRunes) {
+			idx = len(sparkRunes) - 1
+		}
+		b.WriteRune(sparkRunes[idx])
+	}
+	return b.String()
+}
+
+// sampleWindow bounds the rolling utilization window the sparklines
+// draw from; at a 30s interval this is two hours of history.
+const sampleWindow = 240
+
+// dashboard is the -tui state: a rolling sample window plus the
+// autoscaler handle for events and controls.
+type dashboard struct {
+	as      *autoscaler
+	samples []tuiSample
+
+	width, height int
+}
+
+// runDashboard owns the terminal until the context is cancelled or the
+// operator quits, which stops the whole process.
+func runDashboard(ctx context.Context, as *autoscaler) error {
+	fd := int(os.Stdin.Fd())
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("raw mode: %w", err)
+	}
+	defer term.Restore(fd, old)
+
+	out := os.Stdout
+	fmt.Fprint(out, altScreen+hideCursor)
+	defer fmt.Fprint(out, showCursor+mainScreen)
+
+	keys := make(chan []byte)
+	go func() {
+		for {
+			buf := make([]byte, 16)
+			n, err := os.Stdin.Read(buf)
+			if err != nil || n == 0 {
+				close(keys)
+				return
+			}
+			keys <- buf[:n]
+		}
+	}()
+
+	d := &dashboard{as: as}
+	ch := as.stream.subscribe()
+	defer as.stream.unsubscribe(ch)
+
+	// The half-second tick keeps the cooldown countdown moving between
+	// polls.
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		d.width, d.height, _ = term.GetSize(fd)
+		if d.width <= 0 {
+			d.width, d.height = 80, 24
+		}
+		d.draw(out)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch string(key) {
+			case "q", "\x03":
+				return nil
+			case "p":
+				as.togglePaused()
+			case "f":
+				as.requestEvaluation()
+			}
+		case m := <-ch:
+			if s, ok := m.data.(tuiSample); ok {
+				d.samples = append(d.samples, s)
+				if len(d.samples) > sampleWindow {
+					d.samples = d.samples[len(d.samples)-sampleWindow:]
+				}
+			}
+			// Scale events and pending decisions are read straight from
+			// the autoscaler's ring on the next draw.
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *dashboard) draw(out *os.File) {
+	var b strings.Builder
+	b.WriteString(clearAll)
+
+	var cur tuiSample
+	if n := len(d.samples); n > 0 {
+		cur = d.samples[n-1]
+	}
+
+	title := fmt.Sprintf(" go-scale — %s/%s — %d replicas", d.as.cfg.project, d.as.cfg.service, cur.Replicas)
+	bar := cBlue
+	if d.as.isPaused() {
+		title += " — PAUSED"
+		bar = cYellow
+	}
+	fmt.Fprintf(&b, "%s%s%-*s%s\r\n\r\n", cBold, bar, d.width, format.Truncate(title, d.width), cReset)
+
+	var cpus, mems []float64
+	for _, s := range d.samples {
+		cpus = append(cpus, s.CPU)
+		mems = append(mems, s.Mem)
+	}
+	sparkWidth := d.width - 14
+	fmt.Fprintf(&b, " cpu %5.1f%%  %s\r\n", cur.CPU, spark(cpus, sparkWidth))
+	fmt.Fprintf(&b, " mem %5.1f%%  %s\r\n\r\n", cur.Mem, spark(mems, sparkWidth))
+
+	if cur.Cooldown > 0 {
+		remaining := cur.Cooldown - time.Since(cur.Time)
+		if remaining < 0 {
+			remaining = 0
+		}
+		fmt.Fprintf(&b, " cooldown: %s%s remaining%s\r\n", cYellow, format.Duration(remaining), cReset)
+	} else {
+		fmt.Fprintf(&b, " cooldown: %sready%s\r\n", cGreen, cReset)
+	}
+
+	if dec, ok := d.as.currentDecision(); ok {
+		fmt.Fprintf(&b, " %sawaiting approval: %d -> %d [%s]%s\r\n",
+			cYellow, dec.Event.From, dec.Event.To, dec.Event.ID, cReset)
+	}
+
+	fmt.Fprintf(&b, "\r\n%s recent events%s\r\n", cBold, cReset)
+	events := d.as.recentEvents()
+	rows := d.height - 11
+	if rows < 1 {
+		rows = 1
+	}
+	if len(events) > rows {
+		events = events[len(events)-rows:]
+	}
+	for _, e := range events {
+		icon := cGreen + "✓" + cReset
+		switch e.Outcome {
+		case "failed", "hook-failed":
+			icon = cRed + "✗" + cReset
+		case "vetoed", "rejected":
+			icon = cYellow + "⊘" + cReset
+		}
+		line := fmt.Sprintf("  %s %s [%s] %d -> %d %s (cpu %.1f%%, mem %.1f%%)",
+			e.Time.Format("15:04:05"), icon, e.ID, e.From, e.To, e.Outcome, e.CPU, e.Mem)
+		fmt.Fprintf(&b, "%s\r\n", line)
+	}
+
+	fmt.Fprintf(&b, "\r\n%s q quit · p pause/resume · f force evaluation%s\r\n", cGray, cReset)
+	out.WriteString(b.String())
+}
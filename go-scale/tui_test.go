@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestSpark(t *testing.T) {
+	got := spark([]float64{0, 12.5, 50, 99, 100, -5, 200}, 80)
+	if got != "▁▂▅██▁█" {
+		t.Errorf("spark = %q", got)
+	}
+	// Wider than the window: only the newest values fit.
+	if got := spark([]float64{0, 0, 0, 100}, 2); got != "▁█" {
+		t.Errorf("truncated spark = %q", got)
+	}
+	if got := spark([]float64{50}, 0); got != "" {
+		t.Errorf("zero width spark = %q", got)
+	}
+}
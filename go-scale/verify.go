@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// Post-scale verification. A successful scale command means compose or
+// nomad accepted the request, not that the replicas came up: an image
+// pull can fail, a new container can exit on start or flunk its
+// healthcheck. With -verify-timeout every applied event is re-checked
+// until the live count matches the target; an event that never gets
+// there is recorded as "unverified" and can optionally be rolled back.
+
+// verifyStep is how often the live count is re-checked while waiting.
+const verifyStep = 2 * time.Second
+
+// liveReplicas counts the replicas that are up and passing their
+// healthcheck, through whichever backend is configured.
+func (a *autoscaler) liveReplicas(ctx context.Context) (int, error) {
+	if a.nomad != nil {
+		allocs, err := a.nomad.RunningAllocs(ctx, a.cfg.project, a.cfg.service)
+		if err != nil {
+			return 0, err
+		}
+		return len(allocs), nil
+	}
+	containers, err := a.docker.getServiceContainers(ctx, a.cfg, a.cfg.service)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, c := range containers {
+		if c.State == "running" && !strings.Contains(c.Status, "unhealthy") {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// verifyScale waits for the live replica count to reach the event's
+// target. Like the scale command itself it runs on the poll goroutine;
+// the timeout bounds how long the loop is held up.
+func (a *autoscaler) verifyScale(ctx context.Context, event scaleEvent) bool {
+	deadline := time.Now().Add(a.cfg.verifyTimeout)
+	for {
+		n, err := a.liveReplicas(ctx)
+		if err != nil {
+			log.Printf("✗ [%s] verify: %v", event.ID, err)
+		} else if n == event.To {
+			if a.cfg.verbose {
+				log.Printf("i [%s] verified %d replicas up and healthy", event.ID, n)
+			}
+			return true
+		}
+		if time.Now().After(deadline) {
+			if err == nil {
+				log.Printf("✗ [%s] verify timeout: %d/%d replicas up and healthy", event.ID, n, event.To)
+			}
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(verifyStep):
+		}
+	}
+}
+
+// rollbackScale returns the service to its pre-event count after a
+// failed verification. The rollback is its own event so both attempts
+// show up in the history.
+func (a *autoscaler) rollbackScale(ctx context.Context, event scaleEvent) {
+	back := scaleEvent{ID: newEventID(), Time: a.now(), From: event.To, To: event.From, CPU: event.CPU, Mem: event.Mem}
+	log.Printf("[%s] rolling back %s to %d after unverified event %s", back.ID, a.cfg.service, back.To, event.ID)
+	if err := a.applyScale(ctx, back); err != nil {
+		log.Printf("✗ [%s] rollback: %v", back.ID, err)
+		back.Outcome = "failed"
+	} else {
+		back.Outcome = "applied"
+		a.expectReplicas = back.To
+	}
+	a.recordEvent(back)
+}
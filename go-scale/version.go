@@ -0,0 +1,19 @@
+package main
+
+import "fmt"
+
+// Build identification, stamped by release builds:
+//
+//	go build -ldflags "-X main.version=v1.2.3 -X main.commit=abc1234 -X main.buildDate=2024-03-01"
+//
+// A plain `go build` leaves the dev defaults, which is itself useful
+// information in a support request.
+var (
+	version   = "dev"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
+
+func versionString() string {
+	return fmt.Sprintf("go-scale %s (commit %s, built %s)", version, commit, buildDate)
+}
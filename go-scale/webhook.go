@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// decisionWebhook lets an external endpoint review every proposed scale
+// action — approve it, veto it, or modify the target count — so
+// organization-specific approval logic can hook in without forking.
+type decisionWebhook struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+func newDecisionWebhook(url string, timeout time.Duration) *decisionWebhook {
+	return &decisionWebhook{url: url, timeout: timeout, client: &http.Client{Timeout: timeout}}
+}
+
+// decisionRequest is the POST body describing a proposed scale action.
+type decisionRequest struct {
+	EventID  string  `json:"event_id"`
+	Project  string  `json:"project"`
+	Service  string  `json:"service"`
+	From     int     `json:"from"`
+	To       int     `json:"to"`
+	CPU      float64 `json:"cpu"`
+	Mem      float64 `json:"mem"`
+	DecideAt string  `json:"decide_at"`
+}
+
+// decisionResponse is the endpoint's verdict. Action is "approve",
+// "veto" or "modify"; Target only matters for modify.
+type decisionResponse struct {
+	Action string `json:"action"`
+	Target int    `json:"target"`
+	Reason string `json:"reason"`
+}
+
+// review sends the proposed action for approval. It fails open: if the
+// endpoint is down, slow, or talks nonsense, the proposal stands —
+// the webhook is an extra opinion, not a required dependency.
+func (w *decisionWebhook) review(ctx context.Context, req decisionRequest) (target int, allowed bool) {
+	verdict, err := w.post(ctx, req)
+	if err != nil {
+		log.Printf("✗ [%s] decision webhook: %v (proceeding)", req.EventID, err)
+		return req.To, true
+	}
+	switch verdict.Action {
+	case "veto":
+		reason := verdict.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		log.Printf("i [%s] scale %d -> %d vetoed by webhook: %s", req.EventID, req.From, req.To, reason)
+		return req.To, false
+	case "modify":
+		log.Printf("i [%s] webhook modified target %d -> %d", req.EventID, req.To, verdict.Target)
+		return verdict.Target, true
+	case "approve", "":
+		return req.To, true
+	default:
+		log.Printf("✗ [%s] decision webhook: unknown action %q (proceeding)", req.EventID, verdict.Action)
+		return req.To, true
+	}
+}
+
+func (w *decisionWebhook) post(ctx context.Context, req decisionRequest) (*decisionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	var verdict decisionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, err
+	}
+	return &verdict, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDecisionWebhookReview(t *testing.T) {
+	verdict := decisionResponse{}
+	var got decisionRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		json.NewEncoder(w).Encode(verdict)
+	}))
+	defer srv.Close()
+
+	w := newDecisionWebhook(srv.URL, 2*time.Second)
+	ctx := context.Background()
+	proposal := decisionRequest{Project: "shop", Service: "web", From: 2, To: 4, CPU: 91}
+
+	verdict = decisionResponse{Action: "approve"}
+	if target, ok := w.review(ctx, proposal); !ok || target != 4 {
+		t.Errorf("approve: %d, %v", target, ok)
+	}
+	if got.Service != "web" || got.From != 2 || got.To != 4 {
+		t.Errorf("payload = %+v", got)
+	}
+
+	verdict = decisionResponse{Action: "veto", Reason: "deploy in progress"}
+	if _, ok := w.review(ctx, proposal); ok {
+		t.Error("veto not honored")
+	}
+
+	verdict = decisionResponse{Action: "modify", Target: 3}
+	if target, ok := w.review(ctx, proposal); !ok || target != 3 {
+		t.Errorf("modify: %d, %v", target, ok)
+	}
+
+	verdict = decisionResponse{Action: "explode"}
+	if target, ok := w.review(ctx, proposal); !ok || target != 4 {
+		t.Errorf("unknown action should fail open: %d, %v", target, ok)
+	}
+}
+
+func TestDecisionWebhookFailsOpen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	w := newDecisionWebhook(srv.URL, time.Second)
+	if target, ok := w.review(context.Background(), decisionRequest{From: 2, To: 3}); !ok || target != 3 {
+		t.Errorf("5xx should fail open: %d, %v", target, ok)
+	}
+
+	w = newDecisionWebhook("http://127.0.0.1:1", time.Second)
+	if _, ok := w.review(context.Background(), decisionRequest{From: 2, To: 3}); !ok {
+		t.Error("unreachable endpoint should fail open")
+	}
+}
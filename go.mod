@@ -0,0 +1,7 @@
+module github.com/iamdanielv/utils
+
+go 1.21.6
+
+require golang.org/x/term v0.16.0
+
+require golang.org/x/sys v0.16.0 // indirect
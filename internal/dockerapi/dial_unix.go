@@ -0,0 +1,25 @@
+//go:build !windows
+
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const defaultHost = "unix:///var/run/docker.sock"
+
+// dialer maps a DOCKER_HOST to a transport dial function. Unix hosts
+// speak over the local socket only.
+func dialer(host string) (func(context.Context, string, string) (net.Conn, error), error) {
+	if !strings.HasPrefix(host, "unix://") {
+		return nil, fmt.Errorf("unsupported DOCKER_HOST %q (only unix:// sockets)", host)
+	}
+	socket := strings.TrimPrefix(host, "unix://")
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socket)
+	}, nil
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package dockerapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultHost = "npipe:////./pipe/docker_engine"
+
+// dialer maps a DOCKER_HOST to a transport dial function. Windows
+// daemons listen on a named pipe, which CreateFile opens like a
+// regular duplex file — enough for the straight HTTP this client
+// speaks, without pulling in a pipe library.
+func dialer(host string) (func(context.Context, string, string) (net.Conn, error), error) {
+	if !strings.HasPrefix(host, "npipe://") {
+		return nil, fmt.Errorf("unsupported DOCKER_HOST %q (only npipe:// pipes)", host)
+	}
+	// npipe:////./pipe/docker_engine -> \\.\pipe\docker_engine
+	path := strings.ReplaceAll(strings.TrimPrefix(host, "npipe://"), "/", `\`)
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		f, err := os.OpenFile(path, os.O_RDWR, 0)
+		if err != nil {
+			return nil, err
+		}
+		return pipeConn{f}, nil
+	}, nil
+}
+
+// pipeConn adapts the opened pipe to net.Conn for http.Transport.
+// Deadlines are no-ops; the http.Client timeout still applies, since
+// cancellation closes the connection.
+type pipeConn struct {
+	*os.File
+}
+
+func (c pipeConn) LocalAddr() net.Addr              { return pipeAddr(c.Name()) }
+func (c pipeConn) RemoteAddr() net.Addr             { return pipeAddr(c.Name()) }
+func (c pipeConn) SetDeadline(time.Time) error      { return nil }
+func (c pipeConn) SetReadDeadline(time.Time) error  { return nil }
+func (c pipeConn) SetWriteDeadline(time.Time) error { return nil }
+
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "npipe" }
+func (a pipeAddr) String() string  { return string(a) }
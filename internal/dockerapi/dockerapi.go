@@ -0,0 +1,241 @@
+// Package dockerapi is the minimal Engine API client shared by the
+// tools in this repo. It talks straight HTTP over the local socket:
+// the tools need a handful of endpoints and small static binaries, not
+// the full SDK.
+package dockerapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Client is a connection to the local Docker daemon.
+type Client struct {
+	http   *http.Client
+	stream *http.Client // no overall timeout, for long-lived streams
+	host   string
+	// APIVersion is filled by Negotiate.
+	APIVersion string
+}
+
+// New builds a client for DOCKER_HOST or the platform's default local
+// endpoint: the unix socket, or the npipe named pipe on Windows. The
+// per-platform dialer decides which schemes it accepts.
+func New() (*Client, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = defaultHost
+	}
+	dial, err := dialer(host)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		DialContext:           dial,
+		ResponseHeaderTimeout: 30 * time.Second,
+	}
+	// The overall client timeout covers reading the whole body, which
+	// would cut off endpoints that keep the response open (/events), so
+	// those get a second client on the same transport with only the
+	// dial and response-header limits.
+	return &Client{
+		http:   &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		stream: &http.Client{Transport: transport},
+		host:   host,
+	}, nil
+}
+
+// Negotiate asks the daemon which API version it speaks.
+func (c *Client) Negotiate(ctx context.Context) error {
+	var v struct {
+		APIVersion string `json:"ApiVersion"`
+	}
+	if err := c.GetJSON(ctx, "/version", &v); err != nil {
+		return err
+	}
+	if v.APIVersion == "" {
+		return fmt.Errorf("daemon did not report an API version")
+	}
+	c.APIVersion = v.APIVersion
+	return nil
+}
+
+// Container is one row of /containers/json.
+type Container struct {
+	ID      string            `json:"Id"`
+	Names   []string          `json:"Names"`
+	Image   string            `json:"Image"`
+	ImageID string            `json:"ImageID"`
+	State   string            `json:"State"`
+	Status  string            `json:"Status"`
+	Labels  map[string]string `json:"Labels"`
+	Ports   []Port            `json:"Ports"`
+	Created int64             `json:"Created"`
+}
+
+// Port is a published port mapping.
+type Port struct {
+	IP          string `json:"IP"`
+	PrivatePort int    `json:"PrivatePort"`
+	PublicPort  int    `json:"PublicPort"`
+	Type        string `json:"Type"`
+}
+
+// Name returns the primary container name without the leading slash.
+func (c Container) Name() string {
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return ShortID(c.ID)
+}
+
+// ListContainers lists containers matching the given filters; with
+// all set, exited/created containers are included.
+func (c *Client) ListContainers(ctx context.Context, filters map[string][]string, all bool) ([]Container, error) {
+	path := "/containers/json"
+	params := url.Values{}
+	if all {
+		params.Set("all", "1")
+	}
+	if len(filters) > 0 {
+		raw, err := json.Marshal(filters)
+		if err != nil {
+			return nil, err
+		}
+		params.Set("filters", string(raw))
+	}
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var out []Container
+	if err := c.GetJSON(ctx, path, &out); err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	return out, nil
+}
+
+// ContainerDetail is the subset of /containers/{id}/json the tools
+// inspect.
+type ContainerDetail struct {
+	ID           string `json:"Id"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		Status     string `json:"Status"`
+		Restarting bool   `json:"Restarting"`
+		OOMKilled  bool   `json:"OOMKilled"`
+		ExitCode   int    `json:"ExitCode"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+		} `json:"Health"`
+	} `json:"State"`
+	HostConfig struct {
+		Memory   int64 `json:"Memory"`
+		NanoCPUs int64 `json:"NanoCpus"`
+	} `json:"HostConfig"`
+}
+
+// InspectContainer fetches detailed state for one container.
+func (c *Client) InspectContainer(ctx context.Context, id string) (*ContainerDetail, error) {
+	var out ContainerDetail
+	if err := c.GetJSON(ctx, "/containers/"+id+"/json", &out); err != nil {
+		return nil, fmt.Errorf("inspect %s: %w", ShortID(id), err)
+	}
+	return &out, nil
+}
+
+// ImageID resolves an image reference (tag or digest) to the image ID
+// it currently points at in the local store.
+func (c *Client) ImageID(ctx context.Context, ref string) (string, error) {
+	var out struct {
+		ID string `json:"Id"`
+	}
+	if err := c.GetJSON(ctx, "/images/"+url.PathEscape(ref)+"/json", &out); err != nil {
+		return "", fmt.Errorf("inspect image %s: %w", ref, err)
+	}
+	return out.ID, nil
+}
+
+// Get performs a GET request and returns the body on 200. The 30s
+// client timeout covers the full body read, so this suits one-shot
+// endpoints only.
+func (c *Client) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	return c.get(ctx, c.http, path)
+}
+
+// GetStream is Get without the overall timeout, for endpoints that
+// keep the response open indefinitely (/events). Dial and
+// response-header timeouts still apply, and cancelling ctx closes the
+// stream.
+func (c *Client) GetStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	return c.get(ctx, c.stream, path)
+}
+
+func (c *Client) get(ctx context.Context, client *http.Client, path string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://docker"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
+
+// Post performs a POST with an empty body, as the Engine API uses for
+// container actions, and succeeds on any 2xx status.
+func (c *Client) Post(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://docker"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Kill sends a signal to a container.
+func (c *Client) Kill(ctx context.Context, id, signal string) error {
+	if err := c.Post(ctx, "/containers/"+id+"/kill?signal="+url.QueryEscape(signal)); err != nil {
+		return fmt.Errorf("kill %s: %w", ShortID(id), err)
+	}
+	return nil
+}
+
+// GetJSON performs a GET request and decodes the JSON response.
+func (c *Client) GetJSON(ctx context.Context, path string, out any) error {
+	body, err := c.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(out)
+}
+
+// ShortID abbreviates a container ID for display.
+func ShortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,91 @@
+// Package errcode classifies errors from the tools in this repo into a
+// small set of categories with stable process exit codes, so wrapper
+// scripts can tell "Docker is down" apart from "bad flag" without
+// parsing messages.
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Kind is an error category. The numeric values are the documented
+// exit codes and must not be reordered.
+type Kind int
+
+const (
+	// OK (0) and Generic (1) exist for completeness; tools rarely
+	// construct them explicitly.
+	OK      Kind = 0
+	Generic Kind = 1
+	// Config: invalid flags, thresholds, or config file contents.
+	Config Kind = 2
+	// Permission: the backend refused us (socket perms, auth).
+	Permission Kind = 3
+	// Unavailable: the backend (Docker daemon, systemd, network peer)
+	// cannot be reached at all.
+	Unavailable Kind = 4
+	// Partial: the operation partly succeeded, e.g. some units of a
+	// group action failed.
+	Partial Kind = 5
+)
+
+func (k Kind) String() string {
+	switch k {
+	case OK:
+		return "ok"
+	case Config:
+		return "config error"
+	case Permission:
+		return "permission denied"
+	case Unavailable:
+		return "backend unavailable"
+	case Partial:
+		return "partial failure"
+	default:
+		return "error"
+	}
+}
+
+// Error carries a category alongside the underlying error.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+// New builds a categorized error.
+func New(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap attaches a category to err; a nil err stays nil.
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// ExitCode maps an error to its documented exit code. Uncategorized
+// errors are Generic (1); nil is OK (0).
+func ExitCode(err error) int {
+	if err == nil {
+		return int(OK)
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return int(e.Kind)
+	}
+	return int(Generic)
+}
+
+// Fatal logs err and exits with its mapped code.
+func Fatal(err error) {
+	log.Printf("✗ %v", err)
+	os.Exit(ExitCode(err))
+}
@@ -0,0 +1,40 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{errors.New("plain"), 1},
+		{New(Config, "bad threshold"), 2},
+		{Wrap(Unavailable, errors.New("dial unix: no such file")), 4},
+		{fmt.Errorf("wrapped: %w", New(Permission, "denied")), 3},
+		{Wrap(Partial, errors.New("2 of 5 failed")), 5},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	if Wrap(Config, nil) != nil {
+		t.Error("Wrap(nil) should stay nil")
+	}
+}
+
+func TestErrorUnwrap(t *testing.T) {
+	base := errors.New("root cause")
+	err := Wrap(Unavailable, fmt.Errorf("context: %w", base))
+	if !errors.Is(err, base) {
+		t.Error("errors.Is should see through the categorized wrapper")
+	}
+}
@@ -0,0 +1,93 @@
+// Package format holds the small display helpers shared by the tools
+// in this repo — humanized durations, byte sizes, colored percentages
+// and ellipsis truncation — so every view renders values the same way.
+package format
+
+import (
+	"fmt"
+	"time"
+)
+
+// ANSI fragments used by Percent; exported so callers can compose
+// matching styling.
+const (
+	Reset  = "\x1b[0m"
+	Green  = "\x1b[32m"
+	Yellow = "\x1b[33m"
+	Red    = "\x1b[31m"
+)
+
+// Duration renders d compactly with at most two units: "450ms",
+// "3m20s", "1h5m", "2d3h".
+func Duration(d time.Duration) string {
+	if d < 0 {
+		return "-" + Duration(-d)
+	}
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		m := int(d.Minutes())
+		if s := int(d.Seconds()) % 60; s > 0 {
+			return fmt.Sprintf("%dm%ds", m, s)
+		}
+		return fmt.Sprintf("%dm", m)
+	case d < 24*time.Hour:
+		h := int(d.Hours())
+		if m := int(d.Minutes()) % 60; m > 0 {
+			return fmt.Sprintf("%dh%dm", h, m)
+		}
+		return fmt.Sprintf("%dh", h)
+	default:
+		days := int(d.Hours()) / 24
+		if h := int(d.Hours()) % 24; h > 0 {
+			return fmt.Sprintf("%dd%dh", days, h)
+		}
+		return fmt.Sprintf("%dd", days)
+	}
+}
+
+// Bytes renders a byte count with binary units: "512B", "1.5MiB".
+func Bytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// Percent renders a percentage with one decimal, colored green below
+// warn, yellow below crit, red at or above crit.
+func Percent(v, warn, crit float64) string {
+	color := Green
+	switch {
+	case v >= crit:
+		color = Red
+	case v >= warn:
+		color = Yellow
+	}
+	return fmt.Sprintf("%s%.1f%%%s", color, v, Reset)
+}
+
+// Truncate shortens s to width runes, ending with an ellipsis when it
+// was cut.
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,83 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{450 * time.Millisecond, "450ms"},
+		{5 * time.Second, "5s"},
+		{3*time.Minute + 20*time.Second, "3m20s"},
+		{10 * time.Minute, "10m"},
+		{time.Hour + 5*time.Minute, "1h5m"},
+		{2 * time.Hour, "2h"},
+		{51 * time.Hour, "2d3h"},
+		{48 * time.Hour, "2d"},
+		{-5 * time.Second, "-5s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.in); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		in   uint64
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1024, "1.0KiB"},
+		{1536, "1.5KiB"},
+		{3 * 1024 * 1024, "3.0MiB"},
+		{1610612736, "1.5GiB"},
+	}
+	for _, tt := range tests {
+		if got := Bytes(tt.in); got != tt.want {
+			t.Errorf("Bytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	if got := Percent(10, 60, 85); !strings.HasPrefix(got, Green) {
+		t.Errorf("Percent(10) = %q, want green", got)
+	}
+	if got := Percent(70, 60, 85); !strings.HasPrefix(got, Yellow) {
+		t.Errorf("Percent(70) = %q, want yellow", got)
+	}
+	if got := Percent(90, 60, 85); !strings.HasPrefix(got, Red) {
+		t.Errorf("Percent(90) = %q, want red", got)
+	}
+	if got := Percent(42.25, 60, 85); !strings.Contains(got, "42.2%") {
+		t.Errorf("Percent(42.25) = %q, want one decimal", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exact", 5, "exact"},
+		{"toolong", 5, "tool…"},
+		{"x", 0, ""},
+		{"xy", 1, "…"},
+		{"héllo wörld", 6, "héllo…"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.width); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package lifecycle coordinates the shutdown of the long-running tools
+// in this repo: one context cancelled on SIGINT/SIGTERM, workers that
+// take the whole group down when any of them stops, and cleanups that
+// run in reverse registration order under a timeout. It replaces the
+// per-tool signal goroutines and makes teardown testable.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Group owns the run context of a daemon and its ordered shutdown.
+type Group struct {
+	ctx     context.Context
+	stop    context.CancelFunc
+	timeout time.Duration
+
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	err      error
+	cleanups []cleanup
+}
+
+type cleanup struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// New returns a group whose context ends on SIGINT or SIGTERM. Each
+// cleanup registered with Defer gets at most timeout to finish.
+func New(timeout time.Duration) *Group {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	return &Group{ctx: ctx, stop: stop, timeout: timeout}
+}
+
+// WithContext is New without the signal wiring, for tests and for
+// embedding in a larger lifecycle.
+func WithContext(parent context.Context, timeout time.Duration) *Group {
+	ctx, stop := context.WithCancel(parent)
+	return &Group{ctx: ctx, stop: stop, timeout: timeout}
+}
+
+// Context is the run context workers should block on.
+func (g *Group) Context() context.Context { return g.ctx }
+
+// Stop cancels the group context, beginning shutdown.
+func (g *Group) Stop() { g.stop() }
+
+// Go runs fn as a group worker. When any worker returns — error or
+// not — the group context is cancelled so the others wind down too.
+func (g *Group) Go(fn func(context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer g.stop()
+		if err := fn(g.ctx); err != nil {
+			g.mu.Lock()
+			if g.err == nil {
+				g.err = err
+			}
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Defer registers a cleanup to run at shutdown. Cleanups run in
+// reverse registration order, mirroring defer.
+func (g *Group) Defer(name string, fn func(context.Context) error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cleanups = append(g.cleanups, cleanup{name: name, fn: fn})
+}
+
+// Wait blocks until the group stops (signal, Stop, or a worker
+// returning), waits for the workers, then runs the cleanups — each
+// bounded by the group timeout. It returns the first worker or
+// cleanup error.
+func (g *Group) Wait() error {
+	<-g.ctx.Done()
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := len(g.cleanups) - 1; i >= 0; i-- {
+		c := g.cleanups[i]
+		ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+		err := c.fn(ctx)
+		cancel()
+		if err != nil && g.err == nil {
+			g.err = fmt.Errorf("%s: %w", c.name, err)
+		}
+	}
+	return g.err
+}
@@ -0,0 +1,73 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCleanupOrder(t *testing.T) {
+	g := WithContext(context.Background(), time.Second)
+	var order []string
+	g.Defer("first", func(context.Context) error { order = append(order, "first"); return nil })
+	g.Defer("second", func(context.Context) error { order = append(order, "second"); return nil })
+	g.Stop()
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if len(order) != 2 || order[0] != "second" || order[1] != "first" {
+		t.Errorf("cleanup order = %v, want newest first", order)
+	}
+}
+
+func TestWorkerExitStopsGroup(t *testing.T) {
+	g := WithContext(context.Background(), time.Second)
+	g.Go(func(context.Context) error { return nil })
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done() // the other worker's exit must release this one
+		return nil
+	})
+	done := make(chan error, 1)
+	go func() { done <- g.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("group did not stop after a worker returned")
+	}
+}
+
+func TestFirstErrorWins(t *testing.T) {
+	g := WithContext(context.Background(), time.Second)
+	boom := errors.New("boom")
+	g.Go(func(context.Context) error { return boom })
+	g.Defer("cleanup", func(context.Context) error { return errors.New("cleanup failed") })
+	if err := g.Wait(); !errors.Is(err, boom) {
+		t.Errorf("Wait = %v, want the worker error", err)
+	}
+}
+
+func TestCleanupErrorReported(t *testing.T) {
+	g := WithContext(context.Background(), time.Second)
+	g.Defer("flush", func(context.Context) error { return errors.New("disk full") })
+	g.Stop()
+	err := g.Wait()
+	if err == nil || err.Error() != "flush: disk full" {
+		t.Errorf("Wait = %v, want named cleanup error", err)
+	}
+}
+
+func TestCleanupTimeout(t *testing.T) {
+	g := WithContext(context.Background(), 10*time.Millisecond)
+	g.Defer("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	g.Stop()
+	if err := g.Wait(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v, want deadline exceeded", err)
+	}
+}
@@ -0,0 +1,371 @@
+package sdbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Message types and header field codes from the D-Bus specification.
+const (
+	msgMethodCall = 1
+	msgReturn     = 2
+	msgError      = 3
+	msgSignal     = 4
+)
+
+const (
+	fieldPath   = 1
+	fieldIface  = 2
+	fieldMember = 3
+	fieldErr    = 4
+	fieldReply  = 5
+	fieldDest   = 6
+	fieldSig    = 8
+)
+
+// Message is one decoded bus message. Body values follow the
+// signature: basic types map to their Go counterparts, arrays and
+// structs to []any.
+type Message struct {
+	Type    byte
+	Serial  uint32
+	ReplyTo uint32 // which call a return or error answers
+	Path    string
+	Iface   string
+	Member  string
+	ErrName string
+	Sig     string
+	Body    []any
+}
+
+// encoder appends little-endian wire data. Alignment is relative to
+// the buffer start, so callers embed sub-buffers only at 8-aligned
+// offsets.
+type encoder struct{ b []byte }
+
+func (e *encoder) pad(align int) {
+	for len(e.b)%align != 0 {
+		e.b = append(e.b, 0)
+	}
+}
+
+func (e *encoder) byte(v byte) { e.b = append(e.b, v) }
+
+func (e *encoder) u32(v uint32) {
+	e.pad(4)
+	e.b = append(e.b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// str writes a string: aligned length, bytes, nul.
+func (e *encoder) str(s string) {
+	e.u32(uint32(len(s)))
+	e.b = append(e.b, s...)
+	e.b = append(e.b, 0)
+}
+
+// sig writes a signature: byte length, bytes, nul, no alignment.
+func (e *encoder) sig(s string) {
+	e.b = append(e.b, byte(len(s)))
+	e.b = append(e.b, s...)
+	e.b = append(e.b, 0)
+}
+
+// marshalCall builds a method call with string arguments, the only
+// argument type the systemd manager methods we use take.
+func marshalCall(serial uint32, dest, path, iface, method string, args []string) []byte {
+	var body encoder
+	for _, a := range args {
+		body.str(a)
+	}
+
+	var f encoder // header fields, a(yv)
+	field := func(code byte, sig string, write func(*encoder)) {
+		f.pad(8)
+		f.byte(code)
+		f.sig(sig)
+		write(&f)
+	}
+	field(fieldPath, "o", func(e *encoder) { e.str(path) })
+	field(fieldIface, "s", func(e *encoder) { e.str(iface) })
+	field(fieldMember, "s", func(e *encoder) { e.str(method) })
+	field(fieldDest, "s", func(e *encoder) { e.str(dest) })
+	if len(args) > 0 {
+		field(fieldSig, "g", func(e *encoder) { e.sig(strings.Repeat("s", len(args))) })
+	}
+
+	var m encoder
+	m.byte('l')
+	m.byte(msgMethodCall)
+	m.byte(0)
+	m.byte(1)
+	m.u32(uint32(len(body.b)))
+	m.u32(serial)
+	m.u32(uint32(len(f.b)))
+	m.b = append(m.b, f.b...)
+	m.pad(8)
+	m.b = append(m.b, body.b...)
+	return m.b
+}
+
+// readMessage reads and decodes one message.
+func readMessage(r io.Reader) (Message, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return Message{}, err
+	}
+	var ord binary.ByteOrder = binary.LittleEndian
+	if fixed[0] == 'B' {
+		ord = binary.BigEndian
+	}
+	bodyLen := ord.Uint32(fixed[4:8])
+	fieldsLen := ord.Uint32(fixed[12:16])
+	fieldsPadded := (fieldsLen + 7) &^ 7
+	rest := make([]byte, fieldsPadded+bodyLen)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{Type: fixed[1], Serial: ord.Uint32(fixed[8:12])}
+	d := decoder{b: rest[:fieldsLen], ord: ord}
+	for d.off < len(d.b) {
+		d.align(8)
+		if d.off >= len(d.b) {
+			break
+		}
+		code, err := d.readByte()
+		if err != nil {
+			return Message{}, err
+		}
+		v, _, err := d.value("v")
+		if err != nil {
+			return Message{}, fmt.Errorf("header field %d: %w", code, err)
+		}
+		switch code {
+		case fieldPath:
+			msg.Path, _ = v.(string)
+		case fieldIface:
+			msg.Iface, _ = v.(string)
+		case fieldMember:
+			msg.Member, _ = v.(string)
+		case fieldErr:
+			msg.ErrName, _ = v.(string)
+		case fieldReply:
+			msg.ReplyTo, _ = v.(uint32)
+		case fieldSig:
+			msg.Sig, _ = v.(string)
+		}
+	}
+
+	if msg.Sig != "" && bodyLen > 0 {
+		bd := decoder{b: rest[fieldsPadded:], ord: ord}
+		for sig := msg.Sig; sig != ""; {
+			v, restSig, err := bd.value(sig)
+			if err != nil {
+				return Message{}, fmt.Errorf("body (%s): %w", msg.Sig, err)
+			}
+			msg.Body = append(msg.Body, v)
+			sig = restSig
+		}
+	}
+	return msg, nil
+}
+
+// decoder walks wire data driven by a type signature.
+type decoder struct {
+	b   []byte
+	off int
+	ord binary.ByteOrder
+}
+
+func (d *decoder) align(n int) { d.off = (d.off + n - 1) / n * n }
+
+func (d *decoder) need(n int) ([]byte, error) {
+	if d.off+n > len(d.b) {
+		return nil, fmt.Errorf("truncated message")
+	}
+	s := d.b[d.off : d.off+n]
+	d.off += n
+	return s, nil
+}
+
+func (d *decoder) readByte() (byte, error) {
+	s, err := d.need(1)
+	if err != nil {
+		return 0, err
+	}
+	return s[0], nil
+}
+
+func (d *decoder) readU32() (uint32, error) {
+	d.align(4)
+	s, err := d.need(4)
+	if err != nil {
+		return 0, err
+	}
+	return d.ord.Uint32(s), nil
+}
+
+func (d *decoder) readString() (string, error) {
+	n, err := d.readU32()
+	if err != nil {
+		return "", err
+	}
+	s, err := d.need(int(n) + 1) // content plus nul
+	if err != nil {
+		return "", err
+	}
+	return string(s[:n]), nil
+}
+
+// value decodes one complete type off the front of sig and returns the
+// value and the remaining signature.
+func (d *decoder) value(sig string) (any, string, error) {
+	if sig == "" {
+		return nil, "", fmt.Errorf("empty signature")
+	}
+	rest := sig[1:]
+	switch sig[0] {
+	case 'y':
+		v, err := d.readByte()
+		return v, rest, err
+	case 'b':
+		v, err := d.readU32()
+		return v != 0, rest, err
+	case 'n', 'q':
+		d.align(2)
+		s, err := d.need(2)
+		if err != nil {
+			return nil, "", err
+		}
+		if sig[0] == 'n' {
+			return int16(d.ord.Uint16(s)), rest, nil
+		}
+		return d.ord.Uint16(s), rest, nil
+	case 'i':
+		v, err := d.readU32()
+		return int32(v), rest, err
+	case 'u':
+		v, err := d.readU32()
+		return v, rest, err
+	case 'x', 't', 'd':
+		d.align(8)
+		s, err := d.need(8)
+		if err != nil {
+			return nil, "", err
+		}
+		u := d.ord.Uint64(s)
+		switch sig[0] {
+		case 'x':
+			return int64(u), rest, nil
+		case 'd':
+			return math.Float64frombits(u), rest, nil
+		}
+		return u, rest, nil
+	case 's', 'o':
+		v, err := d.readString()
+		return v, rest, err
+	case 'g':
+		n, err := d.readByte()
+		if err != nil {
+			return nil, "", err
+		}
+		s, err := d.need(int(n) + 1)
+		if err != nil {
+			return nil, "", err
+		}
+		return string(s[:n]), rest, nil
+	case 'v':
+		inner, _, err := d.value("g")
+		if err != nil {
+			return nil, "", err
+		}
+		v, _, err := d.value(inner.(string))
+		return v, rest, err
+	case 'a':
+		elem, elemRest, err := splitType(rest)
+		if err != nil {
+			return nil, "", err
+		}
+		n, err := d.readU32()
+		if err != nil {
+			return nil, "", err
+		}
+		d.align(alignOf(elem[0]))
+		end := d.off + int(n)
+		if end > len(d.b) {
+			return nil, "", fmt.Errorf("truncated array")
+		}
+		var vals []any
+		for d.off < end {
+			v, _, err := d.value(elem)
+			if err != nil {
+				return nil, "", err
+			}
+			vals = append(vals, v)
+		}
+		return vals, elemRest, nil
+	case '(':
+		d.align(8)
+		var vals []any
+		for {
+			if rest == "" {
+				return nil, "", fmt.Errorf("unterminated struct")
+			}
+			if rest[0] == ')' {
+				return vals, rest[1:], nil
+			}
+			v, r, err := d.value(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			vals = append(vals, v)
+			rest = r
+		}
+	default:
+		return nil, "", fmt.Errorf("unsupported type %q", sig[0])
+	}
+}
+
+// splitType cuts the first complete type off a signature.
+func splitType(sig string) (string, string, error) {
+	if sig == "" {
+		return "", "", fmt.Errorf("empty signature")
+	}
+	switch sig[0] {
+	case 'a':
+		inner, rest, err := splitType(sig[1:])
+		return "a" + inner, rest, err
+	case '(':
+		depth := 1
+		for i := 1; i < len(sig); i++ {
+			switch sig[i] {
+			case '(':
+				depth++
+			case ')':
+				if depth--; depth == 0 {
+					return sig[:i+1], sig[i+1:], nil
+				}
+			}
+		}
+		return "", "", fmt.Errorf("unterminated struct in %q", sig)
+	default:
+		return sig[:1], sig[1:], nil
+	}
+}
+
+// alignOf gives a type's wire alignment.
+func alignOf(t byte) int {
+	switch t {
+	case 'y', 'g', 'v':
+		return 1
+	case 'n', 'q':
+		return 2
+	case 'x', 't', 'd', '(':
+		return 8
+	default: // b i u s o a
+		return 4
+	}
+}
@@ -0,0 +1,134 @@
+package sdbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestCallRoundTrip(t *testing.T) {
+	raw := marshalCall(7, "org.freedesktop.systemd1", "/org/freedesktop/systemd1",
+		"org.freedesktop.systemd1.Manager", "StartUnit", []string{"nginx.service", "replace"})
+	m, err := readMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("readMessage: %v", err)
+	}
+	if m.Type != msgMethodCall || m.Serial != 7 {
+		t.Errorf("type/serial = %d/%d", m.Type, m.Serial)
+	}
+	if m.Path != "/org/freedesktop/systemd1" || m.Member != "StartUnit" {
+		t.Errorf("path/member = %q/%q", m.Path, m.Member)
+	}
+	if m.Sig != "ss" || len(m.Body) != 2 || m.Body[0] != "nginx.service" || m.Body[1] != "replace" {
+		t.Errorf("body = %q %v", m.Sig, m.Body)
+	}
+}
+
+func TestCallWithoutArgs(t *testing.T) {
+	raw := marshalCall(1, "org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello", nil)
+	m, err := readMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("readMessage: %v", err)
+	}
+	if m.Sig != "" || m.Body != nil {
+		t.Errorf("no-arg call decoded body %q %v", m.Sig, m.Body)
+	}
+}
+
+// marshalReply assembles a method return by hand, since the client
+// only ever sends calls.
+func marshalReply(replyTo uint32, sig string, body []byte) []byte {
+	var f encoder
+	f.pad(8)
+	f.byte(fieldReply)
+	f.sig("u")
+	f.u32(replyTo)
+	if sig != "" {
+		f.pad(8)
+		f.byte(fieldSig)
+		f.sig("g")
+		f.sig(sig)
+	}
+
+	var m encoder
+	m.byte('l')
+	m.byte(msgReturn)
+	m.byte(0)
+	m.byte(1)
+	m.u32(uint32(len(body)))
+	m.u32(99)
+	m.u32(uint32(len(f.b)))
+	m.b = append(m.b, f.b...)
+	m.pad(8)
+	m.b = append(m.b, body...)
+	return m.b
+}
+
+func TestDecodeUnitRows(t *testing.T) {
+	// A two-row ListUnits reply: a(ssssssouso), every struct 8-aligned.
+	row := func(e *encoder, name, desc string) {
+		e.pad(8)
+		for _, s := range []string{name, desc, "loaded", "active", "running", ""} {
+			e.str(s)
+		}
+		e.str("/org/freedesktop/systemd1/unit/x") // o
+		e.u32(0)                                  // u
+		e.str("")                                 // s
+		e.str("/")                                // o
+	}
+	var body encoder
+	body.u32(0) // array byte length, patched below
+	body.pad(8)
+	row(&body, "nginx.service", "A web server")
+	row(&body, "sshd.service", "OpenSSH server")
+	binary.LittleEndian.PutUint32(body.b[:4], uint32(len(body.b)-8))
+
+	m, err := readMessage(bytes.NewReader(marshalReply(3, "a(ssssssouso)", body.b)))
+	if err != nil {
+		t.Fatalf("readMessage: %v", err)
+	}
+	if m.ReplyTo != 3 {
+		t.Errorf("ReplyTo = %d", m.ReplyTo)
+	}
+	rows, ok := m.Body[0].([]any)
+	if !ok || len(rows) != 2 {
+		t.Fatalf("rows = %#v", m.Body)
+	}
+	first, ok := rows[0].([]any)
+	if !ok || len(first) != 10 {
+		t.Fatalf("first row = %#v", rows[0])
+	}
+	if first[0] != "nginx.service" || first[4] != "running" {
+		t.Errorf("first row fields = %v", first)
+	}
+	if second := rows[1].([]any); second[0] != "sshd.service" {
+		t.Errorf("second row fields = %v", second)
+	}
+}
+
+func TestSplitType(t *testing.T) {
+	cases := []struct{ sig, typ, rest string }{
+		{"s", "s", ""},
+		{"a(yv)u", "a(yv)", "u"},
+		{"(so(ii))s", "(so(ii))", "s"},
+		{"aas", "aas", ""},
+	}
+	for _, c := range cases {
+		typ, rest, err := splitType(c.sig)
+		if err != nil || typ != c.typ || rest != c.rest {
+			t.Errorf("splitType(%q) = %q, %q, %v", c.sig, typ, rest, err)
+		}
+	}
+	if _, _, err := splitType("(s"); err == nil {
+		t.Error("unterminated struct accepted")
+	}
+}
+
+func TestAddrPath(t *testing.T) {
+	if got := addrPath("unix:path=/run/dbus/bus;unixexec:...", "/fallback"); got != "/run/dbus/bus" {
+		t.Errorf("addrPath = %q", got)
+	}
+	if got := addrPath("", "/fallback"); got != "/fallback" {
+		t.Errorf("addrPath fallback = %q", got)
+	}
+}
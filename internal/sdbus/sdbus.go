@@ -0,0 +1,303 @@
+// Package sdbus is a minimal D-Bus client: EXTERNAL auth, method calls
+// with string arguments and signature-driven decoding of replies and
+// signals. It covers just enough of the wire protocol to talk to the
+// systemd manager, so the tools here can use the bus API without
+// pulling in a D-Bus library.
+package sdbus
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxQueuedSignals bounds the queue of signals no waiter has claimed
+// yet. Every waiter filters with a match function, so dropping the
+// oldest unclaimed signal can only lose traffic nobody asked for.
+const maxQueuedSignals = 32
+
+// Conn is one authenticated bus connection, safe for concurrent use: a
+// single reader goroutine routes method replies to their callers and
+// signals to waiters, so a caller blocked in WaitSignal never holds up
+// anyone else's Call.
+type Conn struct {
+	c net.Conn
+	r *bufio.Reader // owned by the read goroutine after auth
+
+	mu      sync.Mutex
+	serial  uint32
+	pending map[uint32]chan Message // in-flight calls by serial
+	waiters []*sigWaiter
+	signals []Message // signals that arrived with no waiter to claim them
+	readErr error     // set once the read goroutine dies
+	done    chan struct{}
+}
+
+// sigWaiter is one blocked WaitSignal: the first signal that passes its
+// filter is handed over and the waiter removed.
+type sigWaiter struct {
+	member string
+	match  func(Message) bool
+	ch     chan Message // buffered; the reader never blocks on it
+}
+
+// Error is an error reply from the bus or a service on it, e.g.
+// org.freedesktop.systemd1.NoSuchUnit. Its presence means the bus
+// itself is healthy — the call was delivered and refused.
+type Error struct {
+	Name    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Name + ": " + e.Message
+	}
+	return e.Name
+}
+
+// System connects to the system bus.
+func System() (*Conn, error) {
+	return dial(addrPath(os.Getenv("DBUS_SYSTEM_BUS_ADDRESS"), "/var/run/dbus/system_bus_socket"))
+}
+
+// User connects to the calling user's session bus.
+func User() (*Conn, error) {
+	fallback := ""
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		fallback = dir + "/bus"
+	}
+	return dial(addrPath(os.Getenv("DBUS_SESSION_BUS_ADDRESS"), fallback))
+}
+
+// addrPath extracts the socket path from a unix:path= bus address,
+// falling back to the conventional location when the variable is unset
+// or names no unix socket.
+func addrPath(addr, fallback string) string {
+	for _, part := range strings.Split(addr, ";") {
+		if p, ok := strings.CutPrefix(part, "unix:path="); ok {
+			return p
+		}
+	}
+	return fallback
+}
+
+func dial(path string) (*Conn, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no bus address")
+	}
+	nc, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Conn{
+		c:       nc,
+		r:       bufio.NewReader(nc),
+		pending: make(map[uint32]chan Message),
+		done:    make(chan struct{}),
+	}
+	if err := c.auth(); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	go c.read()
+	// Hello assigns our unique name; the bus refuses anything else
+	// before it.
+	if _, err := c.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", "Hello"); err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// auth runs the EXTERNAL handshake, which authenticates by the uid the
+// kernel reports on the socket.
+func (c *Conn) auth() error {
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := fmt.Fprintf(c.c, "\x00AUTH EXTERNAL %s\r\n", uid); err != nil {
+		return err
+	}
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		return fmt.Errorf("bus auth: %s", strings.TrimSpace(line))
+	}
+	_, err = fmt.Fprint(c.c, "BEGIN\r\n")
+	return err
+}
+
+func (c *Conn) Close() error { return c.c.Close() }
+
+// read is the sole reader after auth. It routes replies to the call
+// that sent them and signals to the first waiter whose filter accepts
+// them, until the connection dies.
+func (c *Conn) read() {
+	for {
+		m, err := readMessage(c.r)
+		if err != nil {
+			c.mu.Lock()
+			c.readErr = err
+			c.mu.Unlock()
+			close(c.done)
+			return
+		}
+		c.mu.Lock()
+		switch m.Type {
+		case msgReturn, msgError:
+			if ch, ok := c.pending[m.ReplyTo]; ok {
+				delete(c.pending, m.ReplyTo)
+				ch <- m
+			}
+		case msgSignal:
+			c.route(m)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// route hands a signal to the first waiter that claims it, or queues it
+// for a later WaitSignal. Called with mu held.
+func (c *Conn) route(m Message) {
+	for i, w := range c.waiters {
+		if w.member == m.Member && w.match(m) {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			w.ch <- m
+			return
+		}
+	}
+	c.signals = append(c.signals, m)
+	if len(c.signals) > maxQueuedSignals {
+		c.signals = c.signals[1:]
+	}
+}
+
+// connErr reports why the read goroutine stopped.
+func (c *Conn) connErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.readErr
+}
+
+// Call invokes a method with string arguments and returns the decoded
+// reply body. An error reply comes back as *Error. Calls from
+// different goroutines proceed independently; only the write of each
+// request frame is serialized.
+func (c *Conn) Call(dest, path, iface, method string, args ...string) ([]any, error) {
+	ch := make(chan Message, 1)
+	c.mu.Lock()
+	if c.readErr != nil {
+		err := c.readErr
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.serial++
+	serial := c.serial
+	c.pending[serial] = ch
+	_, err := c.c.Write(marshalCall(serial, dest, path, iface, method, args))
+	c.mu.Unlock()
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, serial)
+		c.mu.Unlock()
+		return nil, err
+	}
+	select {
+	case m := <-ch:
+		return reply(m)
+	case <-c.done:
+		// The reply may have been delivered just before the reader died.
+		select {
+		case m := <-ch:
+			return reply(m)
+		default:
+		}
+		return nil, c.connErr()
+	}
+}
+
+// reply converts a routed method return into its body, or an error
+// reply into *Error.
+func reply(m Message) ([]any, error) {
+	if m.Type == msgError {
+		e := &Error{Name: m.ErrName}
+		if len(m.Body) > 0 {
+			if s, ok := m.Body[0].(string); ok {
+				e.Message = s
+			}
+		}
+		return nil, e
+	}
+	return m.Body, nil
+}
+
+// WaitSignal returns the next signal with the given member that passes
+// match (nil accepts any), waiting until one arrives or the deadline
+// passes. Signals nobody claimed earlier are checked first, and a
+// signal another waiter's filter rejects is never consumed on its
+// behalf. match runs with the connection's lock held — it must only
+// look at the message.
+func (c *Conn) WaitSignal(member string, match func(Message) bool, deadline time.Time) (Message, error) {
+	if match == nil {
+		match = func(Message) bool { return true }
+	}
+	c.mu.Lock()
+	for i, m := range c.signals {
+		if m.Member == member && match(m) {
+			c.signals = append(c.signals[:i], c.signals[i+1:]...)
+			c.mu.Unlock()
+			return m, nil
+		}
+	}
+	if c.readErr != nil {
+		err := c.readErr
+		c.mu.Unlock()
+		return Message{}, err
+	}
+	w := &sigWaiter{member: member, match: match, ch: make(chan Message, 1)}
+	c.waiters = append(c.waiters, w)
+	c.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	select {
+	case m := <-w.ch:
+		return m, nil
+	case <-timer.C:
+		if m, ok := c.abandon(w); ok {
+			return m, nil
+		}
+		return Message{}, fmt.Errorf("timed out waiting for %s", member)
+	case <-c.done:
+		if m, ok := c.abandon(w); ok {
+			return m, nil
+		}
+		return Message{}, c.connErr()
+	}
+}
+
+// abandon deregisters a waiter, returning the signal that may have
+// been delivered concurrently with the timeout.
+func (c *Conn) abandon(w *sigWaiter) (Message, bool) {
+	c.mu.Lock()
+	for i, cand := range c.waiters {
+		if cand == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	c.mu.Unlock()
+	select {
+	case m := <-w.ch:
+		return m, true
+	default:
+		return Message{}, false
+	}
+}
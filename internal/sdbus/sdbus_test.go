@@ -0,0 +1,191 @@
+package sdbus
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+)
+
+// testConn wires a Conn to one end of an in-process pipe, skipping
+// auth and Hello; the test plays the bus on the far end.
+func testConn(t *testing.T) (*Conn, net.Conn) {
+	t.Helper()
+	near, far := net.Pipe()
+	c := &Conn{
+		c:       near,
+		r:       bufio.NewReader(near),
+		pending: make(map[uint32]chan Message),
+		done:    make(chan struct{}),
+	}
+	go c.read()
+	t.Cleanup(func() {
+		near.Close()
+		far.Close()
+	})
+	return c, far
+}
+
+// marshalSignal assembles a one-string-argument signal frame by hand,
+// like marshalReply.
+func marshalSignal(member, arg string) []byte {
+	var f encoder
+	f.pad(8)
+	f.byte(fieldMember)
+	f.sig("s")
+	f.str(member)
+	f.pad(8)
+	f.byte(fieldSig)
+	f.sig("g")
+	f.sig("s")
+
+	var body encoder
+	body.str(arg)
+
+	var m encoder
+	m.byte('l')
+	m.byte(msgSignal)
+	m.byte(0)
+	m.byte(1)
+	m.u32(uint32(len(body.b)))
+	m.u32(77)
+	m.u32(uint32(len(f.b)))
+	m.b = append(m.b, f.b...)
+	m.pad(8)
+	m.b = append(m.b, body.b...)
+	return m.b
+}
+
+// TestCallWhileWaitingForSignal is the regression test for the old
+// single-lock design, where a blocked WaitSignal held up every Call on
+// the connection.
+func TestCallWhileWaitingForSignal(t *testing.T) {
+	c, far := testConn(t)
+	r := bufio.NewReader(far)
+
+	sigs := make(chan Message, 1)
+	go func() {
+		m, err := c.WaitSignal("JobRemoved", nil, time.Now().Add(5*time.Second))
+		if err != nil {
+			t.Errorf("WaitSignal: %v", err)
+		}
+		sigs <- m
+	}()
+
+	// With the waiter blocked, a call must still go through.
+	done := make(chan error, 1)
+	go func() {
+		body, err := c.Call("dest", "/path", "iface", "Ping")
+		if err == nil && len(body) != 1 {
+			t.Errorf("call body = %v", body)
+		}
+		done <- err
+	}()
+	req, err := readMessage(r)
+	if err != nil {
+		t.Fatalf("read request: %v", err)
+	}
+	var reply encoder
+	reply.str("pong")
+	if _, err := far.Write(marshalReply(req.Serial, "s", reply.b)); err != nil {
+		t.Fatalf("write reply: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Call: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Call blocked behind WaitSignal")
+	}
+
+	if _, err := far.Write(marshalSignal("JobRemoved", "/job/1")); err != nil {
+		t.Fatalf("write signal: %v", err)
+	}
+	select {
+	case m := <-sigs:
+		if len(m.Body) != 1 || m.Body[0] != "/job/1" {
+			t.Errorf("signal body = %v", m.Body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("signal never delivered")
+	}
+}
+
+// TestSignalFilterRouting checks that a waiter never consumes a signal
+// its filter rejects: two concurrent waiters each get their own.
+func TestSignalFilterRouting(t *testing.T) {
+	c, far := testConn(t)
+
+	wait := func(job string) chan error {
+		errs := make(chan error, 1)
+		go func() {
+			m, err := c.WaitSignal("JobRemoved", func(m Message) bool {
+				return len(m.Body) == 1 && m.Body[0] == job
+			}, time.Now().Add(5*time.Second))
+			if err == nil && m.Body[0] != job {
+				t.Errorf("waiter for %s got %v", job, m.Body)
+			}
+			errs <- err
+		}()
+		return errs
+	}
+	errsA, errsB := wait("/job/a"), wait("/job/b")
+
+	// B's signal first: it must reach B even though A registered first.
+	if _, err := far.Write(marshalSignal("JobRemoved", "/job/b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errsB; err != nil {
+		t.Fatalf("waiter b: %v", err)
+	}
+	if _, err := far.Write(marshalSignal("JobRemoved", "/job/a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errsA; err != nil {
+		t.Fatalf("waiter a: %v", err)
+	}
+}
+
+func TestWaitSignalTimeout(t *testing.T) {
+	c, _ := testConn(t)
+	if _, err := c.WaitSignal("JobRemoved", nil, time.Now().Add(20*time.Millisecond)); err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.waiters) != 0 {
+		t.Errorf("%d waiters left registered after timeout", len(c.waiters))
+	}
+}
+
+// TestQueuedSignalDeliveredFirst covers the claim in WaitSignal's doc:
+// a signal that arrived with nobody waiting is handed to the next
+// matching waiter.
+func TestQueuedSignalDeliveredFirst(t *testing.T) {
+	c, far := testConn(t)
+	if _, err := far.Write(marshalSignal("JobRemoved", "/job/q")); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the reader to queue it before asking.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		n := len(c.signals)
+		c.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("signal never queued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	m, err := c.WaitSignal("JobRemoved", nil, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("WaitSignal: %v", err)
+	}
+	if m.Body[0] != "/job/q" {
+		t.Errorf("body = %v", m.Body)
+	}
+}
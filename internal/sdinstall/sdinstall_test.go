@@ -0,0 +1,64 @@
+package sdinstall
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	u := Unit{
+		Name:        "goscale-shop.service",
+		Description: "go-scale autoscaler for shop",
+		ExecStart:   "/usr/local/bin/go-scale -project shop -service web",
+		After:       []string{"docker.service"},
+	}
+	out := Render(u)
+	for _, want := range []string{
+		"Description=go-scale autoscaler for shop",
+		"After=network.target docker.service",
+		"ExecStart=/usr/local/bin/go-scale -project shop -service web",
+		"Restart=on-failure",
+		"NoNewPrivileges=true",
+		"WantedBy=multi-user.target",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered unit missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Type=") || strings.Contains(out, "WatchdogSec=") {
+		t.Errorf("unset Type/WatchdogSec rendered:\n%s", out)
+	}
+
+	u.Type = "notify"
+	u.WatchdogSec = 90 * time.Second
+	out = Render(u)
+	for _, want := range []string{"Type=notify", "WatchdogSec=90"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered unit missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInstallWritesFile(t *testing.T) {
+	dir := t.TempDir()
+	u := Unit{Name: "t.service", Description: "t", ExecStart: "/bin/true"}
+	path, err := Install(u, dir)
+	if err != nil {
+		t.Fatalf("Install: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != Render(u) {
+		t.Error("written file does not match rendered unit")
+	}
+}
+
+func TestInstallValidates(t *testing.T) {
+	if _, err := Install(Unit{}, t.TempDir()); err == nil {
+		t.Error("expected error for empty unit")
+	}
+}
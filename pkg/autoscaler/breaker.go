@@ -0,0 +1,55 @@
+// Package autoscaler holds the reusable decision building blocks behind
+// the go-scale command: the circuit breaker over the backend, the PID
+// controller and the expression rule engine. They carry no docker or
+// compose knowledge, so other programs can embed them against whatever
+// they scale.
+package autoscaler
+
+import "time"
+
+// Breaker is a circuit breaker over a flaky backend. After Threshold
+// consecutive failures it opens: the caller stops hammering a backend
+// that is clearly down and probes at ProbeEvery instead, closing again
+// on the first success. It is not safe for concurrent use.
+type Breaker struct {
+	Threshold  int // consecutive failures before opening; 0 disables
+	ProbeEvery time.Duration
+
+	failures  int
+	open      bool
+	lastProbe time.Time
+}
+
+// Allow reports whether this attempt should talk to the backend at all.
+// While open, only one probe per ProbeEvery gets through.
+func (b *Breaker) Allow(now time.Time) bool {
+	if !b.open {
+		return true
+	}
+	if now.Sub(b.lastProbe) >= b.ProbeEvery {
+		b.lastProbe = now
+		return true
+	}
+	return false
+}
+
+// Failure counts one failed backend interaction and reports whether the
+// circuit just opened.
+func (b *Breaker) Failure(now time.Time) bool {
+	b.failures++
+	if b.Threshold == 0 || b.open || b.failures < b.Threshold {
+		return false
+	}
+	b.open = true
+	b.lastProbe = now
+	return true
+}
+
+// Success resets the streak and reports whether the circuit just closed
+// again.
+func (b *Breaker) Success() bool {
+	b.failures = 0
+	wasOpen := b.open
+	b.open = false
+	return wasOpen
+}
@@ -0,0 +1,62 @@
+package autoscaler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := Breaker{Threshold: 3, ProbeEvery: time.Minute}
+
+	if !b.Allow(now) {
+		t.Fatal("closed breaker should allow")
+	}
+	if b.Failure(now) || b.Failure(now) {
+		t.Fatal("opened before reaching the threshold")
+	}
+	if !b.Failure(now) {
+		t.Fatal("third consecutive failure should open the circuit")
+	}
+
+	// Open: calls are shed until the probe interval elapses, and the
+	// probe itself only gets through once per interval.
+	if b.Allow(now.Add(30 * time.Second)) {
+		t.Error("open breaker allowed a call before the probe interval")
+	}
+	probeAt := now.Add(time.Minute)
+	if !b.Allow(probeAt) {
+		t.Error("probe not allowed after the interval")
+	}
+	if b.Allow(probeAt.Add(time.Second)) {
+		t.Error("second probe allowed within the same interval")
+	}
+
+	// A failed probe keeps it open without re-announcing.
+	if b.Failure(probeAt) {
+		t.Error("failure on an open circuit reported opening again")
+	}
+
+	if !b.Success() {
+		t.Error("success on an open circuit should report closing")
+	}
+	if b.Success() {
+		t.Error("success on a closed circuit reported closing")
+	}
+	if !b.Allow(probeAt) {
+		t.Error("closed breaker should allow again")
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	var b Breaker // zero threshold: never opens
+	for i := 0; i < 100; i++ {
+		if b.Failure(now) {
+			t.Fatal("disabled breaker opened")
+		}
+	}
+	if !b.Allow(now) {
+		t.Fatal("disabled breaker should always allow")
+	}
+}
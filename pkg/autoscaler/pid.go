@@ -0,0 +1,38 @@
+package autoscaler
+
+import "math"
+
+// PID computes replica deltas from the magnitude of the utilization
+// error instead of bang-bang thresholds: a service 40 points over
+// target gets more replicas in one step than one 5 points over, and
+// convergence near the setpoint is smooth.
+type PID struct {
+	kp, ki   float64
+	setpoint float64 // target utilization %
+
+	integral float64
+}
+
+// integralClamp bounds the accumulated error so a long saturated period
+// (e.g. pinned at max replicas) cannot wind up a huge correction.
+const integralClamp = 200
+
+func NewPID(kp, ki, setpoint float64) *PID {
+	return &PID{kp: kp, ki: ki, setpoint: setpoint}
+}
+
+// Next takes one utilization measurement and returns the replica target.
+func (p *PID) Next(measured float64, current int) int {
+	err := measured - p.setpoint
+	p.integral = math.Max(-integralClamp, math.Min(integralClamp, p.integral+err))
+	delta := p.kp*err + p.ki*p.integral
+	target := current + int(math.Round(delta))
+	if target < 1 {
+		target = 1
+	}
+	return target
+}
+
+// Reset clears the accumulated error, for when a scale action landed and
+// the old history no longer describes the new replica count.
+func (p *PID) Reset() { p.integral = 0 }
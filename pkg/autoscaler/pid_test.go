@@ -0,0 +1,53 @@
+package autoscaler
+
+import "testing"
+
+func TestPIDProportional(t *testing.T) {
+	p := NewPID(0.05, 0, 50)
+
+	if got := p.Next(50, 3); got != 3 {
+		t.Errorf("at setpoint: %d, want 3", got)
+	}
+	if got := p.Next(70, 3); got != 4 {
+		t.Errorf("20 over: %d, want 4 (one replica)", got)
+	}
+	if got := p.Next(95, 3); got != 5 {
+		t.Errorf("45 over: %d, want 5 (bigger error, bigger step)", got)
+	}
+	if got := p.Next(25, 3); got != 2 {
+		t.Errorf("25 under: %d, want 2", got)
+	}
+	if got := p.Next(0, 1); got != 1 {
+		t.Errorf("idle single replica: %d, want floor of 1", got)
+	}
+}
+
+func TestPIDIntegral(t *testing.T) {
+	// A small persistent error the proportional term alone rounds away
+	// should eventually accumulate into a step.
+	p := NewPID(0.02, 0.01, 50)
+	target := 3
+	for i := 0; i < 30; i++ {
+		if got := p.Next(62, 3); got > target {
+			target = got
+			break
+		}
+	}
+	if target == 3 {
+		t.Error("integral term never pushed past the rounding threshold")
+	}
+
+	// Wind-up is clamped: even after a long saturated stretch the
+	// correction stays bounded.
+	for i := 0; i < 1000; i++ {
+		p.Next(100, 5)
+	}
+	if p.integral > integralClamp {
+		t.Errorf("integral %f exceeds clamp", p.integral)
+	}
+
+	p.Reset()
+	if p.integral != 0 {
+		t.Error("reset did not clear the integral")
+	}
+}
@@ -0,0 +1,442 @@
+package autoscaler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression-based scaling rules. A rule is a boolean expression over
+// the poll's numbers, e.g.
+//
+//	cpu > 70 || (mem > 80 && replicas < 5)
+//
+// evaluated by a small built-in engine: comparisons, && || !, the four
+// arithmetic operators and parentheses over a fixed variable set — no
+// function calls and no code execution, so an expression from a config
+// file can't do anything but arithmetic.
+
+// ruleVars is the variable set expressions may reference.
+var ruleVars = map[string]bool{
+	"cpu":       true, // average CPU percent across replicas
+	"mem":       true, // average memory percent
+	"metric":    true, // custom metric sample (rule is false when absent)
+	"replicas":  true, // current counted replicas
+	"unhealthy": true, // percent of replicas failing their healthcheck
+	"min":       true, // effective minimum bound this poll
+	"max":       true, // effective maximum bound this poll
+}
+
+// Rule is one parsed scaling rule.
+type Rule struct {
+	text string
+	root exprNode
+}
+
+// exprValue is either a number or a boolean; mixing them outside a
+// comparison is a type error.
+type exprValue struct {
+	num    float64
+	b      bool
+	isBool bool
+}
+
+type exprNode interface {
+	eval(vars map[string]float64) (exprValue, error)
+}
+
+type numNode float64
+
+func (n numNode) eval(map[string]float64) (exprValue, error) {
+	return exprValue{num: float64(n)}, nil
+}
+
+type varNode string
+
+func (n varNode) eval(vars map[string]float64) (exprValue, error) {
+	v, ok := vars[string(n)]
+	if !ok {
+		return exprValue{}, fmt.Errorf("%s has no value this poll", string(n))
+	}
+	return exprValue{num: v}, nil
+}
+
+type unaryNode struct {
+	op string // "!" or "-"
+	x  exprNode
+}
+
+func (n unaryNode) eval(vars map[string]float64) (exprValue, error) {
+	v, err := n.x.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	switch n.op {
+	case "!":
+		if !v.isBool {
+			return exprValue{}, fmt.Errorf("! needs a boolean")
+		}
+		return exprValue{b: !v.b, isBool: true}, nil
+	default: // "-"
+		if v.isBool {
+			return exprValue{}, fmt.Errorf("- needs a number")
+		}
+		return exprValue{num: -v.num}, nil
+	}
+}
+
+type binaryNode struct {
+	op   string
+	x, y exprNode
+}
+
+func (n binaryNode) eval(vars map[string]float64) (exprValue, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	// && and || short-circuit like Go, so a guard such as
+	// `replicas > 0 && metric/replicas > 10` works as expected.
+	if n.op == "&&" || n.op == "||" {
+		if !x.isBool {
+			return exprValue{}, fmt.Errorf("%s needs booleans", n.op)
+		}
+		if (n.op == "&&" && !x.b) || (n.op == "||" && x.b) {
+			return x, nil
+		}
+		y, err := n.y.eval(vars)
+		if err != nil {
+			return exprValue{}, err
+		}
+		if !y.isBool {
+			return exprValue{}, fmt.Errorf("%s needs booleans", n.op)
+		}
+		return y, nil
+	}
+
+	y, err := n.y.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if x.isBool || y.isBool {
+		return exprValue{}, fmt.Errorf("%s needs numbers", n.op)
+	}
+	switch n.op {
+	case "+":
+		return exprValue{num: x.num + y.num}, nil
+	case "-":
+		return exprValue{num: x.num - y.num}, nil
+	case "*":
+		return exprValue{num: x.num * y.num}, nil
+	case "/":
+		if y.num == 0 {
+			return exprValue{}, fmt.Errorf("division by zero")
+		}
+		return exprValue{num: x.num / y.num}, nil
+	case "<":
+		return exprValue{b: x.num < y.num, isBool: true}, nil
+	case "<=":
+		return exprValue{b: x.num <= y.num, isBool: true}, nil
+	case ">":
+		return exprValue{b: x.num > y.num, isBool: true}, nil
+	case ">=":
+		return exprValue{b: x.num >= y.num, isBool: true}, nil
+	case "==":
+		return exprValue{b: x.num == y.num, isBool: true}, nil
+	case "!=":
+		return exprValue{b: x.num != y.num, isBool: true}, nil
+	}
+	return exprValue{}, fmt.Errorf("unknown operator %s", n.op)
+}
+
+// ParseRule parses and type-sanity-checks one rule expression.
+func ParseRule(text string) (*Rule, error) {
+	toks, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("unexpected %q", p.toks[p.pos])
+	}
+	isBool, err := typeCheck(root)
+	if err != nil {
+		return nil, err
+	}
+	if !isBool {
+		return nil, fmt.Errorf("rule must be a condition, not a number")
+	}
+	return &Rule{text: text, root: root}, nil
+}
+
+// typeCheck determines a subexpression's type (boolean or number)
+// structurally, without evaluating it: a probe evaluation would reject
+// valid rules like cpu/(replicas-1) > 5 on arithmetic grounds.
+func typeCheck(n exprNode) (isBool bool, err error) {
+	switch n := n.(type) {
+	case numNode, varNode:
+		return false, nil
+	case unaryNode:
+		xb, err := typeCheck(n.x)
+		if err != nil {
+			return false, err
+		}
+		if n.op == "!" {
+			if !xb {
+				return false, fmt.Errorf("! needs a boolean")
+			}
+			return true, nil
+		}
+		if xb {
+			return false, fmt.Errorf("- needs a number")
+		}
+		return false, nil
+	case binaryNode:
+		xb, err := typeCheck(n.x)
+		if err != nil {
+			return false, err
+		}
+		yb, err := typeCheck(n.y)
+		if err != nil {
+			return false, err
+		}
+		switch n.op {
+		case "&&", "||":
+			if !xb || !yb {
+				return false, fmt.Errorf("%s needs booleans", n.op)
+			}
+			return true, nil
+		case "+", "-", "*", "/":
+			if xb || yb {
+				return false, fmt.Errorf("%s needs numbers", n.op)
+			}
+			return false, nil
+		default: // comparisons
+			if xb || yb {
+				return false, fmt.Errorf("%s needs numbers", n.op)
+			}
+			return true, nil
+		}
+	}
+	return false, fmt.Errorf("unknown expression node %T", n)
+}
+
+// Eval evaluates the rule against one poll's variables. A missing
+// variable (no custom metric source configured, say) is an error;
+// callers treat that as "rule not met".
+func (r *Rule) Eval(vars map[string]float64) (bool, error) {
+	v, err := r.root.eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("rule %q: %w", r.text, err)
+	}
+	return v.b, nil
+}
+
+func tokenize(text string) ([]string, error) {
+	var toks []string
+	rs := []rune(text)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			toks = append(toks, string(rs[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+			toks = append(toks, string(rs[i:j]))
+			i = j
+		case strings.ContainsRune("()+-*/", r):
+			toks = append(toks, string(r))
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(rs) || rs[i+1] != r {
+				return nil, fmt.Errorf("single %q, want %s", r, string(r)+string(r))
+			}
+			toks = append(toks, string(r)+string(r))
+			i += 2
+		case r == '<' || r == '>' || r == '=' || r == '!':
+			if i+1 < len(rs) && rs[i+1] == '=' {
+				toks = append(toks, string(r)+"=")
+				i += 2
+			} else if r == '=' {
+				return nil, fmt.Errorf("single =, want ==")
+			} else {
+				toks = append(toks, string(r))
+				i++
+			}
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return toks, nil
+}
+
+type exprParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: "||", x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: "&&", x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if p.peek() == "!" {
+		p.pos++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", x: x}, nil
+	}
+	return p.parseCmp()
+}
+
+func (p *exprParser) parseCmp() (exprNode, error) {
+	x, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "<", "<=", ">", ">=", "==", "!=":
+		p.pos++
+		y, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op, x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	x, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "+" || p.peek() == "-" {
+		op := p.peek()
+		p.pos++
+		y, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "*" || p.peek() == "/" {
+		op := p.peek()
+		p.pos++
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek() == "-" {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	case unicode.IsDigit(rune(tok[0])) || tok[0] == '.':
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", tok)
+		}
+		p.pos++
+		return numNode(f), nil
+	case unicode.IsLetter(rune(tok[0])) || tok[0] == '_':
+		if !ruleVars[tok] {
+			return nil, fmt.Errorf("unknown variable %q", tok)
+		}
+		p.pos++
+		return varNode(tok), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+}
@@ -0,0 +1,100 @@
+package autoscaler
+
+import "testing"
+
+func TestParseRuleEval(t *testing.T) {
+	r, err := ParseRule("cpu > 70 || (mem > 80 && replicas < 5)")
+	if err != nil {
+		t.Fatalf("ParseRule: %v", err)
+	}
+	cases := []struct {
+		cpu, mem, replicas float64
+		want               bool
+	}{
+		{80, 10, 2, true},  // cpu branch
+		{50, 90, 3, true},  // mem branch under the replica cap
+		{50, 90, 5, false}, // mem branch blocked at 5 replicas
+		{50, 50, 2, false}, // nothing high
+		{70, 50, 2, false}, // strictly greater, not >=
+	}
+	for _, c := range cases {
+		got, err := r.Eval(map[string]float64{"cpu": c.cpu, "mem": c.mem, "replicas": c.replicas})
+		if err != nil {
+			t.Fatalf("Eval(cpu=%g mem=%g replicas=%g): %v", c.cpu, c.mem, c.replicas, err)
+		}
+		if got != c.want {
+			t.Errorf("Eval(cpu=%g mem=%g replicas=%g) = %v, want %v", c.cpu, c.mem, c.replicas, got, c.want)
+		}
+	}
+}
+
+func TestParseRuleArithmeticAndNot(t *testing.T) {
+	r, err := ParseRule("!(cpu/replicas >= 40) && mem*2 < 100")
+	if err != nil {
+		t.Fatalf("ParseRule: %v", err)
+	}
+	got, err := r.Eval(map[string]float64{"cpu": 60, "mem": 30, "replicas": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("60/2 is under 40 and 30*2 under 100, want true")
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",                      // empty
+		"cpu >",                 // dangling operator
+		"cpu > 70 &&",           // dangling conjunction
+		"(cpu > 70",             // unbalanced paren
+		"cpu + 1",               // number, not a condition
+		"load > 70",             // unknown variable
+		"cpu = 70",              // single =
+		"cpu > 70 | mem",        // single |
+		"cpu > 70; rm -rf",      // no statements
+		"cpu + (mem > 5) > 1",   // boolean fed to arithmetic
+		"(cpu > 70) > 1",        // boolean fed to a comparison
+		"cpu && mem",            // numbers fed to a conjunction
+		"-(cpu > 70) == 1",      // boolean negated arithmetically
+		"!(cpu + 1) || mem > 5", // number fed to !
+	} {
+		if _, err := ParseRule(expr); err == nil {
+			t.Errorf("ParseRule(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestParseRuleProbeSafe(t *testing.T) {
+	// Type checking must be structural: evaluating with placeholder
+	// values would reject this valid rule with "division by zero".
+	r, err := ParseRule("cpu/(replicas-1) > 5")
+	if err != nil {
+		t.Fatalf("ParseRule: %v", err)
+	}
+	got, err := r.Eval(map[string]float64{"cpu": 60, "replicas": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got {
+		t.Error("60/2 is over 5, want true")
+	}
+	// The arithmetic error still surfaces at eval time.
+	if _, err := r.Eval(map[string]float64{"cpu": 60, "replicas": 1}); err == nil {
+		t.Error("expected a division-by-zero error at eval time")
+	}
+}
+
+func TestRuleMissingVariable(t *testing.T) {
+	r, err := ParseRule("metric > 100")
+	if err != nil {
+		t.Fatalf("ParseRule: %v", err)
+	}
+	got, err := r.Eval(map[string]float64{"cpu": 50})
+	if err == nil {
+		t.Error("expected error for missing metric variable")
+	}
+	if got {
+		t.Error("failed eval must report false")
+	}
+}
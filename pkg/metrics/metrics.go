@@ -0,0 +1,147 @@
+// Package metrics provides the scalar metric sources the go-scale
+// command scales on — a shelled-out command, a JSON HTTP endpoint — and
+// the parsing helpers behind them. Anything implementing Source can
+// drive a scaling decision, so embedders can bring their own signals.
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source produces a scalar signal for the decision loop beyond the
+// built-in CPU/memory stats — queue depth, job backlog, whatever a
+// command can print.
+type Source interface {
+	// Name identifies the source in logs and heartbeats.
+	Name() string
+	Collect(ctx context.Context) (float64, error)
+}
+
+// Exec runs a user command and parses a number from its stdout. It is
+// the escape hatch for custom signals: any script that can print a
+// value can drive scaling.
+type Exec struct {
+	command string
+	timeout time.Duration
+}
+
+func NewExec(command string) *Exec {
+	return &Exec{command: command, timeout: 10 * time.Second}
+}
+
+func (m *Exec) Name() string { return "cmd" }
+
+func (m *Exec) Collect(ctx context.Context) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	out, err := shellCommand(ctx, m.command).Output()
+	if err != nil {
+		return 0, fmt.Errorf("metric command: %w", err)
+	}
+	v, err := ParseOutput(string(out))
+	if err != nil {
+		return 0, fmt.Errorf("metric command output: %w", err)
+	}
+	return v, nil
+}
+
+// HTTP scrapes a value from a JSON HTTP endpoint, so scaling can follow
+// an application-exposed gauge (active sessions, queue depth) instead
+// of CPU/mem only.
+type HTTP struct {
+	url    string
+	path   string // dotted path into the JSON document, e.g. "stats.queue.depth"
+	client *http.Client
+}
+
+func NewHTTP(url, path string) *HTTP {
+	return &HTTP{
+		url:    url,
+		path:   path,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (m *HTTP) Name() string { return "http" }
+
+func (m *HTTP) Collect(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("metric endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("metric endpoint: %s", resp.Status)
+	}
+	var doc any
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("metric endpoint: %w", err)
+	}
+	v, err := LookupJSONPath(doc, m.path)
+	if err != nil {
+		return 0, fmt.Errorf("metric endpoint: %w", err)
+	}
+	return v, nil
+}
+
+// LookupJSONPath walks a decoded JSON document along a dotted path of
+// object keys and array indexes and returns the number found there.
+// Numeric strings are accepted, since lots of APIs quote their gauges.
+func LookupJSONPath(doc any, path string) (float64, error) {
+	cur := doc
+	if path != "" {
+		for _, part := range strings.Split(path, ".") {
+			switch node := cur.(type) {
+			case map[string]any:
+				next, ok := node[part]
+				if !ok {
+					return 0, fmt.Errorf("path element %q not found", part)
+				}
+				cur = next
+			case []any:
+				i, err := strconv.Atoi(part)
+				if err != nil || i < 0 || i >= len(node) {
+					return 0, fmt.Errorf("bad array index %q", part)
+				}
+				cur = node[i]
+			default:
+				return 0, fmt.Errorf("path element %q: not an object or array", part)
+			}
+		}
+	}
+	switch v := cur.(type) {
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value %q is not numeric", v)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("value at path is %T, not a number", cur)
+	}
+}
+
+// ParseOutput extracts the first numeric token from command output,
+// tolerating surrounding whitespace, labels and units so both `42` and
+// `queue_depth: 42 messages` work.
+func ParseOutput(out string) (float64, error) {
+	for _, field := range strings.Fields(out) {
+		field = strings.Trim(field, ",;:")
+		if v, err := strconv.ParseFloat(field, 64); err == nil {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("no numeric value in %q", strings.TrimSpace(out))
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"42\n", 42, false},
+		{"  3.5  ", 3.5, false},
+		{"queue_depth: 17 messages", 17, false},
+		{"-2", -2, false},
+		{"", 0, true},
+		{"not a number", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOutput(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOutput(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseOutput(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	doc := map[string]any{
+		"stats": map[string]any{
+			"queue": map[string]any{"depth": 17.0},
+			"rates": []any{1.0, 2.5},
+		},
+		"quoted": "3.5",
+	}
+	tests := []struct {
+		path    string
+		want    float64
+		wantErr bool
+	}{
+		{"stats.queue.depth", 17, false},
+		{"stats.rates.1", 2.5, false},
+		{"quoted", 3.5, false},
+		{"stats.missing", 0, true},
+		{"stats.queue", 0, true}, // object, not a number
+		{"stats.rates.9", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := LookupJSONPath(doc, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("LookupJSONPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("LookupJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPMetric(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"sessions":{"active":42}}`)
+	}))
+	defer srv.Close()
+
+	m := NewHTTP(srv.URL, "sessions.active")
+	v, err := m.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if v != 42 {
+		t.Errorf("Collect = %v, want 42", v)
+	}
+}
+
+func TestHTTPMetricBadStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	m := NewHTTP(srv.URL, "x")
+	if _, err := m.Collect(context.Background()); err == nil {
+		t.Error("expected error for 500 response")
+	}
+}
+
+func TestExecMetric(t *testing.T) {
+	m := NewExec("echo 12.5")
+	v, err := m.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if v != 12.5 {
+		t.Errorf("Collect = %v, want 12.5", v)
+	}
+}
+
+func TestExecMetricFailure(t *testing.T) {
+	m := NewExec("exit 3")
+	if _, err := m.Collect(context.Background()); err == nil {
+		t.Error("expected error from failing command")
+	}
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Factory builds a Source from its one-string spec. What the spec means
+// is the provider's business: a shell command for cmd, a URL for http.
+type Factory func(spec string) (Source, error)
+
+var (
+	regMu    sync.Mutex
+	registry = map[string]Factory{}
+)
+
+// Register makes a provider available to New under the given name.
+// Like database/sql drivers, providers register from an init function,
+// and registering the same name twice panics: it is a programming
+// error, not a runtime condition.
+func Register(name string, f Factory) {
+	regMu.Lock()
+	defer regMu.Unlock()
+	if name == "" || f == nil {
+		panic("metrics: Register with empty name or nil factory")
+	}
+	if _, dup := registry[name]; dup {
+		panic("metrics: Register called twice for " + name)
+	}
+	registry[name] = f
+}
+
+// Names lists the registered providers, sorted, for help output.
+func Names() []string {
+	regMu.Lock()
+	defer regMu.Unlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds a source by provider name. A name nobody registered falls
+// back to an executable plugin: goscale-metric-<name> on PATH, run with
+// the spec as its argument and its stdout parsed like a cmd source, so
+// a new signal can be a small script instead of a rebuild.
+func New(name, spec string) (Source, error) {
+	regMu.Lock()
+	f, ok := registry[name]
+	regMu.Unlock()
+	if ok {
+		src, err := f(spec)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		return src, nil
+	}
+	path, err := exec.LookPath("goscale-metric-" + name)
+	if err != nil {
+		return nil, fmt.Errorf("no metric provider %q and no goscale-metric-%s plugin on PATH (have: %s)",
+			name, name, strings.Join(Names(), ", "))
+	}
+	command := path
+	if spec != "" {
+		command += " " + spec
+	}
+	return named{Source: NewExec(command), name: name}, nil
+}
+
+// named relabels a wrapped source so plugin samples log under the
+// provider's name instead of "cmd".
+type named struct {
+	Source
+	name string
+}
+
+func (n named) Name() string { return n.name }
+
+func init() {
+	Register("cmd", func(spec string) (Source, error) {
+		if spec == "" {
+			return nil, fmt.Errorf("needs a command as its spec")
+		}
+		return NewExec(spec), nil
+	})
+	Register("http", func(spec string) (Source, error) {
+		url, path, _ := strings.Cut(spec, "#")
+		if url == "" {
+			return nil, fmt.Errorf("needs a URL (with an optional #dotted.path) as its spec")
+		}
+		return NewHTTP(url, path), nil
+	})
+}
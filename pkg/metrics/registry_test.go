@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewBuiltins(t *testing.T) {
+	src, err := New("cmd", "echo 5")
+	if err != nil {
+		t.Fatalf("New(cmd): %v", err)
+	}
+	if v, err := src.Collect(context.Background()); err != nil || v != 5 {
+		t.Errorf("cmd Collect = %v, %v, want 5", v, err)
+	}
+
+	src, err = New("http", "http://localhost/stats#queue.depth")
+	if err != nil {
+		t.Fatalf("New(http): %v", err)
+	}
+	if src.Name() != "http" {
+		t.Errorf("http Name = %q", src.Name())
+	}
+
+	if _, err := New("cmd", ""); err == nil {
+		t.Error("cmd without a spec succeeded")
+	}
+	if _, err := New("http", "#path.only"); err == nil {
+		t.Error("http without a URL succeeded")
+	}
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	if _, err := New("nope", ""); err == nil {
+		t.Error("unknown provider without a plugin succeeded")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate Register did not panic")
+		}
+	}()
+	Register("cmd", func(string) (Source, error) { return nil, nil })
+}
+
+func TestExecPlugin(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "goscale-metric-depth")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"depth: $1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	src, err := New("depth", "7")
+	if err != nil {
+		t.Fatalf("New(depth): %v", err)
+	}
+	if src.Name() != "depth" {
+		t.Errorf("plugin Name = %q, want depth", src.Name())
+	}
+	v, err := src.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("plugin Collect: %v", err)
+	}
+	if v != 7 {
+		t.Errorf("plugin Collect = %v, want 7", v)
+	}
+}
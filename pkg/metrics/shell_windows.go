@@ -0,0 +1,14 @@
+//go:build windows
+
+package metrics
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand wraps a metric command in the platform shell, so pipes
+// and one-liners work without the caller splitting arguments.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/c", command)
+}
@@ -0,0 +1,117 @@
+// Package scaler holds the backends that actually change replica
+// counts: the docker compose CLI and the Nomad HTTP API. The decision
+// logic lives elsewhere; a backend only needs to count replicas and set
+// a new count.
+package scaler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compose scales services by shelling out to the compose CLI, which
+// keeps us compatible with whatever compose setup (plugin or
+// standalone) is on the host.
+type Compose struct {
+	Project    string
+	Files      []string // -f compose files, in order
+	ProjectDir string   // --project-directory, when the files live elsewhere
+	Profiles   []string // --profile values, for profile-gated services
+	Timeout    time.Duration
+	Retries    int
+	DryRun     bool
+}
+
+// Args builds the common compose invocation prefix, threading -f,
+// --project-directory and --profile so the compose file doesn't have to
+// live in the caller's working directory and profile-gated services
+// stay visible.
+func (c Compose) Args() []string {
+	args := []string{"compose", "-p", c.Project}
+	for _, f := range c.Files {
+		args = append(args, "-f", f)
+	}
+	if c.ProjectDir != "" {
+		args = append(args, "--project-directory", c.ProjectDir)
+	}
+	for _, p := range c.Profiles {
+		args = append(args, "--profile", p)
+	}
+	return args
+}
+
+// ScaleTo sets the replica count for one compose service. Each attempt
+// runs under Timeout so a hung compose can't block the caller forever,
+// and transient failures are retried with doubling backoff up to
+// Retries times.
+func (c Compose) ScaleTo(service string, replicas int) error {
+	args := append(c.Args(),
+		"up", "-d", "--no-recreate",
+		"--scale", fmt.Sprintf("%s=%d", service, replicas),
+		service,
+	)
+	if c.DryRun {
+		bin, argv := ComposeCommand(args)
+		log.Printf("[dry-run] would run: %s %s", bin, strings.Join(argv, " "))
+		return nil
+	}
+	delay := time.Second
+	for attempt := 0; ; attempt++ {
+		err := runCompose(c.Timeout, args)
+		if err == nil || attempt >= c.Retries {
+			return err
+		}
+		log.Printf("✗ compose scale attempt %d/%d: %v (retrying in %s)", attempt+1, c.Retries+1, err, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// ComposeCommand resolves how to invoke compose for an argument list
+// that begins with "compose": the docker CLI plugin where the docker
+// binary has it, the standalone docker-compose binary (which is
+// docker-compose.exe on Windows) otherwise. Detection runs once per
+// process; hosts with neither fall through to the plugin form so the
+// real invocation surfaces the error.
+func ComposeCommand(args []string) (bin string, argv []string) {
+	bin, plugin := composeCLI()
+	if plugin {
+		return bin, args
+	}
+	return bin, args[1:]
+}
+
+var composeCLI = sync.OnceValues(func() (string, bool) {
+	if exec.Command("docker", "compose", "version", "--short").Run() == nil {
+		return "docker", true
+	}
+	if path, err := exec.LookPath("docker-compose"); err == nil {
+		return path, false
+	}
+	return "docker", true
+})
+
+// runCompose runs one compose invocation under the given timeout; zero
+// means no limit.
+func runCompose(timeout time.Duration, args []string) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	bin, argv := ComposeCommand(args)
+	out, err := exec.CommandContext(ctx, bin, argv...).CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("docker compose scale timed out after %s", timeout)
+		}
+		return fmt.Errorf("docker compose scale: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
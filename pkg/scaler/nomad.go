@@ -0,0 +1,153 @@
+package scaler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Nomad is a minimal client for the Nomad HTTP API: enough to count a
+// task group's replicas, sample allocation usage and set a new count
+// through the job scale endpoint.
+type Nomad struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// NewNomad resolves the usual Nomad environment defaults: NOMAD_ADDR
+// and NOMAD_TOKEN, then the local agent.
+func NewNomad(addr, token, namespace string) *Nomad {
+	if addr == "" {
+		addr = os.Getenv("NOMAD_ADDR")
+	}
+	if addr == "" {
+		addr = "http://127.0.0.1:4646"
+	}
+	if token == "" {
+		token = os.Getenv("NOMAD_TOKEN")
+	}
+	return &Nomad{
+		addr:      addr,
+		token:     token,
+		namespace: namespace,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Addr reports the resolved agent address, for startup logs.
+func (c *Nomad) Addr() string { return c.addr }
+
+// do issues one API call and decodes the JSON response into out.
+func (c *Nomad) do(ctx context.Context, method, path string, payload, out any) error {
+	u := c.addr + path
+	if c.namespace != "" {
+		u += "?namespace=" + url.QueryEscape(c.namespace)
+	}
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("X-Nomad-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("nomad: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("nomad: %s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GroupCount returns one task group's desired count and its total task
+// memory in MiB, the denominator for memory percentages.
+func (c *Nomad) GroupCount(ctx context.Context, job, group string) (count, memoryMiB int, err error) {
+	var doc struct {
+		TaskGroups []struct {
+			Name  string
+			Count int
+			Tasks []struct {
+				Resources struct{ MemoryMB int }
+			}
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(job), nil, &doc); err != nil {
+		return 0, 0, err
+	}
+	for _, g := range doc.TaskGroups {
+		if g.Name != group {
+			continue
+		}
+		for _, t := range g.Tasks {
+			memoryMiB += t.Resources.MemoryMB
+		}
+		return g.Count, memoryMiB, nil
+	}
+	return 0, 0, fmt.Errorf("nomad: job %q has no task group %q", job, group)
+}
+
+// RunningAllocs lists the running allocation IDs of one task group.
+func (c *Nomad) RunningAllocs(ctx context.Context, job, group string) ([]string, error) {
+	var allocs []struct {
+		ID           string
+		TaskGroup    string
+		ClientStatus string
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(job)+"/allocations", nil, &allocs); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, a := range allocs {
+		if a.TaskGroup == group && a.ClientStatus == "running" {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids, nil
+}
+
+// AllocUsage reads one allocation's resource usage: CPU percent and
+// memory bytes.
+func (c *Nomad) AllocUsage(ctx context.Context, id string) (cpuPct float64, memBytes uint64, err error) {
+	var doc struct {
+		ResourceUsage struct {
+			CpuStats    struct{ Percent float64 }
+			MemoryStats struct{ Usage uint64 }
+		}
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/client/allocation/"+url.PathEscape(id)+"/stats", nil, &doc); err != nil {
+		return 0, 0, err
+	}
+	return doc.ResourceUsage.CpuStats.Percent, doc.ResourceUsage.MemoryStats.Usage, nil
+}
+
+// ScaleGroup sets the group's count via the job scale endpoint.
+func (c *Nomad) ScaleGroup(ctx context.Context, job, group string, count int, reason string) error {
+	payload := map[string]any{
+		"Count":  count,
+		"Target": map[string]string{"Group": group},
+		"Reason": reason,
+	}
+	return c.do(ctx, http.MethodPost, "/v1/job/"+url.PathEscape(job)+"/scale", payload, nil)
+}
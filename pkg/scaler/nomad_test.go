@@ -0,0 +1,111 @@
+package scaler
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func nomadTestServer(t *testing.T, scaled *map[string]any) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/job/edge", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Nomad-Token"); got != "secret" {
+			t.Errorf("token header = %q", got)
+		}
+		if got := r.URL.Query().Get("namespace"); got != "prod" {
+			t.Errorf("namespace = %q", got)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"TaskGroups": []map[string]any{
+				{"Name": "web", "Count": 3, "Tasks": []map[string]any{
+					{"Resources": map[string]any{"MemoryMB": 256}},
+					{"Resources": map[string]any{"MemoryMB": 256}},
+				}},
+			},
+		})
+	})
+	mux.HandleFunc("/v1/job/edge/allocations", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"ID": "a1", "TaskGroup": "web", "ClientStatus": "running"},
+			{"ID": "a2", "TaskGroup": "web", "ClientStatus": "complete"},
+			{"ID": "a3", "TaskGroup": "worker", "ClientStatus": "running"},
+		})
+	})
+	mux.HandleFunc("/v1/client/allocation/a1/stats", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"ResourceUsage": map[string]any{
+				"CpuStats":    map[string]any{"Percent": 42.5},
+				"MemoryStats": map[string]any{"Usage": 268435456},
+			},
+		})
+	})
+	mux.HandleFunc("/v1/job/edge/scale", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("scale method = %s", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(scaled)
+		w.WriteHeader(http.StatusOK)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestNomadClient(t *testing.T) {
+	var scaled map[string]any
+	srv := nomadTestServer(t, &scaled)
+	defer srv.Close()
+	c := NewNomad(srv.URL, "secret", "prod")
+	ctx := context.Background()
+
+	count, memMiB, err := c.GroupCount(ctx, "edge", "web")
+	if err != nil {
+		t.Fatalf("GroupCount: %v", err)
+	}
+	if count != 3 || memMiB != 512 {
+		t.Errorf("GroupCount = %d, %d MiB, want 3, 512", count, memMiB)
+	}
+	if _, _, err := c.GroupCount(ctx, "edge", "missing"); err == nil {
+		t.Error("GroupCount for a missing group succeeded")
+	}
+
+	allocs, err := c.RunningAllocs(ctx, "edge", "web")
+	if err != nil {
+		t.Fatalf("RunningAllocs: %v", err)
+	}
+	if len(allocs) != 1 || allocs[0] != "a1" {
+		t.Errorf("RunningAllocs = %v, want [a1]", allocs)
+	}
+
+	cpu, mem, err := c.AllocUsage(ctx, "a1")
+	if err != nil {
+		t.Fatalf("AllocUsage: %v", err)
+	}
+	if math.Abs(cpu-42.5) > 0.01 || mem != 268435456 {
+		t.Errorf("AllocUsage = %.1f%%, %d bytes", cpu, mem)
+	}
+
+	if err := c.ScaleGroup(ctx, "edge", "web", 5, "go-scale event abcd"); err != nil {
+		t.Fatalf("ScaleGroup: %v", err)
+	}
+	if scaled["Count"] != float64(5) {
+		t.Errorf("scale payload Count = %v, want 5", scaled["Count"])
+	}
+	target, _ := scaled["Target"].(map[string]any)
+	if target["Group"] != "web" {
+		t.Errorf("scale payload Target = %v", scaled["Target"])
+	}
+}
+
+func TestNomadClientError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "ACL token not found", http.StatusForbidden)
+	}))
+	defer srv.Close()
+	c := NewNomad(srv.URL, "", "")
+	if _, _, err := c.GroupCount(context.Background(), "edge", "web"); err == nil {
+		t.Error("expected error for 403 response")
+	}
+}
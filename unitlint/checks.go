@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// issue is one lint finding against a unit file.
+type issue struct {
+	Unit     string `json:"unit"`
+	Check    string `json:"check"`
+	Severity string `json:"severity"` // "warn" or "info"
+	Detail   string `json:"detail"`
+}
+
+// hardeningDirectives are the sandboxing settings a long-running service
+// should usually set; the same set internal/sdinstall writes into the
+// units this repo installs.
+var hardeningDirectives = []string{
+	"NoNewPrivileges",
+	"ProtectSystem",
+	"ProtectHome",
+	"PrivateTmp",
+}
+
+// deprecatedDirectives maps retired settings to their replacements.
+var deprecatedDirectives = map[string]string{
+	"MemoryLimit":          "MemoryMax",
+	"CPUShares":            "CPUWeight",
+	"StartupCPUShares":     "StartupCPUWeight",
+	"BlockIOWeight":        "IOWeight",
+	"BlockIOAccounting":    "IOAccounting",
+	"PermissionsStartOnly": "ExecStart=+… prefixes",
+	"OnFailureIsolate":     "OnFailureJobMode",
+}
+
+// dependencyKeys are the hard dependencies worth verifying; Wants= is
+// advisory by design so a missing optional unit is not an error.
+var dependencyKeys = []string{"Requires", "Requisite", "BindsTo", "PartOf"}
+
+// lintUnit runs every check against one unit. known holds the names of
+// all scanned units, for dependency resolution.
+func lintUnit(u *unitFile, known map[string]bool) []issue {
+	var out []issue
+	add := func(check, severity, format string, args ...any) {
+		out = append(out, issue{
+			Unit:     u.Name,
+			Check:    check,
+			Severity: severity,
+			Detail:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if u.kind() == "service" {
+		if !u.has("Service", "Restart") && u.get("Service", "Type") != "oneshot" {
+			add("no-restart", "warn", "long-running service without a Restart= policy")
+		}
+		var missing []string
+		for _, d := range hardeningDirectives {
+			if !u.has("Service", d) {
+				missing = append(missing, d)
+			}
+		}
+		if len(missing) > 0 && len(missing) < len(hardeningDirectives) {
+			// Partially hardened units get a nudge; fully bare ones are
+			// too common (distro defaults) to flag loudly.
+			add("hardening", "warn", "missing %s", strings.Join(missing, ", "))
+		} else if len(missing) == len(hardeningDirectives) {
+			add("hardening", "info", "no sandboxing directives set")
+		}
+	}
+
+	for section, keys := range u.sections {
+		for key := range keys {
+			if repl, ok := deprecatedDirectives[key]; ok {
+				add("deprecated", "warn", "[%s] %s= is deprecated, use %s", section, key, repl)
+			}
+		}
+		for _, key := range []string{"StandardOutput", "StandardError"} {
+			if strings.HasPrefix(u.get(section, key), "syslog") {
+				add("deprecated", "warn", "[%s] %s=syslog is deprecated, use journal", section, key)
+			}
+		}
+	}
+
+	for _, key := range dependencyKeys {
+		for _, value := range u.sections["Unit"][key] {
+			for _, dep := range strings.Fields(value) {
+				// Template instances and specifiers resolve at runtime;
+				// we can't verify them from the files alone.
+				if strings.ContainsAny(dep, "@%") {
+					continue
+				}
+				if !known[dep] {
+					add("broken-dependency", "warn", "%s= references missing unit %s", key, dep)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// lintAll runs lintUnit over every scanned unit.
+func lintAll(units []*unitFile) []issue {
+	known := make(map[string]bool, len(units))
+	for _, u := range units {
+		known[u.Name] = true
+	}
+	var out []issue
+	for _, u := range units {
+		out = append(out, lintUnit(u, known)...)
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, name, body string) *unitFile {
+	t.Helper()
+	return parseUnitFile(name, "/etc/systemd/system/"+name, []byte(body))
+}
+
+func TestParseUnitFile(t *testing.T) {
+	u := parse(t, "demo.service", `
+# a comment
+; another
+[Unit]
+Description=Demo service
+Requires=a.service b.socket
+
+[Service]
+Type=simple
+ExecStartPre=/bin/true
+ExecStartPre=/bin/false
+ExecStart=/usr/bin/demo \
+  --flag value
+Restart = on-failure
+`)
+	if got := u.get("Unit", "Description"); got != "Demo service" {
+		t.Errorf("Description = %q", got)
+	}
+	if got := u.get("Service", "Restart"); got != "on-failure" {
+		t.Errorf("Restart = %q (spaces around = not trimmed?)", got)
+	}
+	if got := len(u.sections["Service"]["ExecStartPre"]); got != 2 {
+		t.Errorf("ExecStartPre count = %d, want 2", got)
+	}
+	if got := u.get("Service", "ExecStart"); !strings.Contains(got, "--flag value") {
+		t.Errorf("continuation not joined: %q", got)
+	}
+	if u.kind() != "service" {
+		t.Errorf("kind = %q", u.kind())
+	}
+}
+
+func checksOf(issues []issue) []string {
+	var out []string
+	for _, is := range issues {
+		out = append(out, is.Check)
+	}
+	return out
+}
+
+func TestLintUnit(t *testing.T) {
+	known := map[string]bool{"present.service": true}
+
+	t.Run("missing restart and bare sandbox", func(t *testing.T) {
+		u := parse(t, "demo.service", "[Service]\nExecStart=/bin/demo\n")
+		got := checksOf(lintUnit(u, known))
+		want := []string{"no-restart", "hardening"}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("checks = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("oneshot needs no restart", func(t *testing.T) {
+		u := parse(t, "job.service", "[Service]\nType=oneshot\nExecStart=/bin/job\n")
+		for _, is := range lintUnit(u, known) {
+			if is.Check == "no-restart" {
+				t.Errorf("oneshot flagged for no-restart")
+			}
+		}
+	})
+
+	t.Run("partial hardening warns", func(t *testing.T) {
+		u := parse(t, "demo.service", "[Service]\nRestart=always\nNoNewPrivileges=yes\nPrivateTmp=yes\n")
+		issues := lintUnit(u, known)
+		if len(issues) != 1 || issues[0].Check != "hardening" || issues[0].Severity != "warn" {
+			t.Fatalf("issues = %v", issues)
+		}
+		if !strings.Contains(issues[0].Detail, "ProtectSystem") || !strings.Contains(issues[0].Detail, "ProtectHome") {
+			t.Errorf("detail %q misses the absent directives", issues[0].Detail)
+		}
+	})
+
+	t.Run("deprecated directives", func(t *testing.T) {
+		u := parse(t, "demo.service", "[Service]\nRestart=always\nMemoryLimit=1G\nStandardOutput=syslog\nNoNewPrivileges=yes\nProtectSystem=strict\nProtectHome=yes\nPrivateTmp=yes\n")
+		var deprecated int
+		for _, is := range lintUnit(u, known) {
+			if is.Check == "deprecated" {
+				deprecated++
+			}
+		}
+		if deprecated != 2 {
+			t.Errorf("deprecated findings = %d, want 2 (MemoryLimit, StandardOutput)", deprecated)
+		}
+	})
+
+	t.Run("broken dependency", func(t *testing.T) {
+		u := parse(t, "demo.timer", "[Unit]\nRequires=present.service gone.service\nWants=also-gone.service\n")
+		issues := lintUnit(u, known)
+		if len(issues) != 1 || issues[0].Check != "broken-dependency" {
+			t.Fatalf("issues = %v, want one broken-dependency", issues)
+		}
+		if !strings.Contains(issues[0].Detail, "gone.service") {
+			t.Errorf("detail = %q", issues[0].Detail)
+		}
+	})
+
+	t.Run("templates are skipped", func(t *testing.T) {
+		u := parse(t, "demo.service", "[Unit]\nRequires=getty@tty1.service thing-%i.service\n[Service]\nRestart=always\nNoNewPrivileges=yes\nProtectSystem=strict\nProtectHome=yes\nPrivateTmp=yes\n")
+		for _, is := range lintUnit(u, known) {
+			if is.Check == "broken-dependency" {
+				t.Errorf("template dependency flagged: %v", is)
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// unitlint scans installed systemd unit files and reports hardening
+// gaps, missing restart policies, deprecated directives and broken
+// dependencies. Results print as a table or JSON, or open in a small
+// results browser modeled on centurion.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/iamdanielv/utils/internal/errcode"
+	"golang.org/x/term"
+)
+
+func main() {
+	log.SetPrefix("unitlint: ")
+	log.SetFlags(0)
+
+	userMode := flag.Bool("user", false, "lint the user manager's units instead of the system ones")
+	jsonOut := flag.Bool("json", false, "emit findings as JSON instead of a table")
+	tui := flag.Bool("tui", false, "browse findings interactively")
+	flag.Parse()
+
+	units, err := scanUnits(searchPaths(*userMode))
+	if err != nil {
+		errcode.Fatal(errcode.Wrap(errcode.Unavailable, err))
+	}
+	if len(units) == 0 {
+		errcode.Fatal(errcode.New(errcode.Unavailable, "no unit files found"))
+	}
+	issues := lintAll(units)
+
+	switch {
+	case *tui:
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Fprintln(os.Stderr, "unitlint: -tui needs an interactive terminal")
+			os.Exit(1)
+		}
+		if err := browse(units, issues); err != nil {
+			errcode.Fatal(err)
+		}
+	case *jsonOut:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(issues); err != nil {
+			errcode.Fatal(err)
+		}
+	default:
+		printIssues(os.Stdout, len(units), issues)
+	}
+
+	if hasWarnings(issues) && !*tui {
+		os.Exit(int(errcode.Partial))
+	}
+}
+
+func hasWarnings(issues []issue) bool {
+	for _, is := range issues {
+		if is.Severity == "warn" {
+			return true
+		}
+	}
+	return false
+}
+
+func printIssues(w *os.File, scanned int, issues []issue) {
+	if len(issues) == 0 {
+		fmt.Fprintf(w, "scanned %d units, no findings\n", scanned)
+		return
+	}
+	tw := tabwriter.NewWriter(w, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "UNIT\tSEVERITY\tCHECK\tDETAIL")
+	for _, is := range issues {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", is.Unit, is.Severity, is.Check, is.Detail)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nscanned %d units, %d findings\n", scanned, len(issues))
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iamdanielv/utils/internal/format"
+	"golang.org/x/term"
+)
+
+// ANSI palette and screen controls, matching centurion.
+const (
+	cReset  = "\x1b[0m"
+	cBold   = "\x1b[1m"
+	cRed    = "\x1b[31m"
+	cYellow = "\x1b[33m"
+	cBlue   = "\x1b[34m"
+	cGray   = "\x1b[30;1m"
+	cRev    = "\x1b[7m"
+
+	altScreen  = "\x1b[?1049h"
+	mainScreen = "\x1b[?1049l"
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+	clearAll   = "\x1b[2J\x1b[H"
+)
+
+// browser is the findings list state, a cut-down version of centurion's
+// unit list: j/k selection with a detail pane for the selected finding.
+type browser struct {
+	scanned int
+	issues  []issue
+
+	sel    int
+	offset int
+
+	width, height int
+}
+
+// browse opens the interactive results view.
+func browse(units []*unitFile, issues []issue) error {
+	fd := int(os.Stdin.Fd())
+	old, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("raw mode: %w", err)
+	}
+	defer term.Restore(fd, old)
+
+	out := os.Stdout
+	fmt.Fprint(out, altScreen+hideCursor)
+	defer fmt.Fprint(out, showCursor+mainScreen)
+
+	b := &browser{scanned: len(units), issues: issues}
+	buf := make([]byte, 16)
+	for {
+		b.width, b.height, _ = term.GetSize(fd)
+		if b.width <= 0 {
+			b.width, b.height = 80, 24
+		}
+		b.draw(out)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil || n == 0 {
+			return nil
+		}
+		if b.handleKey(string(buf[:n])) {
+			return nil
+		}
+	}
+}
+
+func (b *browser) handleKey(k string) bool {
+	switch {
+	case k == "q" || k == "\x03":
+		return true
+	case k == "k" || k == "\x1b[A":
+		if b.sel > 0 {
+			b.sel--
+		}
+	case k == "j" || k == "\x1b[B":
+		if b.sel < len(b.issues)-1 {
+			b.sel++
+		}
+	case k == "g":
+		b.sel = 0
+	case k == "G":
+		b.sel = max(0, len(b.issues)-1)
+	}
+	return false
+}
+
+func (b *browser) draw(out *os.File) {
+	var s strings.Builder
+	s.WriteString(clearAll)
+
+	title := fmt.Sprintf(" unitlint — %d findings in %d units", len(b.issues), b.scanned)
+	fmt.Fprintf(&s, "%s%s%-*s%s\r\n", cBold, cBlue, b.width, title, cReset)
+
+	rows := max(1, b.height-3)
+	if b.sel < b.offset {
+		b.offset = b.sel
+	}
+	if b.sel >= b.offset+rows {
+		b.offset = b.sel - rows + 1
+	}
+	for i := b.offset; i < len(b.issues) && i < b.offset+rows; i++ {
+		is := b.issues[i]
+		sev := cYellow + "warn" + cReset
+		if is.Severity == "info" {
+			sev = cGray + "info" + cReset
+		}
+		line := fmt.Sprintf(" %s %-36s %-18s %s", sev, format.Truncate(is.Unit, 36), is.Check, format.Truncate(is.Detail, max(0, b.width-64)))
+		if i == b.sel {
+			fmt.Fprintf(&s, "%s%-*s%s\r\n", cRev, b.width, format.Truncate(line, b.width), cReset)
+		} else {
+			fmt.Fprintf(&s, "%s\r\n", format.Truncate(line, b.width))
+		}
+	}
+
+	detail := ""
+	if b.sel < len(b.issues) {
+		detail = b.issues[b.sel].Unit + ": " + b.issues[b.sel].Detail
+	}
+	fmt.Fprintf(&s, " %s\r\n", format.Truncate(detail, max(0, b.width-1)))
+	fmt.Fprintf(&s, "%s j/k move  g/G ends  q quit%s", cGray, cReset)
+	out.WriteString(s.String())
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
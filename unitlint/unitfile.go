@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// unitFile is one parsed systemd unit.
+type unitFile struct {
+	Name string
+	Path string
+	// sections maps section name -> key -> values in file order; keys
+	// like ExecStartPre may repeat.
+	sections map[string]map[string][]string
+}
+
+// get returns the last value of a key, matching systemd's
+// last-assignment-wins semantics for non-list settings.
+func (u *unitFile) get(section, key string) string {
+	vals := u.sections[section][key]
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[len(vals)-1]
+}
+
+func (u *unitFile) has(section, key string) bool {
+	return len(u.sections[section][key]) > 0
+}
+
+// kind is the unit type taken from the file extension.
+func (u *unitFile) kind() string {
+	return strings.TrimPrefix(filepath.Ext(u.Name), ".")
+}
+
+// parseUnitFile parses the systemd unit file syntax: INI-style sections,
+// # and ; comments, and trailing-backslash line continuations.
+func parseUnitFile(name, path string, data []byte) *unitFile {
+	u := &unitFile{Name: name, Path: path, sections: map[string]map[string][]string{}}
+	section := ""
+	var cont string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = cont + strings.TrimSpace(line)
+		cont = ""
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasSuffix(line, "\\") {
+			cont = strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section == "" {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if u.sections[section] == nil {
+			u.sections[section] = map[string][]string{}
+		}
+		u.sections[section][key] = append(u.sections[section][key], strings.TrimSpace(value))
+	}
+	return u
+}
+
+// unitSuffixes are the file extensions we treat as unit files.
+var unitSuffixes = []string{
+	".service", ".socket", ".timer", ".target", ".mount", ".automount",
+	".path", ".slice", ".swap",
+}
+
+func isUnitName(name string) bool {
+	for _, suffix := range unitSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchPaths returns the unit directories in shadowing order, highest
+// priority first.
+func searchPaths(user bool) []string {
+	if user {
+		home, _ := os.UserHomeDir()
+		return []string{
+			filepath.Join(home, ".config/systemd/user"),
+			"/etc/systemd/user",
+			"/usr/local/lib/systemd/user",
+			"/usr/lib/systemd/user",
+		}
+	}
+	return []string{
+		"/etc/systemd/system",
+		"/run/systemd/system",
+		"/usr/local/lib/systemd/system",
+		"/usr/lib/systemd/system",
+		"/lib/systemd/system",
+	}
+}
+
+// scanUnits loads every unit file under dirs. Earlier directories shadow
+// later ones, the way systemd resolves the same unit name twice.
+func scanUnits(dirs []string) ([]*unitFile, error) {
+	byName := map[string]*unitFile{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue // search paths commonly don't all exist
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !isUnitName(name) {
+				continue
+			}
+			if _, shadowed := byName[name]; shadowed {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			byName[name] = parseUnitFile(name, path, data)
+		}
+	}
+	units := make([]*unitFile, 0, len(byName))
+	for _, u := range byName {
+		units = append(units, u)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Name < units[j].Name })
+	return units, nil
+}